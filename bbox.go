@@ -1,11 +1,13 @@
 package geo
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 // WithBBox returns a geometry that contains a "bbox" property.
-// TODO: it might make sense to calculate the bounding box from the geometry's coordinates.
-// TODO: validate the size of the bounding box relative to the dimensionality of the geometry.
-// TODO: verify that all axes of the most southwesterly point are followed by all axes of the more northeasterly point (should also be done for geometries)
+// See WithComputedBBox to calculate the bounding box from the geometry's coordinates.
+// The bounding box must be valid, see NewEnvelope, otherwise MarshalJSON returns an error.
 func WithBBox(bbox []float64, geom Geometry) Geometry {
 	return &boundingBox{
 		Geometry: geom,
@@ -13,11 +15,32 @@ func WithBBox(bbox []float64, geom Geometry) Geometry {
 	}
 }
 
+// WithComputedBBox returns a geometry whose GeoJSON contains a "bbox" property
+// that is computed from the geometry's coordinates every time it is marshalled.
+// The bounding box has Z if the geometry does, see Bounds,
+// and it is left out if the geometry is empty.
+func WithComputedBBox(geom Geometry) Geometry {
+	return &boundingBox{
+		Geometry: geom,
+		computed: true,
+	}
+}
+
 // boundingBox is a utility type for decorating geometries with a bounding box.
 type boundingBox struct {
 	Geometry
 
 	Box []float64 `json:"bbox"`
+
+	computed bool
+}
+
+// box returns the bounding box, computing it if necessary.
+func (bbox *boundingBox) box() []float64 {
+	if bbox.computed {
+		return bbox.Geometry.Bounds().BBox()
+	}
+	return bbox.Box
 }
 
 // Equal compares two geometries that have bounding boxes.
@@ -26,7 +49,7 @@ func (bbox *boundingBox) Equal(g Geometry) bool {
 	if !ok {
 		return false
 	}
-	if !boxEqual(bbox.Box, other.Box) {
+	if !boxEqual(bbox.box(), other.box()) {
 		return false
 	}
 	return bbox.Geometry.Equal(other.Geometry)
@@ -38,11 +61,46 @@ func (bbox *boundingBox) MarshalJSON() ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	bboxData, _ := json.Marshal(bbox.Box) // Never fails.
+	box := bbox.box()
+	if box == nil && bbox.computed {
+		return gdata, nil
+	}
+	if _, err := NewEnvelope(box); err != nil {
+		return nil, err
+	}
+	bboxData, _ := json.Marshal(box) // Never fails.
 	bboxData = append([]byte(`,"bbox":`), append(bboxData, '}')...)
 	return append(gdata[:len(gdata)-1], bboxData...), nil
 }
 
+// unmarshalBBox unmarshals a GeoJSON "bbox" member.
+// A missing or null bbox returns nil.
+func unmarshalBBox(data json.RawMessage) ([]float64, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var box []float64
+	if err := json.Unmarshal(data, &box); err != nil {
+		return nil, fmt.Errorf("bbox must be an array of numbers, got %s", data)
+	}
+	if _, err := NewEnvelope(box); err != nil {
+		return nil, err
+	}
+	return box, nil
+}
+
+// withUnmarshalledBBox decorates a geometry with its GeoJSON "bbox" member, if it has one.
+func withUnmarshalledBBox(data json.RawMessage, geom Geometry) (Geometry, error) {
+	box, err := unmarshalBBox(data)
+	if err != nil {
+		return nil, err
+	}
+	if box == nil {
+		return geom, nil
+	}
+	return WithBBox(box, geom), nil
+}
+
 // boxEqual compares two float64 slices.
 func boxEqual(b1, b2 []float64) bool {
 	if len(b1) != len(b2) {
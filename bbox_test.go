@@ -44,8 +44,8 @@ func TestBBoxMarshal(t *testing.T) {
 		Expected []byte
 	}{
 		{
-			Input:    WithBBox([]float64{1, 2}, &Point{1, 2}),
-			Expected: []byte(`{"type":"Point","coordinates":[1,2],"bbox":[1,2]}`),
+			Input:    WithBBox([]float64{1, 2, 1, 2}, &Point{1, 2}),
+			Expected: []byte(`{"type":"Point","coordinates":[1,2],"bbox":[1,2,1,2]}`),
 		},
 	} {
 		got, err := json.Marshal(testcase.Input)
@@ -66,6 +66,16 @@ func TestBBoxMarshal(t *testing.T) {
 			t.Fatalf("(case %d) expected error, got nil", i)
 		}
 	}
+	for i, box := range [][]float64{
+		{},
+		{1, 2},
+		{0, 3, 1, 1},
+		{0, 0, 5, 1, 1, 4},
+	} {
+		if _, err := json.Marshal(WithBBox(box, &Point{1, 2})); err == nil {
+			t.Fatalf("(case %d) expected error, got nil", i)
+		}
+	}
 }
 
 func TestBBoxUnmarshal(t *testing.T) {
@@ -76,25 +86,25 @@ func TestBBoxUnmarshal(t *testing.T) {
 	}{
 		// Geometry
 		{
-			Input:    []byte(`{"type":"Point","coordinates":[1,2],"bbox":[1,2]}`),
-			Expected: WithBBox([]float64{1, 2}, &Point{1, 2}),
+			Input:    []byte(`{"type":"Point","coordinates":[1,2],"bbox":[1,2,1,2]}`),
+			Expected: WithBBox([]float64{1, 2, 1, 2}, &Point{1, 2}),
 		},
 		// Feature
 		{
-			Input:    []byte(`{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"bbox":[1,2]}`),
-			Expected: WithBBox([]float64{1, 2}, &Feature{Geometry: &Point{1, 2}}),
+			Input:    []byte(`{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"bbox":[1,2,1,2]}`),
+			Expected: WithBBox([]float64{1, 2, 1, 2}, &Feature{Geometry: &Point{1, 2}}),
 		},
 		// FeatureCollection
 		{
-			Input: []byte(`{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]}}],"bbox":[1,2]}`),
-			Expected: WithBBox([]float64{1, 2}, &FeatureCollection{
+			Input: []byte(`{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]}}],"bbox":[1,2,1,2]}`),
+			Expected: WithBBox([]float64{1, 2, 1, 2}, &FeatureCollection{
 				&Feature{Geometry: &Point{1, 2}},
 			}),
 		},
 		// GeometryCollection
 		{
-			Input:    []byte(`{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[1,2]}],"bbox":[1,2]}`),
-			Expected: WithBBox([]float64{1, 2}, &GeometryCollection{&Point{1, 2}}),
+			Input:    []byte(`{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[1,2]}],"bbox":[1,2,1,2]}`),
+			Expected: WithBBox([]float64{1, 2, 1, 2}, &GeometryCollection{&Point{1, 2}}),
 		},
 	} {
 		geom, err := UnmarshalJSON(testcase.Input)
@@ -105,4 +115,21 @@ func TestBBoxUnmarshal(t *testing.T) {
 			t.Fatalf("(case %d) expected %#v to equal %#v", i, got, expected)
 		}
 	}
+
+	// Fail
+	for i, input := range []string{
+		`{"type":"Point","coordinates":[1,2],"bbox":[1,2]}`,
+		`{"type":"Point","coordinates":[1,2],"bbox":[]}`,
+		`{"type":"Point","coordinates":[1,2],"bbox":"1,2,1,2"}`,
+		`{"type":"Point","coordinates":[1,2],"bbox":[0,3,1,1]}`,
+		`{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"bbox":[1,2,3]}`,
+		`{"type":"FeatureCollection","features":[],"bbox":{"west":1}}`,
+		`{"type":"FeatureCollection","features":[{"type":"Feature","geometry":null,"bbox":[1]}]}`,
+		`{"type":"GeometryCollection","geometries":[],"bbox":[0,0,1,1,1,1,1]}`,
+		`{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[1,2],"bbox":[0,0,9,1,1,8]}]}`,
+	} {
+		if _, err := UnmarshalJSON([]byte(input)); err == nil {
+			t.Fatalf("(case %d) expected error, got nil", i)
+		}
+	}
 }
@@ -6,13 +6,11 @@ import (
 	"fmt"
 	"math"
 	"strconv"
-	"strings"
 )
 
 const (
 	earthRadiusMeters = 6371e3 // earth's radius in meters
 	feetToMeters      = 0.3048 // convert feet to meters
-	circleWKTPrefix   = `CIRCULARSTRING`
 )
 
 // Contains methods.
@@ -58,58 +56,93 @@ func (c Circle) Equal(g Geometry) bool {
 //     * "slc"
 // then this method panics.
 func (c Circle) Contains(p Point) bool {
+	return c.distance(p) < c.radiusMeters()
+}
+
+// Locate returns the location of the point relative to the circle.
+// Like Contains, this uses CircleContainsMethod to measure the distance
+// from the center, and it panics if the method is not recognized.
+// Points exactly one radius from the center are on the boundary.
+func (c Circle) Locate(p Point) Location {
+	if c.IsEmpty() || p.IsEmpty() {
+		return Exterior
+	}
+	switch d, r := c.distance(p), c.radiusMeters(); {
+	case d < r:
+		return Interior
+	case d == r:
+		return Boundary
+	}
+	return Exterior
+}
+
+// distance returns the distance in meters from the center of the circle
+// to the point, calculated with CircleContainsMethod.
+func (c Circle) distance(p Point) float64 {
 	switch CircleContainsMethod {
 	case ContainsMethodHaversine:
-		return c.ContainsHaversine(p)
+		return c.distanceHaversine(p)
 	case ContainsMethodSphericalCosines:
-		return c.ContainsSLC(p)
+		return c.distanceSLC(p)
 	case ContainsMethodEquirectangular:
-		return c.ContainsEquirectangular(p)
+		return c.distanceEquirectangular(p)
 	default:
 		panic("Unrecognized CircleContainsMethod: " + CircleContainsMethod)
 	}
 }
 
+// radiusMeters returns the radius of the circle in meters.
+func (c Circle) radiusMeters() float64 {
+	return feetToMeters * c.Radius
+}
+
 // ContainsHaversine uses the haversine formula to determine if the
 // point is contained in the circle.
 func (c Circle) ContainsHaversine(p Point) bool {
-	var (
-		lat1 = toRadians(c.Coordinates[1])
-		lat2 = toRadians(p[1])
-		dLat = toRadians(p[1] - c.Coordinates[1])
-		dLng = toRadians(p[0] - c.Coordinates[0])
-		a    = (math.Sin(dLat/2) * math.Sin(dLat/2)) +
-			(math.Cos(lat1) * math.Cos(lat2) * math.Sin(dLng/2) * math.Sin(dLng/2))
-		d = earthRadiusMeters * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
-	)
-	return d < (feetToMeters * c.Radius)
+	return c.distanceHaversine(p) < c.radiusMeters()
+}
+
+// distanceHaversine uses the haversine formula to calculate the distance
+// in meters from the center of the circle to the point.
+func (c Circle) distanceHaversine(p Point) float64 {
+	return earthRadiusMeters * angularDistance(c.Coordinates, p)
 }
 
 // ContainsSLC uses the spherical law of cosines to determine if
 // the point is contained in the circle.
 func (c Circle) ContainsSLC(p Point) bool {
+	return c.distanceSLC(p) < c.radiusMeters()
+}
+
+// distanceSLC uses the spherical law of cosines to calculate the distance
+// in meters from the center of the circle to the point.
+func (c Circle) distanceSLC(p Point) float64 {
 	var (
 		lat1 = toRadians(c.Coordinates[1])
 		lat2 = toRadians(p[1])
 		dLng = toRadians(p[0] - c.Coordinates[0])
 		a    = (math.Sin(lat1) * math.Sin(lat2)) +
 			(math.Cos(lat1) * math.Cos(lat2) * math.Cos(dLng))
-		d = earthRadiusMeters * math.Acos(a)
 	)
-	return d < (feetToMeters * c.Radius)
+	return earthRadiusMeters * math.Acos(a)
 }
 
 // ContainsEquirectangular uses equirectangular projection to
 // determine if the point is contained in the circle.
 func (c Circle) ContainsEquirectangular(p Point) bool {
+	return c.distanceEquirectangular(p) < c.radiusMeters()
+}
+
+// distanceEquirectangular uses equirectangular projection to calculate
+// the distance in meters from the center of the circle to the point.
+func (c Circle) distanceEquirectangular(p Point) float64 {
 	var (
 		dLng = toRadians(p[0] - c.Coordinates[0])
 		mLat = toRadians(p[1]+c.Coordinates[1]) / float64(2)
 		y    = toRadians(p[1] - c.Coordinates[1])
 		x    = dLng * math.Cos(mLat)
-		d    = earthRadiusMeters * math.Sqrt((x*x)+(y*y))
 	)
-	return d < (feetToMeters * c.Radius)
+	return earthRadiusMeters * math.Sqrt((x*x)+(y*y))
 }
 
 // toRadians converts from degrees to radians.
@@ -117,6 +150,48 @@ func toRadians(degrees float64) float64 {
 	return (math.Pi * degrees) / 180
 }
 
+// IsEmpty returns true if the circle's center is an empty point.
+func (c Circle) IsEmpty() bool {
+	return c.Coordinates.IsEmpty()
+}
+
+// Bounds returns the envelope of the circle.
+// Like Contains, this assumes the radius is specified in feet
+// and the center is a longitude and latitude, so the envelope is in degrees.
+// Circles that reach a pole span every longitude, and the envelope of
+// a circle that crosses the antimeridian does too, with its west edge
+// greater than its east edge, see CrossesAntimeridian.
+func (c Circle) Bounds() Envelope {
+	if c.IsEmpty() {
+		return emptyEnvelope()
+	}
+	var (
+		r     = c.radiusMeters() / earthRadiusMeters
+		lng   = c.Coordinates[0]
+		lat   = c.Coordinates[1]
+		south = lat - toDegrees(r)
+		north = lat + toDegrees(r)
+	)
+	if south <= -90 || north >= 90 {
+		return Envelope{
+			Min: Point{-180, math.Max(south, -90)},
+			Max: Point{180, math.Min(north, 90)},
+		}
+	}
+	var (
+		dLng = toDegrees(math.Asin(math.Sin(r) / math.Cos(toRadians(lat))))
+		west = angNormalize(lng - dLng)
+		east = angNormalize(lng + dLng)
+	)
+	if west == 180 {
+		west = -180
+	}
+	return Envelope{
+		Min: Point{west, south},
+		Max: Point{east, north},
+	}
+}
+
 // MarshalJSON marshals a circle to GeoJSON.
 // See https://github.com/geojson/geojson-spec/wiki/Proposal---Circles-and-Ellipses-Geoms
 func (c Circle) MarshalJSON() ([]byte, error) {
@@ -132,40 +207,25 @@ func (c *Circle) Scan(src interface{}) error {
 	return scan(c, src)
 }
 
-// Scan scans a circle from well known text.
+// scan scans a circle from well known text.
 func (c *Circle) scan(s string) error {
-	idx := strings.Index(s, circleWKTPrefix)
-	if idx != 0 {
-		return fmt.Errorf("malformed circle: %s", s)
-	}
-	points, err := pointsScan(s[len(circleWKTPrefix):])
+	g, _, err := parseWKT(s, circleWKTTag)
 	if err != nil {
 		return err
 	}
-	if len(points) < 3 {
-		return fmt.Errorf("malformed circle: %s", s)
-	}
-	// points 0 and 2 should be on opposite sides of the circle,
-	// so we can calculate the radius as 1/2 the distance between them
-	// and the center as the midpoint.
-	c.Radius = Point(points[0]).DistanceFrom(points[2]) / 2
-	var (
-		dx = points[2][0] - points[0][0]
-		dy = points[2][1] - points[0][1]
-	)
-	c.Coordinates = Point{points[0][0] + (dx / 2), points[0][1] + (dy / 2)}
+	*c = *withoutLayout(g).(*Circle)
 	return nil
 }
 
 // String returns a string representation of the circle.
 func (c Circle) String() string {
-	return "CIRCULARSTRING" + pointsString([][3]float64{
+	return circleWKTTag + pointsString([][4]float64{
 		{c.Coordinates[0] + c.Radius, c.Coordinates[1]},
 		{c.Coordinates[0], c.Coordinates[1] + c.Radius},
 		{c.Coordinates[0] - c.Radius, c.Coordinates[1]},
 		{c.Coordinates[0], c.Coordinates[1] - c.Radius},
 		{c.Coordinates[0] + c.Radius, c.Coordinates[1]},
-	})
+	}, XY)
 }
 
 // UnmarshalJSON unmarshals the circle from GeoJSON.
@@ -179,7 +239,7 @@ func (c *Circle) UnmarshalJSON(data []byte) error {
 		return fmt.Errorf("expected %s for type, got %s", expected, got)
 	}
 
-	coords := [3]float64{}
+	coords := [4]float64{}
 	if err := json.Unmarshal(g.Coordinates, &coords); err != nil {
 		return err
 	}
@@ -0,0 +1,176 @@
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Reducer combines the values of a property of the features that are dissolved
+// into one, e.g. by adding up their populations.
+// It is given the value of the property for each feature in the order of
+// the collection, with nil for features that do not have the property.
+type Reducer func(values []interface{}) interface{}
+
+// Dissolve merges the features that have the same value of the property key
+// into a single feature, e.g. counties into states.
+// The geometry of each merged feature is the union of their geometries,
+// see Union, so shared edges are removed and only polygons are kept.
+// Its properties are key, with the value that the features have in common,
+// and the result of each reducer for the property with the same name.
+// Features that do not have the property are merged with those where it is null.
+//
+// The merged features are in the order in which their values of the property
+// first appear in the collection. Properties that are not JSON objects are
+// converted with encoding/json, and an error is returned if they cannot be,
+// or if the geometries of any of the features cannot be merged, see Union.
+func (coll FeatureCollection) Dissolve(key string, reducers map[string]Reducer) (FeatureCollection, error) {
+	type group struct {
+		value      interface{}
+		geoms      []Geometry
+		properties []map[string]interface{}
+	}
+	var (
+		groups  []*group
+		byValue = map[string]*group{}
+	)
+	for i, feat := range coll {
+		props, err := featureProperties(feat)
+		if err != nil {
+			return nil, fmt.Errorf("feature %d: %v", i, err)
+		}
+		value := props[key]
+		id, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("feature %d: could not compare property %q: %v", i, key, err)
+		}
+		g, ok := byValue[string(id)]
+		if !ok {
+			g = &group{value: value}
+			byValue[string(id)] = g
+			groups = append(groups, g)
+		}
+		if feat != nil && feat.Geometry != nil {
+			g.geoms = append(g.geoms, feat.Geometry)
+		}
+		g.properties = append(g.properties, props)
+	}
+
+	dissolved := make(FeatureCollection, len(groups))
+	for i, g := range groups {
+		props := map[string]interface{}{key: g.value}
+		for name, reduce := range reducers {
+			values := make([]interface{}, len(g.properties))
+			for j, p := range g.properties {
+				values[j] = p[name]
+			}
+			props[name] = reduce(values)
+		}
+		union, err := unionAll(g.geoms)
+		if err != nil {
+			return nil, fmt.Errorf("could not merge the features where %q is %v: %v", key, g.value, err)
+		}
+		dissolved[i] = &Feature{Geometry: &union, Properties: props}
+	}
+	return dissolved, nil
+}
+
+// featureProperties returns the properties of a feature as a JSON object.
+func featureProperties(feat *Feature) (map[string]interface{}, error) {
+	if feat == nil || feat.Properties == nil {
+		return map[string]interface{}{}, nil
+	}
+	if props, ok := feat.Properties.(map[string]interface{}); ok {
+		return props, nil
+	}
+	data, err := json.Marshal(feat.Properties)
+	if err != nil {
+		return nil, err
+	}
+	var props map[string]interface{}
+	if err := json.Unmarshal(data, &props); err != nil {
+		return nil, fmt.Errorf("properties must be a JSON object, got %s", data)
+	}
+	if props == nil {
+		props = map[string]interface{}{}
+	}
+	return props, nil
+}
+
+// unionAll returns the union of any number of geometries.
+//
+// Overlaying everything at once would compare each edge with every polygon,
+// so the geometries are sorted from west to east and unioned in pairs,
+// then the results of those in pairs, and so on. Neighbours are merged early,
+// which keeps the number of polygons in each overlay small.
+func unionAll(geoms []Geometry) (MultiPolygon, error) {
+	type part struct {
+		polygons MultiPolygon
+		center   float64
+	}
+	parts := make([]part, len(geoms))
+	for i, g := range geoms {
+		mp := MultiPolygon(polygonalParts(g).polygons)
+		env := mp.Bounds()
+		parts[i] = part{polygons: mp, center: (env.Min[0] + env.Max[0]) / 2}
+	}
+	sort.SliceStable(parts, func(i, j int) bool {
+		return parts[i].center < parts[j].center
+	})
+	sorted := make([]MultiPolygon, len(parts))
+	for i, p := range parts {
+		sorted[i] = p.polygons
+	}
+	return unionRange(sorted)
+}
+
+// unionRange unions the geometries of a slice by halves.
+// Like Union, it returns a valid result along with any error.
+func unionRange(parts []MultiPolygon) (MultiPolygon, error) {
+	switch len(parts) {
+	case 0:
+		return MultiPolygon{}, nil
+	case 1:
+		return Union(&parts[0], &MultiPolygon{})
+	}
+	half := len(parts) / 2
+	a, errA := unionRange(parts[:half])
+	b, errB := unionRange(parts[half:])
+	union, err := unionNearby(a, b)
+	for _, e := range []error{errA, errB} {
+		if err == nil {
+			err = e
+		}
+	}
+	return union, err
+}
+
+// unionNearby returns the union of two results of overlays, only overlaying
+// the polygons of each that are within the bounds of the other.
+// The others cannot meet anything, so they are kept as they are.
+func unionNearby(a, b MultiPolygon) (MultiPolygon, error) {
+	var (
+		envA, envB   = a.Bounds(), b.Bounds()
+		nearA, nearB MultiPolygon
+		result       = MultiPolygon{}
+	)
+	for _, poly := range a {
+		if Polygon(poly).Bounds().Intersects(envB) {
+			nearA = append(nearA, poly)
+		} else {
+			result = append(result, poly)
+		}
+	}
+	for _, poly := range b {
+		if Polygon(poly).Bounds().Intersects(envA) {
+			nearB = append(nearB, poly)
+		} else {
+			result = append(result, poly)
+		}
+	}
+	if len(nearA) == 0 || len(nearB) == 0 {
+		return append(append(result, nearA...), nearB...), nil
+	}
+	union, err := Union(&nearA, &nearB)
+	return append(result, union...), err
+}
@@ -0,0 +1,141 @@
+package geo
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+// squareFeature returns a feature with a unit square at x, y.
+func squareFeature(x, y float64, props interface{}) *Feature {
+	return &Feature{
+		Geometry:   &Polygon{{{x, y}, {x + 1, y}, {x + 1, y + 1}, {x, y + 1}, {x, y}}},
+		Properties: props,
+	}
+}
+
+func sumReducer(values []interface{}) interface{} {
+	sum := 0.0
+	for _, v := range values {
+		if f, ok := v.(float64); ok {
+			sum += f
+		}
+	}
+	return sum
+}
+
+func TestDissolve(t *testing.T) {
+	coll := FeatureCollection{
+		squareFeature(0, 0, map[string]interface{}{"state": "CO", "pop": 1.0}),
+		squareFeature(5, 0, map[string]interface{}{"state": "UT", "pop": 2.0}),
+		squareFeature(1, 0, map[string]interface{}{"state": "CO", "pop": 3.0}),
+		squareFeature(6, 0, map[string]interface{}{"state": "UT"}),
+		squareFeature(0, 1, map[string]interface{}{"state": "CO", "pop": 5.0}),
+		{Geometry: &Point{10, 10}, Properties: map[string]interface{}{"state": "UT", "pop": 4.0}},
+	}
+	dissolved, err := coll.Dissolve("state", map[string]Reducer{
+		"pop": sumReducer,
+		"count": func(values []interface{}) interface{} {
+			return len(values)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range []struct {
+		State  string
+		Pop    float64
+		Count  int
+		Area   float64
+		Points int
+	}{
+		{State: "CO", Pop: 9, Count: 3, Area: 3, Points: 7},
+		{State: "UT", Pop: 6, Count: 3, Area: 2, Points: 5},
+	} {
+		feat := dissolved[i]
+		props := feat.Properties.(map[string]interface{})
+		if props["state"] != expected.State || props["pop"] != expected.Pop || props["count"] != expected.Count {
+			t.Fatalf("(feature %d) expected properties %v, got %v", i, expected, props)
+		}
+		mp := feat.Geometry.(*MultiPolygon)
+		if len(*mp) != 1 || len((*mp)[0]) != 1 {
+			t.Fatalf("(feature %d) expected a polygon without holes, got %s", i, mp)
+		}
+		if got := len((*mp)[0][0]); got != expected.Points {
+			t.Fatalf("(feature %d) expected shared edges to be removed, got %s", i, mp)
+		}
+		if got := Area(mp); math.Abs(got-expected.Area) > 1e-9 {
+			t.Fatalf("(feature %d) expected area %f, got %f", i, expected.Area, got)
+		}
+	}
+	if len(dissolved) != 2 {
+		t.Fatalf("expected 2 features, got %d", len(dissolved))
+	}
+}
+
+func TestDissolveHole(t *testing.T) {
+	var coll FeatureCollection
+	for x := 0.0; x < 3; x++ {
+		for y := 0.0; y < 3; y++ {
+			if x != 1 || y != 1 {
+				coll = append(coll, squareFeature(x, y, nil))
+			}
+		}
+	}
+	dissolved, err := coll.Dissolve("county", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dissolved) != 1 {
+		t.Fatalf("expected 1 feature, got %d", len(dissolved))
+	}
+	mp := dissolved[0].Geometry.(*MultiPolygon)
+	if errs := Validate(mp); errs != nil {
+		t.Fatal(errs)
+	}
+	if len(*mp) != 1 || len((*mp)[0]) != 2 || Area(mp) != 8 {
+		t.Fatalf("expected a polygon with a hole and area 8, got %s", mp)
+	}
+}
+
+func TestDissolveProperties(t *testing.T) {
+	type county struct {
+		State string `json:"state"`
+	}
+	coll := FeatureCollection{
+		squareFeature(0, 0, county{State: "CO"}),
+		squareFeature(1, 0, nil),
+		squareFeature(2, 0, map[string]interface{}{"state": nil}),
+		squareFeature(3, 0, map[string]interface{}{"state": json.Number("8")}),
+		squareFeature(4, 0, map[string]interface{}{"state": 8.0}),
+		squareFeature(5, 0, &county{State: "CO"}),
+		{Properties: map[string]interface{}{"state": "WY"}},
+	}
+	dissolved, err := coll.Dissolve("state", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range []struct {
+		State    interface{}
+		Polygons int
+	}{
+		{State: "CO", Polygons: 2},
+		{State: nil, Polygons: 1},
+		{State: json.Number("8"), Polygons: 1},
+		{State: "WY", Polygons: 0},
+	} {
+		if got := dissolved[i].Properties.(map[string]interface{})["state"]; got != expected.State {
+			t.Fatalf("(feature %d) expected state %v, got %v", i, expected.State, got)
+		}
+		if got := len(*dissolved[i].Geometry.(*MultiPolygon)); got != expected.Polygons {
+			t.Fatalf("(feature %d) expected %d polygons, got %d", i, expected.Polygons, got)
+		}
+	}
+	if len(dissolved) != 4 {
+		t.Fatalf("expected 4 features, got %d", len(dissolved))
+	}
+
+	if _, err := (FeatureCollection{squareFeature(0, 0, "CO")}).Dissolve("state", nil); err == nil {
+		t.Fatal("expected an error for properties that are not an object")
+	}
+}
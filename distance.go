@@ -0,0 +1,476 @@
+package geo
+
+import "math"
+
+// circleVertices is the number of vertices of the polygon that stands in for
+// a Circle when measuring distances.
+const circleVertices = 64
+
+// Distance returns the shortest Cartesian distance between two geometries,
+// in the units of their coordinates.
+// The distance is zero if the geometries intersect, including when one is inside
+// a polygon of the other, and NaN if either of them is empty.
+// A Circle is treated as a polygon with 64 vertices on its circumference.
+func Distance(a, b Geometry) float64 {
+	_, _, d := closestPoints(a, b, planarMetric{})
+	return d
+}
+
+// ClosestPoints returns the point of a and the point of b that are closest to each other
+// in the plane, see Distance.
+// If the geometries intersect both points are the same point where they meet,
+// and if either geometry is empty both points are empty.
+func ClosestPoints(a, b Geometry) (Point, Point) {
+	pa, pb, _ := closestPoints(a, b, planarMetric{})
+	return pa, pb
+}
+
+// Distance returns the shortest geodesic distance between two geometries.
+// The distance is zero if the geometries intersect, and NaN if either of them is empty.
+// Whether two edges cross is decided on a sphere, and whether a point is inside
+// a polygon is decided in the plane of longitude and latitude,
+// which only matters for points very close to an edge.
+// A Circle is treated as a polygon with 64 vertices on its circumference.
+// Every part of a is measured against every part of b, so the time it takes
+// grows with the product of their numbers of vertices.
+func (g *Geodesic) Distance(a, b Geometry) float64 {
+	_, _, d := closestPoints(a, b, geodesicMetric{g: g})
+	return d
+}
+
+// ClosestPoints returns the point of a and the point of b that are closest to each other
+// on the ellipsoid, see Geodesic.Distance.
+func (g *Geodesic) ClosestPoints(a, b Geometry) (Point, Point) {
+	pa, pb, _ := closestPoints(a, b, geodesicMetric{g: g})
+	return pa, pb
+}
+
+// distanceMetric measures the distances between the parts of geometries.
+type distanceMetric interface {
+	// points returns the distance between two points.
+	points(a, b [4]float64) float64
+
+	// segment returns the point on the segment ab that is closest to p,
+	// and its distance from p.
+	segment(p, a, b [4]float64) ([4]float64, float64)
+
+	// crossing returns a point where the segments ab and cd meet, if they do.
+	crossing(a, b, c, d [4]float64) ([4]float64, bool)
+
+	// gap returns a distance that parts within the two envelopes cannot be closer than.
+	gap(a, b Envelope) float64
+}
+
+// distanceParts is a geometry broken into the parts that distances are measured between.
+type distanceParts struct {
+	points   [][4]float64
+	segments [][2][4]float64
+	polygons [][][][4]float64
+}
+
+// newDistanceParts breaks a geometry into points, segments and polygons.
+func newDistanceParts(g Geometry) distanceParts {
+	var parts distanceParts
+	visitMeasurable(g, func(g Geometry) {
+		switch v := g.(type) {
+		case *Point:
+			parts.addPoint(*v)
+		case *MultiPoint:
+			for _, p := range *v {
+				parts.addPoint(p)
+			}
+		case *Line:
+			parts.addLine(*v, false)
+		case *MultiLine:
+			for _, line := range *v {
+				parts.addLine(line, false)
+			}
+		case *Polygon:
+			parts.addPolygon(*v)
+		case *MultiPolygon:
+			for _, poly := range *v {
+				parts.addPolygon(poly)
+			}
+		case *Circle:
+			if !v.IsEmpty() {
+				parts.addPolygon([][][4]float64{circleRing(*v)})
+			}
+		}
+	})
+	return parts
+}
+
+// addPoint adds a point, unless it is empty.
+func (parts *distanceParts) addPoint(p Point) {
+	if !p.IsEmpty() {
+		parts.points = append(parts.points, p)
+	}
+}
+
+// addLine adds the segments of a line, closing it first if it is a ring.
+// A line with a single point is added as a point.
+func (parts *distanceParts) addLine(line [][4]float64, ring bool) {
+	if len(line) == 1 {
+		parts.addPoint(line[0])
+		return
+	}
+	for i := 1; i < len(line); i++ {
+		parts.segments = append(parts.segments, [2][4]float64{line[i-1], line[i]})
+	}
+	if n := len(line); ring && n > 1 && (line[0][0] != line[n-1][0] || line[0][1] != line[n-1][1]) {
+		parts.segments = append(parts.segments, [2][4]float64{line[n-1], line[0]})
+	}
+}
+
+// addPolygon adds the rings of a polygon, and the polygon itself for its interior.
+func (parts *distanceParts) addPolygon(poly [][][4]float64) {
+	if len(poly) == 0 {
+		return
+	}
+	for _, ring := range poly {
+		parts.addLine(ring, true)
+	}
+	parts.polygons = append(parts.polygons, poly)
+}
+
+// isEmpty returns true if there is nothing to measure.
+func (parts distanceParts) isEmpty() bool {
+	return len(parts.points) == 0 && len(parts.segments) == 0
+}
+
+// vertices returns the points and the vertices of the segments.
+func (parts distanceParts) vertices() [][4]float64 {
+	vertices := append([][4]float64{}, parts.points...)
+	for _, s := range parts.segments {
+		vertices = append(vertices, s[0], s[1])
+	}
+	return vertices
+}
+
+// inside returns a vertex of parts that is inside a polygon of other, if there is one.
+func (parts distanceParts) inside(other distanceParts) ([4]float64, bool) {
+	if len(other.polygons) == 0 {
+		return [4]float64{}, false
+	}
+	for _, p := range parts.vertices() {
+		for _, poly := range other.polygons {
+			if Polygon(poly).Locate(p) != Exterior {
+				return p, true
+			}
+		}
+	}
+	return [4]float64{}, false
+}
+
+// closestPoints returns the closest points of two geometries and the distance between them.
+// Every part of a is compared with every part of b, but parts whose envelopes are
+// further apart than the closest pair found so far are skipped without measuring,
+// which in the plane leaves only the parts that are near each other.
+func closestPoints(a, b Geometry, m distanceMetric) (Point, Point, float64) {
+	pa, pb := newDistanceParts(a), newDistanceParts(b)
+	if pa.isEmpty() || pb.isEmpty() {
+		return emptyPoint(), emptyPoint(), math.NaN()
+	}
+	if p, ok := pa.inside(pb); ok {
+		return p, p, 0
+	}
+	if p, ok := pb.inside(pa); ok {
+		return p, p, 0
+	}
+	var (
+		pointsA, segmentsA = pa.bounds()
+		pointsB, segmentsB = pb.bounds()
+	)
+	for i, s := range pa.segments {
+		for j, t := range pb.segments {
+			if m.gap(segmentsA[i], segmentsB[j]) > 0 {
+				continue
+			}
+			if p, ok := m.crossing(s[0], s[1], t[0], t[1]); ok {
+				return p, p, 0
+			}
+		}
+	}
+
+	var (
+		bestA, bestB [4]float64
+		best         = math.Inf(1)
+	)
+	consider := func(p, q [4]float64, d float64) {
+		if d < best {
+			bestA, bestB, best = p, q, d
+		}
+	}
+	for i, p := range pa.points {
+		for j, q := range pb.points {
+			if m.gap(pointsA[i], pointsB[j]) < best {
+				consider(p, q, m.points(p, q))
+			}
+		}
+		for j, t := range pb.segments {
+			if m.gap(pointsA[i], segmentsB[j]) < best {
+				q, d := m.segment(p, t[0], t[1])
+				consider(p, q, d)
+			}
+		}
+	}
+	for i, s := range pa.segments {
+		for j, q := range pb.points {
+			if m.gap(segmentsA[i], pointsB[j]) < best {
+				p, d := m.segment(q, s[0], s[1])
+				consider(p, q, d)
+			}
+		}
+		// Segments that do not cross are closest at an end of one of them.
+		for j, t := range pb.segments {
+			if m.gap(segmentsA[i], segmentsB[j]) >= best {
+				continue
+			}
+			for _, p := range s {
+				q, d := m.segment(p, t[0], t[1])
+				consider(p, q, d)
+			}
+			for _, q := range t {
+				p, d := m.segment(q, s[0], s[1])
+				consider(p, q, d)
+			}
+		}
+	}
+	return bestA, bestB, best
+}
+
+// bounds returns the envelopes of the points and of the segments.
+func (parts distanceParts) bounds() (points, segments []Envelope) {
+	points = make([]Envelope, len(parts.points))
+	for i, p := range parts.points {
+		points[i] = emptyEnvelope().extend(p)
+	}
+	segments = make([]Envelope, len(parts.segments))
+	for i, s := range parts.segments {
+		segments[i] = emptyEnvelope().extend(s[0]).extend(s[1])
+	}
+	return points, segments
+}
+
+// circleRing returns a ring of points on the circumference of a circle.
+func circleRing(c Circle) [][4]float64 {
+	ring := make([][4]float64, circleVertices+1)
+	for i := 0; i < circleVertices; i++ {
+		ring[i] = c.Coordinates.Destination(c.radiusMeters(), float64(i)*360/circleVertices)
+	}
+	ring[circleVertices] = ring[0]
+	return ring
+}
+
+// planarMetric measures distances in the plane.
+type planarMetric struct{}
+
+// points returns the Cartesian distance between two points.
+func (planarMetric) points(a, b [4]float64) float64 {
+	return math.Hypot(b[0]-a[0], b[1]-a[1])
+}
+
+// segment returns the point on the segment ab that is closest to p in the plane.
+func (m planarMetric) segment(p, a, b [4]float64) ([4]float64, float64) {
+	var (
+		dx = b[0] - a[0]
+		dy = b[1] - a[1]
+		l2 = dx*dx + dy*dy
+		t  float64
+	)
+	if l2 > 0 {
+		t = ((p[0]-a[0])*dx + (p[1]-a[1])*dy) / l2
+	}
+	var q [4]float64
+	switch {
+	case t <= 0:
+		q = a
+	case t >= 1:
+		q = b
+	default:
+		q = lerp(a, b, t)
+	}
+	return q, m.points(p, q)
+}
+
+// crossing returns a point where the segments ab and cd meet in the plane.
+func (planarMetric) crossing(a, b, c, d [4]float64) ([4]float64, bool) {
+	if points := segmentIntersection(a, b, c, d); len(points) > 0 {
+		return points[0], true
+	}
+	return [4]float64{}, false
+}
+
+// gap returns the Cartesian distance between two envelopes.
+func (planarMetric) gap(a, b Envelope) float64 {
+	var dx, dy float64
+	switch {
+	case b.Min[0] > a.Max[0]:
+		dx = b.Min[0] - a.Max[0]
+	case a.Min[0] > b.Max[0]:
+		dx = a.Min[0] - b.Max[0]
+	}
+	switch {
+	case b.Min[1] > a.Max[1]:
+		dy = b.Min[1] - a.Max[1]
+	case a.Min[1] > b.Max[1]:
+		dy = a.Min[1] - b.Max[1]
+	}
+	return math.Hypot(dx, dy)
+}
+
+// geodesicMetric measures distances along geodesics.
+type geodesicMetric struct {
+	g *Geodesic
+}
+
+// points returns the geodesic distance between two points.
+func (m geodesicMetric) points(a, b [4]float64) float64 {
+	return m.g.pointDistance(a, b)
+}
+
+// geodesicSamples is the number of pieces that a geodesic is sampled in
+// to bracket the point closest to another point.
+const geodesicSamples = 16
+
+// segment returns the point on the geodesic from a to b that is closest to p.
+// The distance from p along a long geodesic may have more than one local minimum,
+// so the geodesic is sampled first, and the closest sample is refined with
+// a golden section search between its neighbours.
+func (m geodesicMetric) segment(p, a, b [4]float64) ([4]float64, float64) {
+	var (
+		best    = a
+		bestD   = m.points(p, a)
+		closest = 0 // the number of the closest sample, from a at 0 to b
+	)
+	if d := m.points(p, b); d < bestD {
+		best, bestD, closest = b, d, geodesicSamples
+	}
+	r := m.g.inverse(a[1], a[0], b[1], b[0], false)
+	if r.s12 == 0 {
+		return best, bestD
+	}
+	var (
+		line = newGeodesicLine(m.g, a[1], a[0], atan2d(r.salp1, r.calp1))
+		at   = func(s float64) ([4]float64, float64) {
+			lat, lon, _ := line.position(s)
+			q := lerp(a, b, s/r.s12)
+			q[0], q[1] = lon, lat
+			return q, m.points(p, q)
+		}
+		step = r.s12 / geodesicSamples
+	)
+	for i := 1; i < geodesicSamples; i++ {
+		if q, d := at(float64(i) * step); d < bestD {
+			best, bestD, closest = q, d, i
+		}
+	}
+	var (
+		ratio  = (math.Sqrt(5) - 1) / 2
+		lo     = math.Max(0, float64(closest-1)*step)
+		hi     = math.Min(r.s12, float64(closest+1)*step)
+		x1     = hi - ratio*(hi-lo)
+		x2     = lo + ratio*(hi-lo)
+		q1, f1 = at(x1)
+		q2, f2 = at(x2)
+	)
+	for i := 0; i < 200 && hi-lo > 1e-12*m.g.a; i++ {
+		if f1 < f2 {
+			hi = x2
+			x2, q2, f2 = x1, q1, f1
+			x1 = hi - ratio*(hi-lo)
+			q1, f1 = at(x1)
+		} else {
+			lo = x1
+			x1, q1, f1 = x2, q2, f2
+			x2 = lo + ratio*(hi-lo)
+			q2, f2 = at(x2)
+		}
+	}
+	if f1 < bestD {
+		best, bestD = q1, f1
+	}
+	if f2 < bestD {
+		best, bestD = q2, f2
+	}
+	return best, bestD
+}
+
+// gap returns zero, since a geodesic can pass outside the envelope of its ends,
+// e.g. towards a pole, so no parts can be skipped.
+func (geodesicMetric) gap(a, b Envelope) float64 {
+	return 0
+}
+
+// crossing returns a point where the segments ab and cd meet,
+// treating them as arcs of great circles.
+func (geodesicMetric) crossing(a, b, c, d [4]float64) ([4]float64, bool) {
+	for _, p := range [][4]float64{a, b} {
+		for _, q := range [][4]float64{c, d} {
+			if p[0] == q[0] && p[1] == q[1] {
+				return p, true
+			}
+		}
+	}
+	var (
+		va, vb = unitVector(a), unitVector(b)
+		vc, vd = unitVector(c), unitVector(d)
+		n1     = cross(va, vb)
+		n2     = cross(vc, vd)
+	)
+	if dot(n1, vc)*dot(n1, vd) > 0 || dot(n2, va)*dot(n2, vb) > 0 {
+		return [4]float64{}, false
+	}
+	// The great circles meet at x and at its antipode.
+	x := cross(n1, n2)
+	if x == [3]float64{} {
+		return [4]float64{}, false
+	}
+	if dot(x, addVectors(va, vb)) < 0 {
+		x = [3]float64{-x[0], -x[1], -x[2]}
+	}
+	if dot(x, addVectors(vc, vd)) < 0 {
+		return [4]float64{}, false
+	}
+	return [4]float64{
+		toDegrees(math.Atan2(x[1], x[0])),
+		toDegrees(math.Atan2(x[2], math.Hypot(x[0], x[1]))),
+	}, true
+}
+
+// unitVector returns the point on the unit sphere at the longitude and latitude of p.
+func unitVector(p [4]float64) [3]float64 {
+	var (
+		lat = toRadians(p[1])
+		lng = toRadians(p[0])
+	)
+	return [3]float64{math.Cos(lat) * math.Cos(lng), math.Cos(lat) * math.Sin(lng), math.Sin(lat)}
+}
+
+// cross returns the cross product of two vectors.
+func cross(u, v [3]float64) [3]float64 {
+	return [3]float64{
+		u[1]*v[2] - u[2]*v[1],
+		u[2]*v[0] - u[0]*v[2],
+		u[0]*v[1] - u[1]*v[0],
+	}
+}
+
+// dot returns the dot product of two vectors.
+func dot(u, v [3]float64) float64 {
+	return u[0]*v[0] + u[1]*v[1] + u[2]*v[2]
+}
+
+// addVectors returns the sum of two vectors.
+func addVectors(u, v [3]float64) [3]float64 {
+	return [3]float64{u[0] + v[0], u[1] + v[1], u[2] + v[2]}
+}
+
+// lerp interpolates linearly between two coordinates.
+func lerp(a, b [4]float64, t float64) [4]float64 {
+	var p [4]float64
+	for i := range p {
+		p[i] = a[i] + t*(b[i]-a[i])
+	}
+	return p
+}
@@ -0,0 +1,233 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	square := &Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+
+	for i, testcase := range []struct {
+		A, B     Geometry
+		Distance float64
+		PA, PB   Point
+	}{
+		{
+			A: &Point{0, 0}, B: &Point{3, 4},
+			Distance: 5, PA: Point{0, 0}, PB: Point{3, 4},
+		},
+		{
+			// Point to segment.
+			A: &Point{5, 5}, B: &Line{{0, 0}, {10, 0}},
+			Distance: 5, PA: Point{5, 5}, PB: Point{5, 0},
+		},
+		{
+			// Point beyond the end of a segment.
+			A: &Point{13, 4}, B: &Line{{0, 0}, {10, 0}},
+			Distance: 5, PA: Point{13, 4}, PB: Point{10, 0},
+		},
+		{
+			// Point on a line.
+			A: &Point{2, 0}, B: &Line{{0, 0}, {10, 0}},
+			Distance: 0, PA: Point{2, 0}, PB: Point{2, 0},
+		},
+		{
+			// Line to line.
+			A: &Line{{0, 0}, {10, 0}}, B: &Line{{4, 3}, {6, 8}},
+			Distance: 3, PA: Point{4, 0}, PB: Point{4, 3},
+		},
+		{
+			// Crossing lines.
+			A: &Line{{0, 0}, {10, 10}}, B: &Line{{0, 10}, {10, 0}},
+			Distance: 0, PA: Point{5, 5}, PB: Point{5, 5},
+		},
+		{
+			// Polygon to polygon.
+			A: square, B: &Polygon{{{13, 4}, {20, 4}, {20, 20}, {13, 4}}},
+			Distance: 3, PA: Point{10, 4}, PB: Point{13, 4},
+		},
+		{
+			// Overlapping polygons.
+			A: square, B: &Polygon{{{5, 5}, {20, 5}, {20, 15}, {5, 5}}},
+			Distance: 0, PA: Point{5, 5}, PB: Point{5, 5},
+		},
+		{
+			// Polygon inside a polygon.
+			A: square, B: &Polygon{{{2, 2}, {3, 2}, {3, 3}, {2, 2}}},
+			Distance: 0, PA: Point{2, 2}, PB: Point{2, 2},
+		},
+		{
+			// Point inside a polygon.
+			A: &Point{5, 5}, B: square,
+			Distance: 0, PA: Point{5, 5}, PB: Point{5, 5},
+		},
+		{
+			// Point in a hole.
+			A: &Point{5, 5}, B: &Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}, {{4, 4}, {4, 7}, {7, 7}, {7, 4}, {4, 4}}},
+			Distance: 1, PA: Point{5, 5}, PB: Point{4, 5},
+		},
+		{
+			A: &MultiPoint{{20, 20}, {12, 5}}, B: &MultiPolygon{{{{30, 30}, {40, 30}, {40, 40}, {30, 30}}}, {{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}},
+			Distance: 2, PA: Point{12, 5}, PB: Point{10, 5},
+		},
+		{
+			A: &GeometryCollection{&Point{-3, -4}, &Line{{20, 0}, {20, 10}}}, B: &FeatureCollection{{Geometry: square}},
+			Distance: 5, PA: Point{-3, -4}, PB: Point{0, 0},
+		},
+		{
+			A: WithSRID(4326, &Point{0, 12}), B: &MultiLine{{{0, 20}, {10, 20}}, {{5, 15}}},
+			Distance: math.Hypot(5, 3), PA: Point{0, 12}, PB: Point{5, 15},
+		},
+	} {
+		if expected, got := testcase.Distance, Distance(testcase.A, testcase.B); math.Abs(expected-got) > 1e-12 {
+			t.Fatalf("(case %d) expected %f, got %f", i, expected, got)
+		}
+		pa, pb := ClosestPoints(testcase.A, testcase.B)
+		if expected, got := testcase.PA, pa; !expected.Equal(&got) {
+			t.Fatalf("(case %d) expected %s, got %s", i, expected, got)
+		}
+		if expected, got := testcase.PB, pb; !expected.Equal(&got) {
+			t.Fatalf("(case %d) expected %s, got %s", i, expected, got)
+		}
+		// Distance is symmetric.
+		if expected, got := testcase.Distance, Distance(testcase.B, testcase.A); math.Abs(expected-got) > 1e-12 {
+			t.Fatalf("(case %d) expected %f, got %f", i, expected, got)
+		}
+	}
+}
+
+func TestDistanceEmpty(t *testing.T) {
+	for i, g := range []Geometry{&Point{math.NaN(), math.NaN()}, &Line{}, &Polygon{}, &GeometryCollection{}, &Feature{}} {
+		if d := Distance(&Point{1, 2}, g); !math.IsNaN(d) {
+			t.Fatalf("(case %d) expected NaN, got %f", i, d)
+		}
+		if pa, pb := ClosestPoints(g, &Point{1, 2}); !pa.IsEmpty() || !pb.IsEmpty() {
+			t.Fatalf("(case %d) expected empty points, got %s and %s", i, pa, pb)
+		}
+	}
+}
+
+func TestDistanceCircle(t *testing.T) {
+	c := &Circle{Coordinates: Point{0, 0}, Radius: 1000 / feetToMeters}
+	if d := Distance(c, &Point{0, 0}); d != 0 {
+		t.Fatalf("expected 0, got %f", d)
+	}
+	expected := 1 - toDegrees(1000/earthRadiusMeters)
+	if got := Distance(c, &Point{1, 0}); math.Abs(expected-got) > 1e-6 {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	if got := WGS84.Distance(c, &Point{0.1, 0}); math.Abs(WGS84.pointDistance([4]float64{0, 0}, [4]float64{0.1, 0})-1000-got) > 10 {
+		t.Fatalf("expected about %f, got %f", WGS84.pointDistance([4]float64{0, 0}, [4]float64{0.1, 0})-1000, got)
+	}
+}
+
+func TestGeodesicDistance(t *testing.T) {
+	// A road along the equator and an incident north of it.
+	var (
+		road     = &Line{{0, 0}, {1, 0}}
+		incident = &Point{0.5, 0.01}
+	)
+	if expected, got := WGS84.pointDistance([4]float64{0.5, 0}, [4]float64{0.5, 0.01}), WGS84.Distance(incident, road); math.Abs(expected-got) > 1e-6 {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	pa, pb := WGS84.ClosestPoints(incident, road)
+	if expected, got := *incident, pa; !expected.Equal(&got) {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if math.Abs(pb[0]-0.5) > 1e-9 || math.Abs(pb[1]) > 1e-9 {
+		t.Fatalf("expected the closest point of the road to be POINT(0.5 0), got %s", pb)
+	}
+
+	// On a geodesic between two points at the same latitude the closest point
+	// to a point on that latitude halfway between them is not at the same latitude,
+	// since the geodesic bulges towards the pole.
+	var (
+		a = [4]float64{-30, 60}
+		b = [4]float64{30, 60}
+		p = [4]float64{0, 60}
+	)
+	q, d := geodesicMetric{g: WGS84}.segment(p, a, b)
+	if q[1] <= 60 || math.Abs(q[0]) > 1e-6 {
+		t.Fatalf("expected a point north of %v, got %v", p, q)
+	}
+	if expected := WGS84.pointDistance(p, q); math.Abs(expected-d) > 1e-9 {
+		t.Fatalf("expected %f, got %f", expected, d)
+	}
+	_, _, azi2 := WGS84.Inverse(a[1], a[0], q[1], q[0])
+	_, azi1, _ := WGS84.Inverse(q[1], q[0], p[1], p[0])
+	if angle := math.Abs(angNormalize(azi1 - azi2)); math.Abs(angle-90) > 1e-3 {
+		t.Fatalf("expected the shortest path to meet the geodesic at a right angle, got %f", angle)
+	}
+
+	// Crossing lines.
+	if d := WGS84.Distance(&Line{{-1, -1}, {1, 1}}, &Line{{-1, 1}, {1, -1}}); d != 0 {
+		t.Fatalf("expected 0, got %f", d)
+	}
+	// Lines that would cross on the other side of the earth.
+	if d := WGS84.Distance(&Line{{-1, 0}, {1, 0}}, &Line{{180, -1}, {180, 1}}); d < 1e7 {
+		t.Fatalf("expected a long distance, got %f", d)
+	}
+	// Point inside a polygon.
+	if d := WGS84.Distance(&Point{0.5, 0.5}, &Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}}); d != 0 {
+		t.Fatalf("expected 0, got %f", d)
+	}
+}
+
+// unprunedMetric measures distances in the plane without skipping any parts.
+type unprunedMetric struct {
+	planarMetric
+}
+
+func (unprunedMetric) gap(a, b Envelope) float64 {
+	return 0
+}
+
+func TestDistancePruning(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randomLine := func(x, y float64) *Line {
+		line := make(Line, 50)
+		for i := range line {
+			line[i] = [4]float64{x + r.Float64()*10, y + r.Float64()*10}
+		}
+		return &line
+	}
+	for i := 0; i < 50; i++ {
+		var (
+			a, b           = randomLine(0, 0), randomLine(float64(i), 5+float64(i))
+			pa, pb, d      = closestPoints(a, b, planarMetric{})
+			qa, qb, expect = closestPoints(a, b, unprunedMetric{})
+		)
+		if d != expect || pa != qa || pb != qb {
+			t.Fatalf("(case %d) expected %v %v at %f, got %v %v at %f", i, qa, qb, expect, pa, pb, d)
+		}
+	}
+}
+
+func TestGeodesicSegment(t *testing.T) {
+	m := geodesicMetric{g: WGS84}
+	for i, testcase := range []struct {
+		P, A, B [4]float64
+	}{
+		{P: [4]float64{10, 80}, A: [4]float64{-170, 0}, B: [4]float64{5, 1}},
+		{P: [4]float64{0, -60}, A: [4]float64{-80, 10}, B: [4]float64{90, -5}},
+		{P: [4]float64{100, 0}, A: [4]float64{0, 0}, B: [4]float64{170, 0}},
+		{P: [4]float64{45, 45}, A: [4]float64{0, 0}, B: [4]float64{1, 1}},
+	} {
+		// Compare with the closest of many points along the geodesic.
+		var (
+			_, d     = m.segment(testcase.P, testcase.A, testcase.B)
+			r        = WGS84.inverse(testcase.A[1], testcase.A[0], testcase.B[1], testcase.B[0], false)
+			line     = newGeodesicLine(WGS84, testcase.A[1], testcase.A[0], atan2d(r.salp1, r.calp1))
+			expected = math.Inf(1)
+		)
+		for j := 0; j <= 10000; j++ {
+			lat, lon, _ := line.position(r.s12 * float64(j) / 10000)
+			expected = math.Min(expected, m.points(testcase.P, [4]float64{lon, lat}))
+		}
+		if d > expected+1e-3 || d < expected-r.s12/10000 {
+			t.Fatalf("(case %d) expected %f, got %f", i, expected, d)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+)
+
+// Envelope is an axis-aligned bounding box.
+// Min is the southwesterly (and lowest) corner and Max is the
+// northeasterly (and highest) corner.
+// Z ordinates are only meaningful if HasZ is true,
+// and M ordinates are never used.
+//
+// As RFC 7946 allows, an envelope whose west edge (Min[0]) is greater
+// than its east edge (Max[0]) crosses the antimeridian,
+// e.g. the envelope from 170 to -170 is 20 degrees wide.
+type Envelope struct {
+	Min  Point
+	Max  Point
+	HasZ bool
+}
+
+// NewEnvelope returns the envelope for a GeoJSON bounding box,
+// which is [west, south, east, north] or [west, south, low, east, north, high].
+// An error is returned if the bounding box has the wrong number of values,
+// or if it is not valid, see Validate.
+func NewEnvelope(bbox []float64) (Envelope, error) {
+	var env Envelope
+	switch len(bbox) {
+	case 4:
+		env.Min = Point{bbox[0], bbox[1]}
+		env.Max = Point{bbox[2], bbox[3]}
+	case 6:
+		env.Min = Point{bbox[0], bbox[1], bbox[2]}
+		env.Max = Point{bbox[3], bbox[4], bbox[5]}
+		env.HasZ = true
+	default:
+		return Envelope{}, fmt.Errorf("bbox must have 4 or 6 values, got %d", len(bbox))
+	}
+	if err := env.Validate(); err != nil {
+		return Envelope{}, err
+	}
+	return env, nil
+}
+
+// Validate returns an error if the envelope is empty or has a value that is not finite,
+// if its south edge is north of its north edge,
+// or if its low Z is greater than its high Z.
+// West may be greater than east, see CrossesAntimeridian.
+func (env Envelope) Validate() error {
+	for _, x := range []float64{env.Min[0], env.Min[1], env.Min[2], env.Max[0], env.Max[1], env.Max[2]} {
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return fmt.Errorf("bbox values must be finite, got %g", x)
+		}
+	}
+	if south, north := env.Min[1], env.Max[1]; south > north {
+		return fmt.Errorf("bbox south %g is greater than north %g", south, north)
+	}
+	if low, high := env.Min[2], env.Max[2]; env.HasZ && low > high {
+		return fmt.Errorf("bbox low %g is greater than high %g", low, high)
+	}
+	return nil
+}
+
+// CrossesAntimeridian returns true if the envelope's west edge is east of its east edge.
+func (env Envelope) CrossesAntimeridian() bool {
+	return env.Min[0] > env.Max[0]
+}
+
+// Contains returns true if the point is inside the envelope or on its edge.
+// If the envelope has Z, the point's Z must be inside its Z range too.
+func (env Envelope) Contains(p Point) bool {
+	if env.IsEmpty() || p.IsEmpty() {
+		return false
+	}
+	if !env.containsX(p[0]) || p[1] < env.Min[1] || p[1] > env.Max[1] {
+		return false
+	}
+	return !env.HasZ || (p[2] >= env.Min[2] && p[2] <= env.Max[2])
+}
+
+// Intersects returns true if the envelopes have at least one point in common.
+// Z ranges are only compared if both envelopes have Z.
+func (env Envelope) Intersects(other Envelope) bool {
+	if env.IsEmpty() || other.IsEmpty() {
+		return false
+	}
+	if env.Min[1] > other.Max[1] || other.Min[1] > env.Max[1] {
+		return false
+	}
+	if env.HasZ && other.HasZ && (env.Min[2] > other.Max[2] || other.Min[2] > env.Max[2]) {
+		return false
+	}
+	for _, a := range env.xRanges() {
+		for _, b := range other.xRanges() {
+			if a[0] <= b[1] && b[0] <= a[1] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Expand returns the smallest envelope that contains the envelope and the point.
+// An envelope that crosses the antimeridian is grown towards the point
+// in whichever direction is shorter; other envelopes are grown in the plane.
+func (env Envelope) Expand(p Point) Envelope {
+	if !env.CrossesAntimeridian() || env.IsEmpty() || p.IsEmpty() {
+		return env.extend(p)
+	}
+	if !env.containsX(p[0]) {
+		if east, west := mod360(p[0]-env.Max[0]), mod360(env.Min[0]-p[0]); east <= west {
+			env.Max[0] = p[0]
+		} else {
+			env.Min[0] = p[0]
+		}
+	}
+	for i := 1; i < 3; i++ {
+		env.Min[i] = math.Min(env.Min[i], p[i])
+		env.Max[i] = math.Max(env.Max[i], p[i])
+	}
+	return env
+}
+
+// Union returns the smallest envelope that contains both envelopes.
+// If either envelope crosses the antimeridian the result is the shortest
+// range of longitudes that covers both, which may also cross the antimeridian.
+// The Z range of an envelope without Z is ignored.
+func (env Envelope) Union(other Envelope) Envelope {
+	crosses := env.CrossesAntimeridian() || other.CrossesAntimeridian()
+	if !crosses || env.IsEmpty() || other.IsEmpty() {
+		return env.merge(other)
+	}
+	west, east := env.unionX(other)
+	env = env.merge(other)
+	env.Min[0], env.Max[0] = west, east
+	return env
+}
+
+// unionX returns the shortest range of longitudes that covers
+// the X ranges of both envelopes.
+func (env Envelope) unionX(other Envelope) (west, east float64) {
+	var (
+		aw, ae = env.Min[0], env.Max[0]
+		bw, be = other.Min[0], other.Max[0]
+	)
+	switch {
+	case env.containsX(bw) && env.containsX(be):
+		// Either other is inside env, or together they go all the way around.
+		if mod360(bw-aw) <= mod360(be-aw) {
+			return aw, ae
+		}
+		return -180, 180
+	case env.containsX(bw):
+		return aw, be
+	case env.containsX(be):
+		return bw, ae
+	case other.containsX(aw):
+		return bw, be
+	}
+	// The ranges are disjoint, so close the smaller gap between them.
+	if mod360(bw-ae) <= mod360(aw-be) {
+		return aw, be
+	}
+	return bw, ae
+}
+
+// containsX returns true if the envelope's X range contains x.
+func (env Envelope) containsX(x float64) bool {
+	if env.CrossesAntimeridian() {
+		return x >= env.Min[0] || x <= env.Max[0]
+	}
+	return x >= env.Min[0] && x <= env.Max[0]
+}
+
+// xRanges returns the envelope's X range as ranges that do not cross the antimeridian.
+func (env Envelope) xRanges() [][2]float64 {
+	if env.CrossesAntimeridian() {
+		return [][2]float64{{env.Min[0], 180}, {-180, env.Max[0]}}
+	}
+	return [][2]float64{{env.Min[0], env.Max[0]}}
+}
+
+// mod360 returns x modulo 360 in the range [0, 360).
+func mod360(x float64) float64 {
+	if x = math.Mod(x, 360); x < 0 {
+		x += 360
+	}
+	return x
+}
+
+// emptyEnvelope returns the envelope of an empty geometry.
+func emptyEnvelope() Envelope {
+	nan := math.NaN()
+	return Envelope{
+		Min: Point{nan, nan},
+		Max: Point{nan, nan},
+	}
+}
+
+// IsEmpty returns true if the envelope does not contain any point,
+// i.e. it is the envelope of an empty geometry.
+func (env Envelope) IsEmpty() bool {
+	return env.Min.IsEmpty() || env.Max.IsEmpty()
+}
+
+// BBox returns the envelope as a GeoJSON bounding box,
+// i.e. [west, south, east, north] or [west, south, low, east, north, high]
+// if the envelope has Z.
+// The bounding box of an empty envelope is nil.
+func (env Envelope) BBox() []float64 {
+	switch {
+	case env.IsEmpty():
+		return nil
+	case env.HasZ:
+		return []float64{env.Min[0], env.Min[1], env.Min[2], env.Max[0], env.Max[1], env.Max[2]}
+	}
+	return []float64{env.Min[0], env.Min[1], env.Max[0], env.Max[1]}
+}
+
+// extend returns the envelope grown to include the point.
+// Empty points are ignored.
+func (env Envelope) extend(p Point) Envelope {
+	if p.IsEmpty() {
+		return env
+	}
+	if env.IsEmpty() {
+		return Envelope{
+			Min:  Point{p[0], p[1], p[2]},
+			Max:  Point{p[0], p[1], p[2]},
+			HasZ: env.HasZ,
+		}
+	}
+	for i := 0; i < 3; i++ {
+		env.Min[i] = math.Min(env.Min[i], p[i])
+		env.Max[i] = math.Max(env.Max[i], p[i])
+	}
+	return env
+}
+
+// merge returns the smallest envelope that contains both envelopes.
+// The Z range of an envelope without Z is ignored.
+func (env Envelope) merge(other Envelope) Envelope {
+	switch {
+	case other.IsEmpty():
+		return env
+	case env.IsEmpty():
+		return other
+	}
+	for i := 0; i < 2; i++ {
+		env.Min[i] = math.Min(env.Min[i], other.Min[i])
+		env.Max[i] = math.Max(env.Max[i], other.Max[i])
+	}
+	switch {
+	case env.HasZ && other.HasZ:
+		env.Min[2] = math.Min(env.Min[2], other.Min[2])
+		env.Max[2] = math.Max(env.Max[2], other.Max[2])
+	case other.HasZ:
+		env.Min[2], env.Max[2], env.HasZ = other.Min[2], other.Max[2], true
+	}
+	return env
+}
+
+// bounds computes the envelope of a geometry from its coordinates.
+// The envelope has Z if the layout of the geometry has Z.
+func bounds(g Geometry) Envelope {
+	v := &envelopeVisitor{env: emptyEnvelope()}
+	g.VisitCoordinates(v)
+	env := v.env
+	if env.HasZ = LayoutOf(g).HasZ(); !env.HasZ && !env.IsEmpty() {
+		env.Min[2], env.Max[2] = 0, 0
+	}
+	env.Min[2], env.Max[2] = unmarked(env.Min[2]), unmarked(env.Max[2])
+	return env
+}
+
+// envelopeVisitor grows an envelope to include every point it visits.
+type envelopeVisitor struct {
+	env Envelope
+}
+
+// Visit visits a point.
+func (v *envelopeVisitor) Visit(p Point) {
+	v.env = v.env.extend(p)
+}
+
+// toDegrees converts from radians to degrees.
+func toDegrees(radians float64) float64 {
+	return (180 * radians) / math.Pi
+}
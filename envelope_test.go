@@ -0,0 +1,371 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBounds(t *testing.T) {
+	for i, testcase := range []struct {
+		Input    Geometry
+		Expected []float64
+	}{
+		{Input: &Point{1, 2}, Expected: []float64{1, 2, 1, 2}},
+		{Input: &Point{1, 2, 3}, Expected: []float64{1, 2, 3, 1, 2, 3}},
+		{Input: &Point{1, 2, 0, 4}, Expected: []float64{1, 2, 1, 2}},
+		{Input: &Point{math.NaN(), math.NaN()}},
+		{Input: &Line{{0, 5}, {-2, 1}, {4, 3}}, Expected: []float64{-2, 1, 4, 5}},
+		{Input: &Line{}},
+		{Input: &MultiPoint{{1, 1, -1}, {2, 0, 7}}, Expected: []float64{1, 0, -1, 2, 1, 7}},
+		{Input: &MultiLine{{{0, 0}, {1, 1}}, {{5, -5}, {6, -4}}}, Expected: []float64{0, -5, 6, 1}},
+		{Input: &Polygon{{{0, 0}, {4, 0}, {4, 3}, {0, 0}}}, Expected: []float64{0, 0, 4, 3}},
+		{Input: &MultiPolygon{{{{0, 0}, {1, 0}, {0, 1}, {0, 0}}}, {{{10, 10}, {11, 10}, {10, 12}, {10, 10}}}}, Expected: []float64{0, 0, 11, 12}},
+		{
+			// Only the member with Z contributes to the Z range.
+			Input:    &GeometryCollection{&Point{-1, -1}, &Line{{2, 2, 5}, {3, 3, 6}}},
+			Expected: []float64{-1, -1, 5, 3, 3, 6},
+		},
+		{Input: &GeometryCollection{}},
+		{Input: &GeometryCollection{&Line{}, &Point{4, 5}}, Expected: []float64{4, 5, 4, 5}},
+		{Input: &Feature{Geometry: &Point{1, 2}}, Expected: []float64{1, 2, 1, 2}},
+		{Input: &Feature{}},
+		{
+			Input: &FeatureCollection{
+				{Geometry: &Point{1, 2}},
+				{},
+				{Geometry: &Line{{-3, 0}, {0, 9}}},
+			},
+			Expected: []float64{-3, 0, 1, 9},
+		},
+		{Input: WithLayout(XYZ, &Point{1, 2}), Expected: []float64{1, 2, 0, 1, 2, 0}},
+		{Input: WithLayout(XY, &Point{1, 2, 3}), Expected: []float64{1, 2, 1, 2}},
+		{Input: WithSRID(4326, &Point{1, 2}), Expected: []float64{1, 2, 1, 2}},
+		{Input: WithBBox([]float64{0, 0, 9, 9}, &Point{1, 2}), Expected: []float64{1, 2, 1, 2}},
+	} {
+		if expected, got := testcase.Expected, testcase.Input.Bounds().BBox(); !boxEqual(expected, got) {
+			t.Fatalf("(case %d) expected %v, got %v", i, expected, got)
+		}
+		if expected, got := testcase.Expected == nil, testcase.Input.Bounds().IsEmpty(); expected != got {
+			t.Fatalf("(case %d) expected IsEmpty to be %t, got %t", i, expected, got)
+		}
+	}
+}
+
+func TestBoundsCircle(t *testing.T) {
+	// One nautical mile is very nearly one minute of latitude.
+	env := (&Circle{Coordinates: Point{10, 60}, Radius: 1852 / feetToMeters}).Bounds()
+	for i, testcase := range []struct {
+		Expected float64
+		Got      float64
+	}{
+		{Expected: 60 - 1.0/60, Got: env.Min[1]},
+		{Expected: 60 + 1.0/60, Got: env.Max[1]},
+		{Expected: 10 - 2.0/60, Got: env.Min[0]},
+		{Expected: 10 + 2.0/60, Got: env.Max[0]},
+	} {
+		if math.Abs(testcase.Expected-testcase.Got) > 1e-4 {
+			t.Fatalf("(case %d) expected %f, got %f", i, testcase.Expected, testcase.Got)
+		}
+	}
+
+	// Circles that reach a pole span every longitude.
+	env = (&Circle{Coordinates: Point{10, 89.99}, Radius: 1852 / feetToMeters}).Bounds()
+	if env.Min[0] != -180 || env.Max[0] != 180 || env.Max[1] != 90 {
+		t.Fatalf("expected the envelope to span every longitude up to the pole, got %v", env.BBox())
+	}
+
+	// Circles that cross the antimeridian have envelopes that cross it too.
+	for i, center := range []Point{{179.9, 10}, {-179.9, 10}} {
+		env = (&Circle{Coordinates: center, Radius: 50000 / feetToMeters}).Bounds()
+		if !env.CrossesAntimeridian() || env.Min[0] < 179 || env.Max[0] > -179 {
+			t.Fatalf("(case %d) expected the envelope to cross the antimeridian, got %v", i, env.BBox())
+		}
+		for _, p := range []Point{{180, 10}, {-180, 10}, center} {
+			if !env.Contains(p) {
+				t.Fatalf("(case %d) expected the envelope %v to contain %v", i, env.BBox(), p)
+			}
+		}
+	}
+	// So do the envelopes of collections with such circles.
+	circle := &Circle{Coordinates: Point{179.999, 0}, Radius: 5000 / feetToMeters}
+	for i, g := range []Geometry{
+		&GeometryCollection{circle, &Point{0, 0}},
+		&FeatureCollection{{Geometry: &Point{0, 0}}, {Geometry: circle}},
+	} {
+		env = g.Bounds()
+		for _, p := range []Point{{180, 0}, {-180, 0}, {179.999, 0}, {0, 0}} {
+			if !env.Contains(p) {
+				t.Fatalf("(case %d) expected the envelope %v to contain %v", i, env.BBox(), p)
+			}
+		}
+	}
+	env = (&Circle{Coordinates: Point{179.9, 10}, Radius: 1000 / feetToMeters}).Bounds()
+	if env.CrossesAntimeridian() || env.Max[0] > 180 {
+		t.Fatalf("expected the envelope to stop short of the antimeridian, got %v", env.BBox())
+	}
+
+	if !(&Circle{Coordinates: Point{math.NaN(), math.NaN()}}).Bounds().IsEmpty() {
+		t.Fatal("expected the bounds of an empty circle to be empty")
+	}
+}
+
+func TestWithComputedBBox(t *testing.T) {
+	marshalTestcases{
+		{
+			Input:    WithComputedBBox(&Line{{0, 5}, {-2, 1}, {4, 3}}),
+			Expected: `{"type":"LineString","coordinates":[[0,5],[-2,1],[4,3]],"bbox":[-2,1,4,5]}`,
+		},
+		{
+			Input:    WithComputedBBox(&Point{1, 2, 3}),
+			Expected: `{"type":"Point","coordinates":[1,2,3],"bbox":[1,2,3,1,2,3]}`,
+		},
+		{
+			Input:    WithComputedBBox(&Line{}),
+			Expected: `{"type":"LineString","coordinates":[]}`,
+		},
+		{
+			Input:    WithComputedBBox(&Feature{Geometry: &Point{1, 2}}),
+			Expected: `{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":null,"bbox":[1,2,1,2]}`,
+		},
+		{
+			Input:    WithComputedBBox(&FeatureCollection{{Geometry: &Point{1, 2}}, {Geometry: &Point{3, 0}}}),
+			Expected: `{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":null},{"type":"Feature","geometry":{"type":"Point","coordinates":[3,0]},"properties":null}],"bbox":[1,0,3,2]}`,
+		},
+	}.pass(t)
+
+	// The bounding box follows changes to the geometry.
+	line := &Line{{0, 0}, {1, 1}}
+	g := WithComputedBBox(line)
+	line.Transform(pointShifter(1))
+	if expected, got := (WithBBox([]float64{1, 1, 2, 2}, &Line{{1, 1}, {2, 2}})), g; !got.Equal(expected) {
+		t.Fatalf("expected %s to equal %s", got, expected)
+	}
+
+	// RFC 7946 computes the bounding box from the normalized geometry.
+	data, err := (RFC7946{Precision: 1}).Marshal(WithComputedBBox(&Point{1.26, 2.04}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := `{"type":"Point","coordinates":[1.3,2],"bbox":[1.3,2,1.3,2]}`, string(data); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestNewEnvelope(t *testing.T) {
+	for i, testcase := range []struct {
+		Input        []float64
+		Expected     Envelope
+		Antimeridian bool
+	}{
+		{
+			Input:    []float64{-1, -2, 3, 4},
+			Expected: Envelope{Min: Point{-1, -2}, Max: Point{3, 4}},
+		},
+		{
+			Input:    []float64{-1, -2, -3, 3, 4, 5},
+			Expected: Envelope{Min: Point{-1, -2, -3}, Max: Point{3, 4, 5}, HasZ: true},
+		},
+		{
+			Input:        []float64{170, -10, -170, 10},
+			Expected:     Envelope{Min: Point{170, -10}, Max: Point{-170, 10}},
+			Antimeridian: true,
+		},
+	} {
+		env, err := NewEnvelope(testcase.Input)
+		if err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if expected, got := testcase.Expected, env; expected != got {
+			t.Fatalf("(case %d) expected %v, got %v", i, expected, got)
+		}
+		if expected, got := testcase.Antimeridian, env.CrossesAntimeridian(); expected != got {
+			t.Fatalf("(case %d) expected %t, got %t", i, expected, got)
+		}
+		if expected, got := testcase.Input, env.BBox(); !boxEqual(expected, got) {
+			t.Fatalf("(case %d) expected %v, got %v", i, expected, got)
+		}
+	}
+
+	// Fail
+	for i, testcase := range []struct {
+		Input    []float64
+		Expected string
+	}{
+		{Input: nil, Expected: "bbox must have 4 or 6 values, got 0"},
+		{Input: []float64{1, 2, 3}, Expected: "bbox must have 4 or 6 values, got 3"},
+		{Input: []float64{0, 5, 1, 4}, Expected: "bbox south 5 is greater than north 4"},
+		{Input: []float64{0, 0, 2, 1, 1, 1}, Expected: "bbox low 2 is greater than high 1"},
+		{Input: []float64{0, 0, math.Inf(1), 1}, Expected: "bbox values must be finite, got +Inf"},
+		{Input: []float64{0, math.NaN(), 1, 1}, Expected: "bbox values must be finite, got NaN"},
+	} {
+		_, err := NewEnvelope(testcase.Input)
+		if err == nil {
+			t.Fatalf("(case %d) expected error, got nil", i)
+		}
+		if expected, got := testcase.Expected, err.Error(); expected != got {
+			t.Fatalf("(case %d) expected %s, got %s", i, expected, got)
+		}
+	}
+	if err := emptyEnvelope().Validate(); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestEnvelopeContains(t *testing.T) {
+	for i, testcase := range []struct {
+		Envelope Envelope
+		Inside   []Point
+		Outside  []Point
+	}{
+		{
+			Envelope: Envelope{Min: Point{0, 0}, Max: Point{2, 1}},
+			Inside:   []Point{{0, 0}, {1, 0.5}, {2, 1}, {1, 1, 99}},
+			Outside:  []Point{{-1, 0}, {1, 2}, {3, 0.5}, {math.NaN(), math.NaN()}},
+		},
+		{
+			Envelope: Envelope{Min: Point{0, 0, 10}, Max: Point{2, 1, 20}, HasZ: true},
+			Inside:   []Point{{1, 1, 10}, {2, 0, 20}},
+			Outside:  []Point{{1, 1}, {1, 1, 21}},
+		},
+		{
+			Envelope: Envelope{Min: Point{170, -10}, Max: Point{-170, 10}},
+			Inside:   []Point{{175, 0}, {180, 0}, {-180, 0}, {-170, 10}},
+			Outside:  []Point{{0, 0}, {169, 0}, {-169, 0}, {175, 11}},
+		},
+	} {
+		for _, p := range testcase.Inside {
+			if !testcase.Envelope.Contains(p) {
+				t.Fatalf("(case %d) expected %v to contain %v", i, testcase.Envelope, p)
+			}
+		}
+		for _, p := range testcase.Outside {
+			if testcase.Envelope.Contains(p) {
+				t.Fatalf("(case %d) expected %v to not contain %v", i, testcase.Envelope, p)
+			}
+		}
+	}
+	if emptyEnvelope().Contains(Point{0, 0}) {
+		t.Fatal("expected an empty envelope to not contain anything")
+	}
+}
+
+func TestEnvelopeIntersects(t *testing.T) {
+	for i, testcase := range []struct {
+		A, B     []float64
+		Expected bool
+	}{
+		{A: []float64{0, 0, 2, 2}, B: []float64{1, 1, 3, 3}, Expected: true},
+		{A: []float64{0, 0, 2, 2}, B: []float64{2, 2, 3, 3}, Expected: true},
+		{A: []float64{0, 0, 2, 2}, B: []float64{3, 0, 4, 2}},
+		{A: []float64{0, 0, 2, 2}, B: []float64{0, 3, 2, 4}},
+		{A: []float64{0, 0, 0, 2, 2, 2}, B: []float64{0, 0, 3, 2, 2, 4}},
+		{A: []float64{0, 0, 0, 2, 2, 2}, B: []float64{0, 0, 3, 2}, Expected: true},
+		{A: []float64{170, 0, -170, 2}, B: []float64{-175, 1, -160, 3}, Expected: true},
+		{A: []float64{170, 0, -170, 2}, B: []float64{175, 1, 178, 3}, Expected: true},
+		{A: []float64{170, 0, -170, 2}, B: []float64{160, 0, -160, 2}, Expected: true},
+		{A: []float64{170, 0, -170, 2}, B: []float64{-160, 0, 160, 2}},
+		{A: []float64{170, 0, -170, 2}, B: []float64{-10, 0, 10, 2}},
+	} {
+		a, err := NewEnvelope(testcase.A)
+		if err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		b, err := NewEnvelope(testcase.B)
+		if err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if expected, got := testcase.Expected, a.Intersects(b); expected != got {
+			t.Fatalf("(case %d) expected %t, got %t", i, expected, got)
+		}
+		if expected, got := testcase.Expected, b.Intersects(a); expected != got {
+			t.Fatalf("(case %d) expected %t, got %t", i, expected, got)
+		}
+	}
+	if emptyEnvelope().Intersects(Envelope{Max: Point{1, 1}}) {
+		t.Fatal("expected an empty envelope to not intersect anything")
+	}
+}
+
+func TestEnvelopeExpand(t *testing.T) {
+	for i, testcase := range []struct {
+		Input    Envelope
+		Point    Point
+		Expected []float64
+	}{
+		{Input: emptyEnvelope(), Point: Point{1, 2}, Expected: []float64{1, 2, 1, 2}},
+		{Input: Envelope{Max: Point{1, 1}}, Point: Point{math.NaN(), math.NaN()}, Expected: []float64{0, 0, 1, 1}},
+		{Input: Envelope{Max: Point{1, 1}}, Point: Point{3, -1}, Expected: []float64{0, -1, 3, 1}},
+		{Input: Envelope{Max: Point{1, 1, 1}, HasZ: true}, Point: Point{0, 0, 5}, Expected: []float64{0, 0, 0, 1, 1, 5}},
+		{Input: Envelope{Min: Point{170, 0}, Max: Point{-170, 1}}, Point: Point{-160, 2}, Expected: []float64{170, 0, -160, 2}},
+		{Input: Envelope{Min: Point{170, 0}, Max: Point{-170, 1}}, Point: Point{160, 0}, Expected: []float64{160, 0, -170, 1}},
+		{Input: Envelope{Min: Point{170, 0}, Max: Point{-170, 1}}, Point: Point{179, 0}, Expected: []float64{170, 0, -170, 1}},
+	} {
+		if expected, got := testcase.Expected, testcase.Input.Expand(testcase.Point).BBox(); !boxEqual(expected, got) {
+			t.Fatalf("(case %d) expected %v, got %v", i, expected, got)
+		}
+	}
+}
+
+func TestEnvelopeUnion(t *testing.T) {
+	for i, testcase := range []struct {
+		A, B     Envelope
+		Expected []float64
+	}{
+		{A: emptyEnvelope(), B: emptyEnvelope()},
+		{A: emptyEnvelope(), B: Envelope{Max: Point{1, 1}}, Expected: []float64{0, 0, 1, 1}},
+		{A: Envelope{Max: Point{1, 1}}, B: Envelope{Min: Point{-1, 2}, Max: Point{0, 3}}, Expected: []float64{-1, 0, 1, 3}},
+		{
+			A:        Envelope{Max: Point{1, 1}},
+			B:        Envelope{Min: Point{0, 0, -1}, Max: Point{0, 0, 1}, HasZ: true},
+			Expected: []float64{0, 0, -1, 1, 1, 1},
+		},
+		{
+			// Overlapping across the antimeridian.
+			A:        Envelope{Min: Point{170, 0}, Max: Point{-170, 1}},
+			B:        Envelope{Min: Point{-175, 0}, Max: Point{-160, 2}},
+			Expected: []float64{170, 0, -160, 2},
+		},
+		{
+			A:        Envelope{Min: Point{170, 0}, Max: Point{-170, 1}},
+			B:        Envelope{Min: Point{160, 0}, Max: Point{175, 1}},
+			Expected: []float64{160, 0, -170, 1},
+		},
+		{
+			// Inside.
+			A:        Envelope{Min: Point{170, 0}, Max: Point{-170, 1}},
+			B:        Envelope{Min: Point{-179, 0}, Max: Point{-171, 1}},
+			Expected: []float64{170, 0, -170, 1},
+		},
+		{
+			// Contains.
+			A:        Envelope{Min: Point{175, 0}, Max: Point{178, 1}},
+			B:        Envelope{Min: Point{170, 0}, Max: Point{-170, 1}},
+			Expected: []float64{170, 0, -170, 1},
+		},
+		{
+			// Disjoint, the gap to the east is smaller.
+			A:        Envelope{Min: Point{170, 0}, Max: Point{-170, 1}},
+			B:        Envelope{Min: Point{-150, 0}, Max: Point{-140, 1}},
+			Expected: []float64{170, 0, -140, 1},
+		},
+		{
+			// Disjoint, the gap to the west is smaller.
+			A:        Envelope{Min: Point{170, 0}, Max: Point{-170, 1}},
+			B:        Envelope{Min: Point{140, 0}, Max: Point{150, 1}},
+			Expected: []float64{140, 0, -170, 1},
+		},
+		{
+			// Together they go all the way around.
+			A:        Envelope{Min: Point{90, 0}, Max: Point{-90, 1}},
+			B:        Envelope{Min: Point{-100, 0}, Max: Point{100, 1}},
+			Expected: []float64{-180, 0, 180, 1},
+		},
+	} {
+		if expected, got := testcase.Expected, testcase.A.Union(testcase.B).BBox(); !boxEqual(expected, got) {
+			t.Fatalf("(case %d) expected %v, got %v", i, expected, got)
+		}
+		if expected, got := testcase.Expected, testcase.B.Union(testcase.A).BBox(); !boxEqual(expected, got) {
+			t.Fatalf("(case %d) expected %v, got %v", i, expected, got)
+		}
+	}
+}
@@ -1,55 +1,150 @@
 package geo
 
 import (
+	"bytes"
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
 )
 
 var (
-	featureJSONPrefix = []byte(`{"type":"Feature","geometry":`)
+	featureJSONPrefix = []byte(`{"type":"Feature"`)
+	idJSONKey         = []byte(`,"id":`)
+	geometryJSONKey   = []byte(`,"geometry":`)
 	propertiesJSONKey = []byte(`,"properties":`)
 )
 
+// featureReservedMembers are the members of a GeoJSON feature
+// that can not be used as foreign members.
+var featureReservedMembers = map[string]bool{
+	"bbox":       true,
+	"geometry":   true,
+	"id":         true,
+	"properties": true,
+	"type":       true,
+}
+
 // Feature is a GeoJSON feature.
 type Feature struct {
+	// ID is the feature's identifier, which must be a string or a number.
+	// Numbers are unmarshalled as json.Number so that large integers are kept exactly.
+	ID interface{} `json:"id,omitempty"`
+
 	Geometry   Geometry    `json:"geometry"`
 	Properties interface{} `json:"properties,omitempty"`
+
+	// Members holds foreign members, i.e. members of the feature object
+	// other than those that GeoJSON defines, such as "title".
+	// Numbers are unmarshalled as json.Number.
+	// Reserved names like "id" and "geometry" are ignored when marshalling.
+	Members map[string]interface{} `json:"-"`
 }
 
 // Equal compares one feature to another.
 // Note that this method does not compare properties.
+// Features with null geometries are equal to each other.
 func (f Feature) Equal(g Geometry) bool {
 	other, ok := g.(*Feature)
 	if !ok {
 		return false
 	}
+	if f.Geometry == nil || other.Geometry == nil {
+		return f.Geometry == nil && other.Geometry == nil
+	}
 	return f.Geometry.Equal(other.Geometry)
 }
 
 // Contains determines if the feature's geometry contains the point.
+// A feature with a null geometry contains nothing.
 func (f Feature) Contains(p Point) bool {
+	if f.Geometry == nil {
+		return false
+	}
 	return f.Geometry.Contains(p)
 }
 
+// IsEmpty returns true if the feature's geometry is null or empty.
+func (f Feature) IsEmpty() bool {
+	return f.Geometry == nil || f.Geometry.IsEmpty()
+}
+
+// Bounds returns the envelope of the feature's geometry.
+// The envelope of a null geometry is empty.
+func (f Feature) Bounds() Envelope {
+	if f.Geometry == nil {
+		return emptyEnvelope()
+	}
+	return f.Geometry.Bounds()
+}
+
 // MarshalJSON marshals the feature to GeoJSON.
 func (f Feature) MarshalJSON() ([]byte, error) {
-	geom, err := f.Geometry.MarshalJSON()
-	if err != nil {
-		return nil, err
+	buf := append([]byte{}, featureJSONPrefix...)
+	if f.ID != nil {
+		id, err := marshalFeatureID(f.ID)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, idJSONKey...)
+		buf = append(buf, id...)
+	}
+	geom := []byte("null")
+	if f.Geometry != nil {
+		data, err := f.Geometry.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		geom = data
 	}
 	props, err := json.Marshal(f.Properties)
 	if err != nil {
 		return nil, err
 	}
-	buf := append(featureJSONPrefix, geom...)
+	buf = append(buf, geometryJSONKey...)
+	buf = append(buf, geom...)
 	buf = append(buf, propertiesJSONKey...)
 	buf = append(buf, props...)
+
+	keys := make([]string, 0, len(f.Members))
+	for key := range f.Members {
+		if !featureReservedMembers[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		k, _ := json.Marshal(key) // Never fails.
+		v, err := json.Marshal(f.Members[key])
+		if err != nil {
+			return nil, err
+		}
+		buf = append(append(append(buf, ','), k...), ':')
+		buf = append(buf, v...)
+	}
 	return append(buf, '}'), nil
 }
 
+// marshalFeatureID marshals a feature id, which must be a string or a number.
+func marshalFeatureID(id interface{}) ([]byte, error) {
+	switch reflect.ValueOf(id).Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return json.Marshal(id)
+	}
+	return nil, fmt.Errorf("feature id must be a string or a number, got %T", id)
+}
+
 // Scan scans a feature from well known text.
+// A nil src, i.e. SQL NULL, results in a null geometry.
 func (f *Feature) Scan(src interface{}) error {
+	if src == nil {
+		f.Geometry = nil
+		return nil
+	}
 	return scan(f, src)
 }
 
@@ -63,37 +158,120 @@ func (f *Feature) scan(s string) error {
 	return nil
 }
 
+// UnmarshalWKB unmarshals the feature's geometry from Well Known Binary.
+func (f *Feature) UnmarshalWKB(data []byte) error {
+	geom, err := UnmarshalWKB(data)
+	if err != nil {
+		return err
+	}
+	f.Geometry = geom
+	return nil
+}
+
 // String converts the feature to a WKT string.
+// A null geometry is written as an empty GEOMETRYCOLLECTION.
 func (f Feature) String() string {
+	if f.Geometry == nil {
+		return geometryCollectionWKTTag + " " + emptyWKTTag
+	}
 	return f.Geometry.String()
 }
 
 // feature is a utility type used to unmarshal geojson Feature's.
 type feature struct {
 	Type       string          `json:"type"`
+	ID         json.RawMessage `json:"id"`
 	Geometry   json.RawMessage `json:"geometry"`
 	Properties interface{}     `json:"properties"`
-	BBox       []float64       `json:"bbox"`
+	BBox       json.RawMessage `json:"bbox"`
+
+	Members map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON unmarshals the feature and collects its foreign members.
+func (f *feature) UnmarshalJSON(data []byte) error {
+	type plainFeature feature // Does not have this UnmarshalJSON method.
+
+	if err := json.Unmarshal(data, (*plainFeature)(f)); err != nil {
+		return err
+	}
+	members := map[string]json.RawMessage{}
+
+	// Never fails because data is a valid JSON object.
+	_ = json.Unmarshal(data, &members)
+
+	for key, value := range members {
+		if featureReservedMembers[key] {
+			continue
+		}
+		if f.Members == nil {
+			f.Members = map[string]json.RawMessage{}
+		}
+		f.Members[key] = value
+	}
+	return nil
 }
 
 // ToFeature converts the private feature type to the public one.
 func (f *feature) ToFeature() (*Feature, error) {
-	g := geometry{}
-
-	if err := json.Unmarshal(f.Geometry, &g); err != nil {
+	var (
+		geom Geometry
+		err  error
+	)
+	// The bbox is not kept, but it must still be valid.
+	if _, err = unmarshalBBox(f.BBox); err != nil {
 		return nil, err
 	}
-	// Unmarshal the coordinates into one of our Geometry types.
-	geom, err := g.unmarshalCoordinates()
-	if err != nil {
-		return nil, err
+	if len(f.Geometry) > 0 && string(f.Geometry) != "null" {
+		if geom, err = unmarshalGeometry(f.Geometry); err != nil {
+			return nil, err
+		}
 	}
 	feat := &Feature{}
 	feat.Geometry = geom
 	feat.Properties = f.Properties
+	if feat.ID, err = unmarshalFeatureID(f.ID); err != nil {
+		return nil, err
+	}
+	for key, raw := range f.Members {
+		if feat.Members == nil {
+			feat.Members = map[string]interface{}{}
+		}
+		if feat.Members[key], err = unmarshalJSONValue(raw); err != nil {
+			return nil, err
+		}
+	}
 	return feat, nil
 }
 
+// unmarshalFeatureID unmarshals a feature id, which must be a string or a number.
+// A missing or null id returns nil.
+func unmarshalFeatureID(data json.RawMessage) (interface{}, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	id, err := unmarshalJSONValue(data)
+	if err != nil {
+		return nil, err
+	}
+	switch id.(type) {
+	case nil, string, json.Number:
+		return id, nil
+	}
+	return nil, fmt.Errorf("feature id must be a string or a number, got %s", data)
+}
+
+// unmarshalJSONValue unmarshals any JSON value, keeping numbers as json.Number.
+func unmarshalJSONValue(data json.RawMessage) (interface{}, error) {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
 // UnmarshalJSON unmarshals a feature from JSON.
 func (f *Feature) UnmarshalJSON(data []byte) error {
 	ff, _, err := unmarshalFeature(data)
@@ -105,18 +283,26 @@ func (f *Feature) UnmarshalJSON(data []byte) error {
 }
 
 // Value returns well known text for the feature.
+// A null geometry is returned as SQL NULL.
 func (f Feature) Value() (driver.Value, error) {
+	if f.Geometry == nil {
+		return nil, nil
+	}
 	return f.Geometry.Value()
 }
 
 // Transform transforms the geometry point by point.
 func (f *Feature) Transform(t Transformer) {
-	f.Geometry.Transform(t)
+	if f.Geometry != nil {
+		f.Geometry.Transform(t)
+	}
 }
 
 // VisitCoordinates visits each point in the geometry.
 func (f Feature) VisitCoordinates(v Visitor) {
-	f.Geometry.VisitCoordinates(v)
+	if f.Geometry != nil {
+		f.Geometry.VisitCoordinates(v)
+	}
 }
 
 func unmarshalFeature(data []byte) (*Feature, *feature, error) {
@@ -129,21 +315,10 @@ func unmarshalFeature(data []byte) (*Feature, *feature, error) {
 	if expected, got := FeatureType, feat.Type; expected != got {
 		return nil, nil, fmt.Errorf("expected type %s, got %s", expected, got)
 	}
-
-	g := geometry{}
-
-	if err := json.Unmarshal(feat.Geometry, &g); err != nil {
-		return nil, nil, err
-	}
-
-	// Unmarshal the coordinates into one of our Geometry types.
-	geom, err := g.unmarshalCoordinates()
+	f, err := feat.ToFeature()
 	if err != nil {
 		return nil, nil, err
 	}
-	f := &Feature{}
-	f.Geometry = geom
-	f.Properties = feat.Properties
 	return f, &feat, nil
 }
 
@@ -152,8 +327,5 @@ func unmarshalFeatureBBox(data []byte) (Geometry, error) {
 	if err != nil {
 		return nil, err
 	}
-	if len(ff.BBox) > 0 {
-		return WithBBox(ff.BBox, f), nil
-	}
-	return f, nil
+	return withUnmarshalledBBox(ff.BBox, f)
 }
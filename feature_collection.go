@@ -12,6 +12,8 @@ const (
 )
 
 // FeatureCollection represents a feature collection.
+// Each feature keeps its id and foreign members,
+// but foreign members of the collection itself are not kept.
 type FeatureCollection []*Feature
 
 // Equal compares one feature collection to another.
@@ -31,9 +33,29 @@ func (coll FeatureCollection) Equal(g Geometry) bool {
 	return true
 }
 
-// Contains returns true if every feature in the collection
-// contains the p, and false otherwise.
+// Contains returns true if the point is in any feature of the collection,
+// i.e. the collection is treated as the union of its features.
+// It is the same as ContainsAny.
 func (coll FeatureCollection) Contains(p Point) bool {
+	return coll.ContainsAny(p)
+}
+
+// ContainsAny returns true if any feature in the collection contains the point.
+func (coll FeatureCollection) ContainsAny(p Point) bool {
+	for _, feat := range coll {
+		if feat.Contains(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsAll returns true if every feature in the collection contains the point.
+// An empty collection does not contain any point.
+func (coll FeatureCollection) ContainsAll(p Point) bool {
+	if len(coll) == 0 {
+		return false
+	}
 	for _, feat := range coll {
 		if !feat.Contains(p) {
 			return false
@@ -42,6 +64,26 @@ func (coll FeatureCollection) Contains(p Point) bool {
 	return true
 }
 
+// IsEmpty returns true if every feature in the collection is empty.
+func (coll FeatureCollection) IsEmpty() bool {
+	for _, feat := range coll {
+		if !feat.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// Bounds returns the smallest envelope that contains every feature in the collection.
+// If a feature crosses the antimeridian so may the envelope, see Envelope.Union.
+func (coll FeatureCollection) Bounds() Envelope {
+	env := emptyEnvelope()
+	for _, feat := range coll {
+		env = env.Union(feat.Bounds())
+	}
+	return env
+}
+
 // MarshalJSON marshals the feature collection to geojson.
 func (coll FeatureCollection) MarshalJSON() ([]byte, error) {
 	buf := []byte(featureCollectionJSONPrefix)
@@ -60,24 +102,43 @@ func (coll FeatureCollection) MarshalJSON() ([]byte, error) {
 
 // Scan scans the feature collection from WKT.
 // This method expects a GEOMETRYCOLLECTION.
-// TODO: implement this.
 func (coll *FeatureCollection) Scan(src interface{}) error {
 	return scan(coll, src)
 }
 
 // scan scans the feature collection from WKT.
-// This method expects a GEOMETRYCOLLECTION.
-// TODO: implement this.
+// This method expects a GEOMETRYCOLLECTION,
+// each member of which becomes the geometry of one feature.
 func (coll *FeatureCollection) scan(s string) error {
+	g, _, err := parseWKT(s, geometryCollectionWKTTag)
+	if err != nil {
+		return err
+	}
+	*coll = featuresFrom(*withoutLayout(g).(*GeometryCollection))
+	return nil
+}
+
+// UnmarshalWKB unmarshals the feature collection from Well Known Binary.
+// This method expects a GeometryCollection,
+// each member of which becomes the geometry of one feature.
+func (coll *FeatureCollection) UnmarshalWKB(data []byte) error {
+	g, _, err := unmarshalWKB(data, wkbGeometryCollection)
+	if err != nil {
+		return err
+	}
+	*coll = featuresFrom(*withoutLayout(g).(*GeometryCollection))
 	return nil
 }
 
 // String converts the feature collection to a GEOMETRYCOLLECTION.
 func (coll FeatureCollection) String() string {
+	if len(coll) == 0 {
+		return featureCollectionWKTPrefix + " " + emptyWKTTag
+	}
 	s := featureCollectionWKTPrefix + "("
 	for i, feat := range coll {
 		if i == 0 {
-			s += feat.Geometry.String()
+			s += feat.String()
 		} else {
 			s += ", " + feat.String()
 		}
@@ -87,9 +148,9 @@ func (coll FeatureCollection) String() string {
 
 // featureCollection is a utility type used to unmarshal a geojson FeatureCollection.
 type featureCollection struct {
-	Type     string     `json:"type"`
-	Features []*feature `json:"features"`
-	BBox     []float64  `json:"bbox"`
+	Type     string          `json:"type"`
+	Features []*feature      `json:"features"`
+	BBox     json.RawMessage `json:"bbox"`
 }
 
 func (fc *featureCollection) ToFeatureCollection() (*FeatureCollection, error) {
@@ -139,6 +200,15 @@ func (coll FeatureCollection) VisitCoordinates(v Visitor) {
 	}
 }
 
+// featuresFrom creates a feature for each geometry in a collection.
+func featuresFrom(gc GeometryCollection) FeatureCollection {
+	feats := make([]*Feature, len(gc))
+	for i, geom := range gc {
+		feats[i] = &Feature{Geometry: geom}
+	}
+	return feats
+}
+
 func unmarshalFeatureCollection(data []byte) (*featureCollection, error) {
 	fc := &featureCollection{}
 
@@ -161,8 +231,5 @@ func unmarshalFeatureCollectionBBox(data []byte) (Geometry, error) {
 	if err != nil {
 		return nil, err
 	}
-	if len(fc.BBox) > 0 {
-		return WithBBox(fc.BBox, coll), nil
-	}
-	return coll, nil
+	return withUnmarshalledBBox(fc.BBox, coll)
 }
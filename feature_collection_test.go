@@ -43,6 +43,61 @@ func TestFeatureCollection(t *testing.T) {
 			{12, 12},
 		},
 	}.test(t)
+
+	// Contains any feature
+	cases{
+		G: &FeatureCollection{
+			{Geometry: &Polygon{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}}},
+			{},
+			{Geometry: &Polygon{{{10, 10}, {12, 10}, {12, 12}, {10, 12}, {10, 10}}}},
+		},
+		Inside: []Point{
+			{1, 1},
+			{11, 11},
+		},
+		Outside: []Point{
+			{5, 5},
+		},
+	}.test(t)
+	cases{
+		G: &FeatureCollection{},
+		Outside: []Point{
+			{0, 0},
+		},
+	}.test(t)
+}
+
+func TestFeatureCollectionContainsAnyAll(t *testing.T) {
+	coll := FeatureCollection{
+		{Geometry: &Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}}},
+		{Geometry: &Polygon{{{2, 2}, {6, 2}, {6, 6}, {2, 6}, {2, 2}}}},
+	}
+	for i, testcase := range []struct {
+		Point Point
+		Any   bool
+		All   bool
+	}{
+		{Point: Point{1, 1}, Any: true},
+		{Point: Point{3, 3}, Any: true, All: true},
+		{Point: Point{5, 5}, Any: true},
+		{Point: Point{7, 7}},
+	} {
+		if expected, got := testcase.Any, coll.ContainsAny(testcase.Point); expected != got {
+			t.Fatalf("(case %d) expected ContainsAny to be %t, got %t", i, expected, got)
+		}
+		if expected, got := testcase.All, coll.ContainsAll(testcase.Point); expected != got {
+			t.Fatalf("(case %d) expected ContainsAll to be %t, got %t", i, expected, got)
+		}
+	}
+	if (FeatureCollection{}).ContainsAll(Point{0, 0}) {
+		t.Fatal("expected an empty FeatureCollection to not contain anything")
+	}
+
+	// A feature with a null geometry contains nothing.
+	coll = append(coll, &Feature{})
+	if coll.ContainsAll(Point{3, 3}) {
+		t.Fatal("expected a feature with a null geometry to not contain the point")
+	}
 }
 
 func TestFeatureCollectionMarshal(t *testing.T) {
@@ -56,6 +56,21 @@ func TestFeatureMarshal(t *testing.T) {
 			},
 			Expected: `{"type":"Feature","geometry":{"type":"LineString","coordinates":[[0,0],[1,2]]},"properties":null}`,
 		},
+		{
+			Feature: Feature{
+				ID:       "a1",
+				Geometry: &Point{1, 2},
+				Members:  map[string]interface{}{"title": "Example", "id": "ignored", "rank": 2},
+			},
+			Expected: `{"type":"Feature","id":"a1","geometry":{"type":"Point","coordinates":[1,2]},"properties":null,"rank":2,"title":"Example"}`,
+		},
+		{
+			Feature: Feature{
+				ID:       json.Number("9007199254740993"),
+				Geometry: &Point{1, 2},
+			},
+			Expected: `{"type":"Feature","id":9007199254740993,"geometry":{"type":"Point","coordinates":[1,2]},"properties":null}`,
+		},
 	} {
 		got, err := testcase.Feature.MarshalJSON()
 		if err != nil {
@@ -72,6 +87,8 @@ func TestFeatureMarshal(t *testing.T) {
 	}{
 		{Feature: Feature{Geometry: badGeom{}}},
 		{Feature: Feature{Geometry: &Point{1, 2}, Properties: badGeom{}}},
+		{Feature: Feature{Geometry: &Point{1, 2}, ID: []int{1}}},
+		{Feature: Feature{Geometry: &Point{1, 2}, Members: map[string]interface{}{"x": badGeom{}}}},
 	} {
 
 		if _, err := testcase.Feature.MarshalJSON(); err == nil {
@@ -282,3 +299,116 @@ func TestFeatureValue(t *testing.T) {
 		},
 	}.pass(t)
 }
+
+func TestFeatureIDAndMembers(t *testing.T) {
+	input := `{"type":"FeatureCollection","features":[` +
+		`{"type":"Feature","id":9007199254740993,"geometry":{"type":"Point","coordinates":[1,2]},"properties":null,"title":"First","extra":{"a":[1.5]}},` +
+		`{"type":"Feature","id":"b","geometry":{"type":"Point","coordinates":[3,4]},"properties":null}]}`
+
+	coll := &FeatureCollection{}
+	if err := json.Unmarshal([]byte(input), coll); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := json.Number("9007199254740993"), (*coll)[0].ID; expected != got {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	if expected, got := "b", (*coll)[1].ID; expected != got {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	if expected, got := "First", (*coll)[0].Members["title"]; expected != got {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	if (*coll)[1].Members != nil {
+		t.Fatalf("expected no members, got %v", (*coll)[1].Members)
+	}
+
+	// Round trip
+	data, err := json.Marshal(coll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := `{"type":"FeatureCollection","features":[`+
+		`{"type":"Feature","id":9007199254740993,"geometry":{"type":"Point","coordinates":[1,2]},"properties":null,"extra":{"a":[1.5]},"title":"First"},`+
+		`{"type":"Feature","id":"b","geometry":{"type":"Point","coordinates":[3,4]},"properties":null}]}`, string(data); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+
+	// Fail
+	for i, input := range []string{
+		`{"type":"Feature","id":true,"geometry":{"type":"Point","coordinates":[1,2]}}`,
+		`{"type":"Feature","id":{"a":1},"geometry":{"type":"Point","coordinates":[1,2]}}`,
+	} {
+		if err := (&Feature{}).UnmarshalJSON([]byte(input)); err == nil {
+			t.Fatalf("(case %d) expected error, got nil", i)
+		}
+	}
+}
+
+func TestFeatureNullGeometry(t *testing.T) {
+	input := `{"type":"Feature","id":1,"geometry":null,"properties":{"name":"nowhere"}}`
+	f := &Feature{}
+	if err := json.Unmarshal([]byte(input), f); err != nil {
+		t.Fatal(err)
+	}
+	if f.Geometry != nil {
+		t.Fatalf("expected null geometry, got %s", f.Geometry)
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := input, string(data); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if !f.IsEmpty() {
+		t.Fatal("expected feature to be empty")
+	}
+	if f.Contains(Point{0, 0}) {
+		t.Fatal("expected feature to contain nothing")
+	}
+	if expected, got := `GEOMETRYCOLLECTION EMPTY`, f.String(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if v, err := f.Value(); err != nil || v != nil {
+		t.Fatalf("expected nil value, got %v (%v)", v, err)
+	}
+	f.Transform(pointShifter(1))
+	f.VisitCoordinates(&quadrants{})
+
+	cases{
+		G:         &Feature{},
+		Same:      []Geometry{&Feature{}},
+		Different: []Geometry{&Feature{Geometry: &Point{1, 1}}},
+	}.test(t)
+	if (&Feature{Geometry: &Point{1, 1}}).Equal(&Feature{}) {
+		t.Fatal("expected features to be different")
+	}
+
+	// Scanning SQL NULL
+	f = &Feature{Geometry: &Point{1, 1}}
+	if err := f.Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if f.Geometry != nil {
+		t.Fatalf("expected null geometry, got %s", f.Geometry)
+	}
+
+	// In a collection
+	coll := &FeatureCollection{{Geometry: &Point{1, 2}}, {}}
+	if data, err = json.Marshal(coll); err != nil {
+		t.Fatal(err)
+	}
+	got := &FeatureCollection{}
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	if !coll.Equal(got) {
+		t.Fatalf("expected %s, got %s", coll, got)
+	}
+	if expected, got := `GEOMETRYCOLLECTION(POINT(1 2), GEOMETRYCOLLECTION EMPTY)`, coll.String(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if coll.IsEmpty() {
+		t.Fatal("expected collection not to be empty")
+	}
+}
@@ -147,6 +147,16 @@ func (badgeom badGeom) Contains(p Point) bool {
 	return false
 }
 
+// IsEmpty always returns false.
+func (badgeom badGeom) IsEmpty() bool {
+	return false
+}
+
+// Bounds always returns an empty envelope.
+func (badgeom badGeom) Bounds() Envelope {
+	return emptyEnvelope()
+}
+
 // MarshalJSON always returns an error.
 func (badgeom badGeom) MarshalJSON() ([]byte, error) {
 	return nil, errors.New("bad geom")
@@ -0,0 +1,1176 @@
+package geo
+
+import "math"
+
+// The geodesic calculations in this file follow Charles F. F. Karney,
+// "Algorithms for geodesics", J. Geodesy 87, 43-55 (2013),
+// https://doi.org/10.1007/s00190-012-0578-z, and are a port of the
+// corresponding parts of GeographicLib (https://geographiclib.sourceforge.io),
+// which is available under the MIT/X11 License.
+// They are accurate to about 15 nanometers on the WGS84 ellipsoid.
+
+const (
+	geodesicOrder = 6
+	nA1           = geodesicOrder
+	nC1           = geodesicOrder
+	nC1p          = geodesicOrder
+	nA2           = geodesicOrder
+	nC2           = geodesicOrder
+	nA3           = geodesicOrder
+	nA3x          = nA3
+	nC3           = geodesicOrder
+	nC3x          = (nC3 * (nC3 - 1)) / 2
+	nC4           = geodesicOrder
+	nC4x          = (nC4 * (nC4 + 1)) / 2
+
+	geodesicMaxit1 = 20
+	geodesicMaxit2 = geodesicMaxit1 + 53 + 10
+)
+
+var (
+	geodesicTiny    = math.Sqrt(math.SmallestNonzeroFloat64 * (1 << 52))
+	geodesicTol0    = 2 * epsilon
+	geodesicTol1    = 200 * geodesicTol0
+	geodesicTol2    = math.Sqrt(geodesicTol0)
+	geodesicTolb    = geodesicTol0 * geodesicTol2
+	geodesicXthresh = 1000 * geodesicTol2
+)
+
+// Geodesic solves problems about geodesics, the shortest paths between
+// points on an ellipsoid of revolution.
+// Latitudes and longitudes are in degrees, with positions given as points
+// whose X is the longitude and Y is the latitude,
+// azimuths are in degrees clockwise from north,
+// and distances and areas are in the units of the equatorial radius,
+// or their squares.
+type Geodesic struct {
+	a, f, f1, e2, ep2, n, b, c2, etol2 float64
+
+	a3x [nA3x]float64
+	c3x [nC3x]float64
+	c4x [nC4x]float64
+}
+
+// WGS84 is the ellipsoid of the World Geodetic System 1984, in meters.
+var WGS84 = NewGeodesic(6378137, 1/298.257223563)
+
+// NewGeodesic returns a Geodesic for the ellipsoid with the equatorial radius a
+// and the flattening f.
+func NewGeodesic(a, f float64) *Geodesic {
+	g := &Geodesic{a: a, f: f}
+	g.f1 = 1 - f
+	g.e2 = f * (2 - f)
+	g.ep2 = g.e2 / sq(g.f1)
+	g.n = f / (2 - f)
+	g.b = a * g.f1
+
+	// The authalic radius squared.
+	switch {
+	case g.e2 == 0:
+		g.c2 = (sq(a) + sq(g.b)) / 2
+	case g.e2 > 0:
+		g.c2 = (sq(a) + sq(g.b)*math.Atanh(math.Sqrt(g.e2))/math.Sqrt(g.e2)) / 2
+	default:
+		g.c2 = (sq(a) + sq(g.b)*math.Atan(math.Sqrt(-g.e2))/math.Sqrt(-g.e2)) / 2
+	}
+	g.etol2 = 0.1 * geodesicTol2 / math.Sqrt(math.Max(0.001, math.Abs(f))*math.Min(1, 1-f/2)/2)
+	g.a3coeff()
+	g.c3coeff()
+	g.c4coeff()
+	return g
+}
+
+// Inverse returns the length of the shortest geodesic between two points,
+// and its azimuths at the first and the second point.
+func (g *Geodesic) Inverse(lat1, lon1, lat2, lon2 float64) (s12, azi1, azi2 float64) {
+	r := g.inverse(lat1, lon1, lat2, lon2, false)
+	return r.s12, atan2d(r.salp1, r.calp1), atan2d(r.salp2, r.calp2)
+}
+
+// Direct returns the point at distance s12 from a point along the geodesic
+// that leaves it at the azimuth azi1, and the azimuth of the geodesic there.
+// The longitude is in the range [-180, 180].
+func (g *Geodesic) Direct(lat1, lon1, azi1, s12 float64) (lat2, lon2, azi2 float64) {
+	return newGeodesicLine(g, lat1, lon1, azi1).position(s12)
+}
+
+// pointDistance returns the length of the shortest geodesic between two points.
+func (g *Geodesic) pointDistance(a, b [4]float64) float64 {
+	return g.inverse(a[1], a[0], b[1], b[0], false).s12
+}
+
+// Area returns the area of the polygons in a geometry.
+// The area of a polygon is the area of its exterior ring minus the areas of its holes.
+// The area of a ring is the area of the smaller of the two regions that it bounds,
+// whichever way it is wound.
+// The area of a Circle is the area of a spherical cap, since Circle is defined on a sphere.
+// The area of a collection is the sum of the areas of its members,
+// and points and lines have no area.
+func (g *Geodesic) Area(geom Geometry) float64 {
+	area := 0.0
+	visitMeasurable(geom, func(geom Geometry) {
+		switch v := geom.(type) {
+		case *Polygon:
+			area += g.polygonArea(*v)
+		case *MultiPolygon:
+			for _, poly := range *v {
+				area += g.polygonArea(poly)
+			}
+		case *Circle:
+			if !v.IsEmpty() {
+				area += 2 * math.Pi * sq(earthRadiusMeters) * (1 - math.Cos(v.radiusMeters()/earthRadiusMeters))
+			}
+		}
+	})
+	return area
+}
+
+// Length returns the length of the lines in a geometry.
+// The length of a collection is the sum of the lengths of its members,
+// and points and polygons have no length, see Perimeter.
+func (g *Geodesic) Length(geom Geometry) float64 {
+	length := 0.0
+	visitMeasurable(geom, func(geom Geometry) {
+		switch v := geom.(type) {
+		case *Line:
+			length += g.lineLength(*v, false)
+		case *MultiLine:
+			for _, line := range *v {
+				length += g.lineLength(line, false)
+			}
+		}
+	})
+	return length
+}
+
+// Perimeter returns the length of the boundaries of the polygons in a geometry,
+// including the boundaries of their holes.
+// The perimeter of a Circle is the circumference of a spherical cap.
+// The perimeter of a collection is the sum of the perimeters of its members,
+// and points and lines have no perimeter.
+func (g *Geodesic) Perimeter(geom Geometry) float64 {
+	perimeter := 0.0
+	visitMeasurable(geom, func(geom Geometry) {
+		switch v := geom.(type) {
+		case *Polygon:
+			for _, ring := range *v {
+				perimeter += g.lineLength(ring, true)
+			}
+		case *MultiPolygon:
+			for _, poly := range *v {
+				for _, ring := range poly {
+					perimeter += g.lineLength(ring, true)
+				}
+			}
+		case *Circle:
+			if !v.IsEmpty() {
+				perimeter += 2 * math.Pi * earthRadiusMeters * math.Sin(v.radiusMeters()/earthRadiusMeters)
+			}
+		}
+	})
+	return perimeter
+}
+
+// lineLength returns the length of a line, closing it first if it is a ring.
+func (g *Geodesic) lineLength(line [][4]float64, ring bool) float64 {
+	var length accumulator
+	for i := 1; i < len(line); i++ {
+		length.add(g.pointDistance(line[i-1], line[i]))
+	}
+	if ring && len(line) > 1 {
+		length.add(g.pointDistance(line[len(line)-1], line[0]))
+	}
+	return length.sum()
+}
+
+// polygonArea returns the area of the exterior ring of a polygon minus the areas of its holes.
+func (g *Geodesic) polygonArea(poly [][][4]float64) float64 {
+	area := 0.0
+	for i, ring := range poly {
+		a := math.Abs(g.ringArea(ring))
+		if i == 0 {
+			area += a
+		} else {
+			area -= a
+		}
+	}
+	return area
+}
+
+// ringArea returns the signed area of a ring, which is closed if its last point
+// is not its first.
+// The area is positive if the ring is counterclockwise, and its magnitude is
+// at most half of the area of the ellipsoid.
+func (g *Geodesic) ringArea(ring [][4]float64) float64 {
+	if len(ring) < 3 {
+		return 0
+	}
+	var (
+		area      accumulator
+		crossings int
+	)
+	for i := range ring {
+		a, b := ring[i], ring[(i+1)%len(ring)]
+		area.add(g.inverse(a[1], a[0], b[1], b[0], true).S12)
+		crossings += transit(a[0], b[0])
+	}
+	area0 := 4 * math.Pi * g.c2
+	area.remainder(area0)
+	if crossings&1 != 0 {
+		if area.sum() < 0 {
+			area.add(area0 / 2)
+		} else {
+			area.add(-area0 / 2)
+		}
+	}
+	// The sum is positive for clockwise rings, so negate it.
+	area.negate()
+	switch {
+	case area.sum() > area0/2:
+		area.add(-area0)
+	case area.sum() <= -area0/2:
+		area.add(area0)
+	}
+	return 0 + area.sum()
+}
+
+// transit returns 1 or -1 if the edge from lon1 to lon2 crosses the prime meridian
+// going east or west, and 0 otherwise.
+func transit(lon1, lon2 float64) int {
+	lon12, _ := angDiff(lon1, lon2)
+	lon1 = angNormalize(lon1)
+	lon2 = angNormalize(lon2)
+	switch {
+	case lon12 > 0 && ((lon1 < 0 && lon2 >= 0) || (lon1 > 0 && lon2 == 0)):
+		return 1
+	case lon12 < 0 && lon1 >= 0 && lon2 < 0:
+		return -1
+	}
+	return 0
+}
+
+// inverseResult holds the solution of the inverse problem,
+// with the azimuths as their sines and cosines.
+type inverseResult struct {
+	s12, a12                 float64
+	salp1, calp1             float64
+	salp2, calp2             float64
+	m12, M12, M21, S12       float64
+	sig12, ssig1, csig1, eps float64
+}
+
+// inverse solves the inverse problem, computing the area between the geodesic
+// and the equator if area is true.
+func (g *Geodesic) inverse(lat1, lon1, lat2, lon2 float64, area bool) inverseResult {
+	var r inverseResult
+
+	// Make longitude difference positive.
+	lon12, lon12s := angDiff(lon1, lon2)
+	lonsign := math.Copysign(1, lon12)
+	lon12 = lonsign * angRound(lon12)
+	lon12s = angRound((180 - lon12) - lonsign*lon12s)
+	lam12 := toRadians(lon12)
+	var slam12, clam12 float64
+	if lon12 > 90 {
+		slam12, clam12 = sincosd(lon12s)
+		clam12 = -clam12
+	} else {
+		slam12, clam12 = sincosd(lon12)
+	}
+
+	// Swap points so that the first point has the larger absolute latitude,
+	// and make its latitude negative.
+	lat1 = angRound(latFix(lat1))
+	lat2 = angRound(latFix(lat2))
+	swapp := 1.0
+	if math.Abs(lat1) < math.Abs(lat2) || math.IsNaN(lat2) {
+		swapp = -1
+		lonsign *= -1
+		lat1, lat2 = lat2, lat1
+	}
+	latsign := math.Copysign(1, -lat1)
+	lat1 *= latsign
+	lat2 *= latsign
+
+	// Reduced latitudes.
+	sbet1, cbet1 := sincosd(lat1)
+	sbet1, cbet1 = norm(g.f1*sbet1, cbet1)
+	cbet1 = math.Max(geodesicTiny, cbet1)
+	sbet2, cbet2 := sincosd(lat2)
+	sbet2, cbet2 = norm(g.f1*sbet2, cbet2)
+	cbet2 = math.Max(geodesicTiny, cbet2)
+	if cbet1 < -sbet1 {
+		if cbet2 == cbet1 {
+			sbet2 = math.Copysign(sbet1, sbet2)
+		}
+	} else if math.Abs(sbet2) == -sbet1 {
+		cbet2 = cbet1
+	}
+	dn1 := math.Sqrt(1 + g.ep2*sq(sbet1))
+	dn2 := math.Sqrt(1 + g.ep2*sq(sbet2))
+
+	var (
+		c1a, c2a       [nC1 + 1]float64
+		c3a            [nC3]float64
+		salp1, calp1   float64
+		salp2, calp2   float64
+		sig12, a12     float64
+		s12x, m12x     float64
+		ssig1, csig1   float64
+		ssig2, csig2   float64
+		eps, domg12    float64
+		omg12          float64
+		somg12, comg12 = 2.0, 0.0
+		meridian       = lat1 == -90 || slam12 == 0
+		M12, M21       float64
+	)
+
+	if meridian {
+		// The geodesic runs along a meridian.
+		calp1, salp1 = clam12, slam12
+		calp2, salp2 = 1, 0
+		ssig1, csig1 = sbet1, calp1*cbet1
+		ssig2, csig2 = sbet2, calp2*cbet2
+		sig12 = math.Atan2(math.Max(0, csig1*ssig2-ssig1*csig2), csig1*csig2+ssig1*ssig2)
+		s12x, m12x, _, M12, M21 = g.lengths(g.n, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, cbet1, cbet2, c1a[:], c2a[:])
+		if sig12 < 1 || m12x >= 0 {
+			if sig12 < 3*geodesicTiny || (sig12 < geodesicTol0 && (s12x < 0 || m12x < 0)) {
+				sig12, m12x, s12x = 0, 0, 0
+			}
+			m12x *= g.b
+			s12x *= g.b
+			a12 = toDegrees(sig12)
+		} else {
+			// The shortest path is not along the meridian.
+			meridian = false
+		}
+	}
+
+	switch {
+	case !meridian && sbet1 == 0 && (g.f <= 0 || lon12s >= g.f*180):
+		// The geodesic runs along the equator.
+		calp1, calp2 = 0, 0
+		salp1, salp2 = 1, 1
+		s12x = g.a * lam12
+		sig12 = lam12 / g.f1
+		omg12 = sig12
+		m12x = g.b * math.Sin(sig12)
+		M12 = math.Cos(sig12)
+		M21 = M12
+		a12 = lon12 / g.f1
+	case !meridian:
+		var dnm float64
+		sig12, salp1, calp1, salp2, calp2, dnm = g.inverseStart(sbet1, cbet1, dn1, sbet2, cbet2, dn2, lam12, slam12, clam12, c1a[:], c2a[:])
+		if sig12 >= 0 {
+			// A short line on a sphere.
+			s12x = sig12 * g.b * dnm
+			m12x = sq(dnm) * g.b * math.Sin(sig12/dnm)
+			M12 = math.Cos(sig12 / dnm)
+			M21 = M12
+			a12 = toDegrees(sig12)
+			omg12 = lam12 / (g.f1 * dnm)
+		} else {
+			// Solve for the azimuth at the first point with Newton's method,
+			// falling back on bisection.
+			var (
+				numit        int
+				tripn, tripb bool
+				salp1a       = geodesicTiny
+				calp1a       = 1.0
+				salp1b       = geodesicTiny
+				calp1b       = -1.0
+				v, dv        float64
+			)
+			for ; numit < geodesicMaxit2; numit++ {
+				v, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dv = g.lambda12(
+					sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1, slam12, clam12,
+					numit < geodesicMaxit1, c1a[:], c2a[:], c3a[:])
+				tol := geodesicTol0
+				if tripn {
+					tol *= 8
+				}
+				if tripb || !(math.Abs(v) >= tol) {
+					break
+				}
+				if v > 0 && (numit > geodesicMaxit1 || calp1/salp1 > calp1b/salp1b) {
+					salp1b, calp1b = salp1, calp1
+				} else if v < 0 && (numit > geodesicMaxit1 || calp1/salp1 < calp1a/salp1a) {
+					salp1a, calp1a = salp1, calp1
+				}
+				if numit < geodesicMaxit1 && dv > 0 {
+					dalp1 := -v / dv
+					if math.Abs(dalp1) < math.Pi {
+						sdalp1, cdalp1 := math.Sin(dalp1), math.Cos(dalp1)
+						if nsalp1 := salp1*cdalp1 + calp1*sdalp1; nsalp1 > 0 {
+							calp1 = calp1*cdalp1 - salp1*sdalp1
+							salp1, calp1 = norm(nsalp1, calp1)
+							tripn = math.Abs(v) <= 16*geodesicTol0
+							continue
+						}
+					}
+				}
+				salp1, calp1 = norm((salp1a+salp1b)/2, (calp1a+calp1b)/2)
+				tripn = false
+				tripb = math.Abs(salp1a-salp1)+(calp1a-calp1) < geodesicTolb ||
+					math.Abs(salp1-salp1b)+(calp1-calp1b) < geodesicTolb
+			}
+			s12x, m12x, _, M12, M21 = g.lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, cbet1, cbet2, c1a[:], c2a[:])
+			m12x *= g.b
+			s12x *= g.b
+			a12 = toDegrees(sig12)
+			if area {
+				sdomg12, cdomg12 := math.Sin(domg12), math.Cos(domg12)
+				somg12 = slam12*cdomg12 - clam12*sdomg12
+				comg12 = clam12*cdomg12 + slam12*sdomg12
+			}
+		}
+	}
+	r.s12 = 0 + s12x
+	r.m12 = 0 + m12x
+	r.a12 = a12
+
+	if area {
+		salp0 := salp1 * cbet1
+		calp0 := math.Hypot(calp1, salp1*sbet1)
+		if calp0 != 0 && salp0 != 0 {
+			ssig1, csig1 = norm(sbet1, calp1*cbet1)
+			ssig2, csig2 = norm(sbet2, calp2*cbet2)
+			k2 := sq(calp0) * g.ep2
+			eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+			a4 := sq(g.a) * calp0 * salp0 * g.e2
+			var c4a [nC4]float64
+			g.c4f(eps, c4a[:])
+			b41 := sinCosSeries(false, ssig1, csig1, c4a[:])
+			b42 := sinCosSeries(false, ssig2, csig2, c4a[:])
+			r.S12 = a4 * (b42 - b41)
+		}
+		if !meridian && somg12 > 1 {
+			somg12, comg12 = math.Sin(omg12), math.Cos(omg12)
+		}
+		var alp12 float64
+		if !meridian && comg12 > -0.7071 && sbet2-sbet1 < 1.75 {
+			// Use the tan(Gamma/2) = tan(omg12/2) * (tan(bet1/2) + tan(bet2/2)) /
+			// (1 + tan(bet1/2) * tan(bet2/2)) formula, with tan(x/2) = sin(x)/(1+cos(x)).
+			domg12 := 1 + comg12
+			dbet1 := 1 + cbet1
+			dbet2 := 1 + cbet2
+			alp12 = 2 * math.Atan2(somg12*(sbet1*dbet2+sbet2*dbet1), domg12*(sbet1*sbet2+dbet1*dbet2))
+		} else {
+			salp12 := salp2*calp1 - calp2*salp1
+			calp12 := calp2*calp1 + salp2*salp1
+			if salp12 == 0 && calp12 < 0 {
+				salp12 = geodesicTiny * calp1
+				calp12 = -1
+			}
+			alp12 = math.Atan2(salp12, calp12)
+		}
+		r.S12 += g.c2 * alp12
+		r.S12 *= swapp * lonsign * latsign
+		r.S12 += 0
+	}
+
+	if swapp < 0 {
+		salp1, salp2 = salp2, salp1
+		calp1, calp2 = calp2, calp1
+		M12, M21 = M21, M12
+	}
+	r.salp1, r.calp1 = salp1*swapp*lonsign, calp1*swapp*latsign
+	r.salp2, r.calp2 = salp2*swapp*lonsign, calp2*swapp*latsign
+	r.M12, r.M21 = M12, M21
+	return r
+}
+
+// inverseStart returns a starting point for Newton's method in inverse.
+// If sig12 is not negative the solution has already been found,
+// since the points are close together and nearly on a sphere.
+func (g *Geodesic) inverseStart(sbet1, cbet1, dn1, sbet2, cbet2, dn2, lam12, slam12, clam12 float64, c1a, c2a []float64) (sig12, salp1, calp1, salp2, calp2, dnm float64) {
+	sig12 = -1
+	salp2, calp2, dnm = math.NaN(), math.NaN(), math.NaN()
+	var (
+		sbet12    = sbet2*cbet1 - cbet2*sbet1
+		cbet12    = cbet2*cbet1 + sbet2*sbet1
+		sbet12a   = sbet2*cbet1 + cbet2*sbet1
+		shortline = cbet12 >= 0 && sbet12 < 0.5 && cbet2*lam12 < 0.5
+		somg12    float64
+		comg12    float64
+	)
+	if shortline {
+		sbetm2 := sq(sbet1 + sbet2)
+		sbetm2 /= sbetm2 + sq(cbet1+cbet2)
+		dnm = math.Sqrt(1 + g.ep2*sbetm2)
+		omg12 := lam12 / (g.f1 * dnm)
+		somg12, comg12 = math.Sin(omg12), math.Cos(omg12)
+	} else {
+		somg12, comg12 = slam12, clam12
+	}
+	salp1 = cbet2 * somg12
+	if comg12 >= 0 {
+		calp1 = sbet12 + cbet2*sbet1*sq(somg12)/(1+comg12)
+	} else {
+		calp1 = sbet12a - cbet2*sbet1*sq(somg12)/(1-comg12)
+	}
+	ssig12 := math.Hypot(salp1, calp1)
+	csig12 := sbet1*sbet2 + cbet1*cbet2*comg12
+
+	switch {
+	case shortline && ssig12 < g.etol2:
+		// Really short lines.
+		salp2 = cbet1 * somg12
+		if comg12 >= 0 {
+			calp2 = sbet12 - cbet1*sbet2*sq(somg12)/(1+comg12)
+		} else {
+			calp2 = sbet12 - cbet1*sbet2*(1-comg12)
+		}
+		salp2, calp2 = norm(salp2, calp2)
+		sig12 = math.Atan2(ssig12, csig12)
+	case math.Abs(g.n) >= 0.1 || csig12 >= 0 || ssig12 >= 6*math.Abs(g.n)*math.Pi*sq(cbet1):
+		// Nothing to do, the zeroth order spherical approximation is good enough.
+	default:
+		// The points are nearly antipodal, so scale to the astroid problem.
+		var (
+			lam12x             = math.Atan2(-slam12, -clam12)
+			x, y               float64
+			lamscale, betscale float64
+		)
+		if g.f >= 0 {
+			k2 := sq(sbet1) * g.ep2
+			eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+			lamscale = g.f * cbet1 * g.a3f(eps) * math.Pi
+			betscale = lamscale * cbet1
+			x = lam12x / lamscale
+			y = sbet12a / betscale
+		} else {
+			cbet12a := cbet2*cbet1 - sbet2*sbet1
+			bet12a := math.Atan2(sbet12a, cbet12a)
+			_, m12b, m0, _, _ := g.lengths(g.n, math.Pi+bet12a, sbet1, -cbet1, dn1, sbet2, cbet2, dn2, cbet1, cbet2, c1a, c2a)
+			x = -1 + m12b/(cbet1*cbet2*m0*math.Pi)
+			if x < -0.01 {
+				betscale = sbet12a / x
+			} else {
+				betscale = -g.f * sq(cbet1) * math.Pi
+			}
+			lamscale = betscale / cbet1
+			y = lam12x / lamscale
+		}
+		if y > -geodesicTol1 && x > -1-geodesicXthresh {
+			if g.f >= 0 {
+				salp1 = math.Min(1, -x)
+				calp1 = -math.Sqrt(1 - sq(salp1))
+			} else {
+				if x > -geodesicTol1 {
+					calp1 = math.Max(0, x)
+				} else {
+					calp1 = math.Max(-1, x)
+				}
+				salp1 = math.Sqrt(1 - sq(calp1))
+			}
+		} else {
+			k := astroid(x, y)
+			var omg12a float64
+			if g.f >= 0 {
+				omg12a = lamscale * (-x * k / (1 + k))
+			} else {
+				omg12a = lamscale * (-y * (1 + k) / k)
+			}
+			somg12, comg12 = math.Sin(omg12a), -math.Cos(omg12a)
+			salp1 = cbet2 * somg12
+			calp1 = sbet12a - cbet2*sbet1*sq(somg12)/(1-comg12)
+		}
+	}
+	if !(salp1 <= 0) {
+		salp1, calp1 = norm(salp1, calp1)
+	} else {
+		salp1, calp1 = 1, 0
+	}
+	return sig12, salp1, calp1, salp2, calp2, dnm
+}
+
+// lambda12 returns the longitude difference of the geodesic that leaves the first
+// point at the given azimuth when it reaches the latitude of the second point,
+// minus the longitude difference between the points, and its derivative if diffp is true.
+func (g *Geodesic) lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1, slam120, clam120 float64, diffp bool, c1a, c2a, c3a []float64) (lam12, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dlam12 float64) {
+	if sbet1 == 0 && calp1 == 0 {
+		// Break the degeneracy of equatorial lines.
+		calp1 = -geodesicTiny
+	}
+	salp0 := salp1 * cbet1
+	calp0 := math.Hypot(calp1, salp1*sbet1)
+
+	ssig1 = sbet1
+	somg1 := salp0 * sbet1
+	csig1 = calp1 * cbet1
+	comg1 := csig1
+	ssig1, csig1 = norm(ssig1, csig1)
+
+	if cbet2 != cbet1 {
+		salp2 = salp0 / cbet2
+	} else {
+		salp2 = salp1
+	}
+	if cbet2 != cbet1 || math.Abs(sbet2) != -sbet1 {
+		var t float64
+		if cbet1 < -sbet1 {
+			t = (cbet2 - cbet1) * (cbet1 + cbet2)
+		} else {
+			t = (sbet1 - sbet2) * (sbet1 + sbet2)
+		}
+		calp2 = math.Sqrt(sq(calp1*cbet1)+t) / cbet2
+	} else {
+		calp2 = math.Abs(calp1)
+	}
+
+	ssig2 = sbet2
+	somg2 := salp0 * sbet2
+	csig2 = calp2 * cbet2
+	comg2 := csig2
+	ssig2, csig2 = norm(ssig2, csig2)
+
+	sig12 = math.Atan2(math.Max(0, csig1*ssig2-ssig1*csig2), csig1*csig2+ssig1*ssig2)
+	somg12 := math.Max(0, comg1*somg2-somg1*comg2)
+	comg12 := comg1*comg2 + somg1*somg2
+	eta := math.Atan2(somg12*clam120-comg12*slam120, comg12*clam120+somg12*slam120)
+
+	k2 := sq(calp0) * g.ep2
+	eps = k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+	g.c3f(eps, c3a)
+	b312 := sinCosSeries(true, ssig2, csig2, c3a) - sinCosSeries(true, ssig1, csig1, c3a)
+	domg12 = -g.f * g.a3f(eps) * salp0 * (sig12 + b312)
+	lam12 = eta + domg12
+
+	if diffp {
+		if calp2 == 0 {
+			dlam12 = -2 * g.f1 * dn1 / sbet1
+		} else {
+			_, dlam12, _, _, _ = g.lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, cbet1, cbet2, c1a, c2a)
+			dlam12 *= g.f1 / (calp2 * cbet2)
+		}
+	} else {
+		dlam12 = math.NaN()
+	}
+	return lam12, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dlam12
+}
+
+// lengths returns the distance s12b and the reduced length m12b, both divided by b,
+// the value m0 of the reduced length for a full circle, and the geodesic scales M12 and M21.
+func (g *Geodesic) lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, cbet1, cbet2 float64, c1a, c2a []float64) (s12b, m12b, m0, M12, M21 float64) {
+	a1 := a1m1f(eps)
+	c1f(eps, c1a)
+	a2 := a2m1f(eps)
+	c2f(eps, c2a)
+	m0x := a1 - a2
+	a1++
+	a2++
+
+	b1 := sinCosSeries(true, ssig2, csig2, c1a) - sinCosSeries(true, ssig1, csig1, c1a)
+	s12b = a1 * (sig12 + b1)
+	b2 := sinCosSeries(true, ssig2, csig2, c2a) - sinCosSeries(true, ssig1, csig1, c2a)
+	j12 := m0x*sig12 + (a1*b1 - a2*b2)
+
+	m0 = m0x
+	// Missing a factor of b.
+	m12b = dn2*(csig1*ssig2) - dn1*(ssig1*csig2) - csig1*csig2*j12
+
+	csig12 := csig1*csig2 + ssig1*ssig2
+	t := g.ep2 * (cbet1 - cbet2) * (cbet1 + cbet2) / (dn1 + dn2)
+	M12 = csig12 + (t*ssig2-csig2*j12)*ssig1/dn1
+	M21 = csig12 - (t*ssig1-csig1*j12)*ssig2/dn2
+	return s12b, m12b, m0, M12, M21
+}
+
+// geodesicLine is a geodesic that leaves a point at a given azimuth.
+type geodesicLine struct {
+	g *Geodesic
+
+	lat1, lon1                  float64
+	salp0, calp0                float64
+	ssig1, csig1, somg1, comg1  float64
+	k2, a1m1, b11, stau1, ctau1 float64
+	a3c, b31                    float64
+	c1a, c1pa                   [nC1 + 1]float64
+	c3a                         [nC3]float64
+}
+
+// newGeodesicLine returns the geodesic that leaves (lat1, lon1) at the azimuth azi1.
+func newGeodesicLine(g *Geodesic, lat1, lon1, azi1 float64) *geodesicLine {
+	l := &geodesicLine{g: g, lat1: latFix(lat1), lon1: lon1}
+	salp1, calp1 := sincosd(angRound(azi1))
+
+	sbet1, cbet1 := sincosd(angRound(l.lat1))
+	sbet1, cbet1 = norm(g.f1*sbet1, cbet1)
+	cbet1 = math.Max(geodesicTiny, cbet1)
+
+	l.salp0 = salp1 * cbet1
+	l.calp0 = math.Hypot(calp1, salp1*sbet1)
+	l.ssig1 = sbet1
+	l.somg1 = l.salp0 * sbet1
+	if sbet1 != 0 || calp1 != 0 {
+		l.csig1 = cbet1 * calp1
+	} else {
+		l.csig1 = 1
+	}
+	l.comg1 = l.csig1
+	l.ssig1, l.csig1 = norm(l.ssig1, l.csig1)
+
+	l.k2 = sq(l.calp0) * g.ep2
+	eps := l.k2 / (2*(1+math.Sqrt(1+l.k2)) + l.k2)
+
+	l.a1m1 = a1m1f(eps)
+	c1f(eps, l.c1a[:])
+	l.b11 = sinCosSeries(true, l.ssig1, l.csig1, l.c1a[:])
+	s, c := math.Sin(l.b11), math.Cos(l.b11)
+	l.stau1 = l.ssig1*c + l.csig1*s
+	l.ctau1 = l.csig1*c - l.ssig1*s
+
+	c1pf(eps, l.c1pa[:])
+
+	g.c3f(eps, l.c3a[:])
+	l.a3c = -g.f * l.salp0 * g.a3f(eps)
+	l.b31 = sinCosSeries(true, l.ssig1, l.csig1, l.c3a[:])
+	return l
+}
+
+// position returns the point at distance s12 along the line, and the azimuth there.
+func (l *geodesicLine) position(s12 float64) (lat2, lon2, azi2 float64) {
+	g := l.g
+	tau12 := s12 / (g.b * (1 + l.a1m1))
+	s, c := math.Sin(tau12), math.Cos(tau12)
+	b12 := -sinCosSeries(true, l.stau1*c+l.ctau1*s, l.ctau1*c-l.stau1*s, l.c1pa[:])
+	sig12 := tau12 - (b12 - l.b11)
+	ssig12, csig12 := math.Sin(sig12), math.Cos(sig12)
+	if math.Abs(g.f) > 0.01 {
+		// Take one step of Newton's method to improve the accuracy for large flattening.
+		ssig2 := l.ssig1*csig12 + l.csig1*ssig12
+		csig2 := l.csig1*csig12 - l.ssig1*ssig12
+		b12 = sinCosSeries(true, ssig2, csig2, l.c1a[:])
+		serr := (1+l.a1m1)*(sig12+(b12-l.b11)) - s12/g.b
+		sig12 -= serr / math.Sqrt(1+l.k2*sq(ssig2))
+		ssig12, csig12 = math.Sin(sig12), math.Cos(sig12)
+	}
+	ssig2 := l.ssig1*csig12 + l.csig1*ssig12
+	csig2 := l.csig1*csig12 - l.ssig1*ssig12
+	sbet2 := l.calp0 * ssig2
+	cbet2 := math.Hypot(l.salp0, l.calp0*csig2)
+	if cbet2 == 0 {
+		// The geodesic reaches a pole.
+		cbet2, csig2 = geodesicTiny, geodesicTiny
+	}
+	salp2, calp2 := l.salp0, l.calp0*csig2
+
+	somg2, comg2 := l.salp0*ssig2, csig2
+	omg12 := math.Atan2(somg2*l.comg1-comg2*l.somg1, comg2*l.comg1+somg2*l.somg1)
+	lam12 := omg12 + l.a3c*(sig12+(sinCosSeries(true, ssig2, csig2, l.c3a[:])-l.b31))
+	lon12 := toDegrees(lam12)
+
+	lat2 = atan2d(sbet2, g.f1*cbet2)
+	lon2 = angNormalize(angNormalize(l.lon1) + angNormalize(lon12))
+	azi2 = atan2d(salp2, calp2)
+	return lat2, lon2, azi2
+}
+
+// astroid solves k^4+2*k^3-(x^2+y^2-1)*k^2-2*y^2*k-y^2 = 0 for its positive root k.
+func astroid(x, y float64) float64 {
+	p := sq(x)
+	q := sq(y)
+	r := (p + q - 1) / 6
+	if q == 0 && r <= 0 {
+		return 0
+	}
+	var (
+		s    = p * q / 4
+		r2   = sq(r)
+		r3   = r * r2
+		disc = s * (s + 2*r3)
+		u    = r
+	)
+	if disc >= 0 {
+		t3 := s + r3
+		if t3 < 0 {
+			t3 -= math.Sqrt(disc)
+		} else {
+			t3 += math.Sqrt(disc)
+		}
+		t := math.Cbrt(t3)
+		u += t
+		if t != 0 {
+			u += r2 / t
+		}
+	} else {
+		ang := math.Atan2(math.Sqrt(-disc), -(s + r3))
+		u += 2 * r * math.Cos(ang/3)
+	}
+	v := math.Sqrt(sq(u) + q)
+	var uv float64
+	if u < 0 {
+		uv = q / (v - u)
+	} else {
+		uv = u + v
+	}
+	w := (uv - q) / (2 * v)
+	return uv / (math.Sqrt(uv+sq(w)) + w)
+}
+
+// sinCosSeries evaluates the sum of c[l] * sin(2*l*x) for l = 1 to n-1 if sinp is true,
+// or of c[l] * cos((2*l+1)*x) for l = 0 to n-1 otherwise, using Clenshaw summation.
+func sinCosSeries(sinp bool, sinx, cosx float64, c []float64) float64 {
+	k := len(c)
+	n := k
+	if sinp {
+		n--
+	}
+	ar := 2 * (cosx - sinx) * (cosx + sinx)
+	var y0, y1 float64
+	if n&1 != 0 {
+		k--
+		y0 = c[k]
+	}
+	for n /= 2; n > 0; n-- {
+		k--
+		y1 = ar*y0 - y1 + c[k]
+		k--
+		y0 = ar*y1 - y0 + c[k]
+	}
+	if sinp {
+		return 2 * sinx * cosx * y0
+	}
+	return cosx * (y0 - y1)
+}
+
+// a1m1f returns the scale factor A1 - 1.
+func a1m1f(eps float64) float64 {
+	coeff := []float64{1, 4, 64, 0, 256}
+	m := nA1 / 2
+	t := polyval(m, coeff, sq(eps)) / coeff[m+1]
+	return (t + eps) / (1 - eps)
+}
+
+// c1f sets the coefficients C1[l] of the Fourier series for the distance.
+func c1f(eps float64, c []float64) {
+	coeff := []float64{
+		-1, 6, -16, 32,
+		-9, 64, -128, 2048,
+		9, -16, 768,
+		3, -5, 512,
+		-7, 1280,
+		-7, 2048,
+	}
+	fourierCoefficients(nC1, eps, coeff, c)
+}
+
+// c1pf sets the coefficients C1'[l] of the Fourier series for the inverse of the distance.
+func c1pf(eps float64, c []float64) {
+	coeff := []float64{
+		205, -432, 768, 1536,
+		4005, -4736, 3840, 12288,
+		-225, 116, 384,
+		-7173, 2695, 7680,
+		3467, 7680,
+		38081, 61440,
+	}
+	fourierCoefficients(nC1p, eps, coeff, c)
+}
+
+// a2m1f returns the scale factor A2 - 1.
+func a2m1f(eps float64) float64 {
+	coeff := []float64{-11, -28, -192, 0, 256}
+	m := nA2 / 2
+	t := polyval(m, coeff, sq(eps)) / coeff[m+1]
+	return (t - eps) / (1 + eps)
+}
+
+// c2f sets the coefficients C2[l] of the Fourier series for the reduced length.
+func c2f(eps float64, c []float64) {
+	coeff := []float64{
+		1, 2, 16, 32,
+		35, 64, 384, 2048,
+		15, 80, 768,
+		7, 35, 512,
+		63, 1280,
+		77, 2048,
+	}
+	fourierCoefficients(nC2, eps, coeff, c)
+}
+
+// fourierCoefficients sets c[1] to c[n] from polynomials in eps^2 with the given
+// coefficients, each followed by its divisor.
+func fourierCoefficients(n int, eps float64, coeff, c []float64) {
+	var (
+		eps2 = sq(eps)
+		d    = eps
+		o    = 0
+	)
+	for l := 1; l <= n; l++ {
+		m := (n - l) / 2
+		c[l] = d * polyval(m, coeff[o:], eps2) / coeff[o+m+1]
+		o += m + 2
+		d *= eps
+	}
+}
+
+// a3coeff sets the coefficients of the polynomial in eps for A3.
+func (g *Geodesic) a3coeff() {
+	coeff := []float64{
+		-3, 128,
+		-2, -3, 64,
+		-1, -3, -1, 16,
+		3, -1, -2, 8,
+		1, -1, 2,
+		1, 1,
+	}
+	o, k := 0, 0
+	for j := nA3 - 1; j >= 0; j-- {
+		m := nA3 - j - 1
+		if j < m {
+			m = j
+		}
+		g.a3x[k] = polyval(m, coeff[o:], g.n) / coeff[o+m+1]
+		k++
+		o += m + 2
+	}
+}
+
+// c3coeff sets the coefficients of the polynomials in eps for C3.
+func (g *Geodesic) c3coeff() {
+	coeff := []float64{
+		3, 128,
+		2, 5, 128,
+		-1, 3, 3, 64,
+		-1, 0, 1, 8,
+		-1, 1, 4,
+		5, 256,
+		1, 3, 128,
+		-3, -2, 3, 64,
+		1, -3, 2, 32,
+		7, 512,
+		-10, 9, 384,
+		5, -9, 5, 192,
+		7, 512,
+		-14, 7, 512,
+		21, 2560,
+	}
+	o, k := 0, 0
+	for l := 1; l < nC3; l++ {
+		for j := nC3 - 1; j >= l; j-- {
+			m := nC3 - j - 1
+			if j < m {
+				m = j
+			}
+			g.c3x[k] = polyval(m, coeff[o:], g.n) / coeff[o+m+1]
+			k++
+			o += m + 2
+		}
+	}
+}
+
+// c4coeff sets the coefficients of the polynomials in eps for C4.
+func (g *Geodesic) c4coeff() {
+	coeff := []float64{
+		97, 15015,
+		1088, 156, 45045,
+		-224, -4784, 1573, 45045,
+		-10656, 14144, -4576, -858, 45045,
+		64, 624, -4576, 6864, -3003, 15015,
+		100, 208, 572, 3432, -12012, 30030, 45045,
+		1, 9009,
+		-2944, 468, 135135,
+		5792, 1040, -1287, 135135,
+		5952, -11648, 9152, -2574, 135135,
+		-64, -624, 4576, -6864, 3003, 135135,
+		8, 10725,
+		1856, -936, 225225,
+		-8448, 4992, -1144, 225225,
+		-1440, 4160, -4576, 1716, 225225,
+		-136, 63063,
+		1024, -208, 105105,
+		3584, -3328, 1144, 315315,
+		-128, 135135,
+		-2560, 832, 405405,
+		128, 99099,
+	}
+	o, k := 0, 0
+	for l := 0; l < nC4; l++ {
+		for j := nC4 - 1; j >= l; j-- {
+			m := nC4 - j - 1
+			g.c4x[k] = polyval(m, coeff[o:], g.n) / coeff[o+m+1]
+			k++
+			o += m + 2
+		}
+	}
+}
+
+// a3f returns the scale factor A3.
+func (g *Geodesic) a3f(eps float64) float64 {
+	return polyval(nA3-1, g.a3x[:], eps)
+}
+
+// c3f sets the coefficients C3[l] of the Fourier series for the longitude.
+func (g *Geodesic) c3f(eps float64, c []float64) {
+	mult, o := 1.0, 0
+	for l := 1; l < nC3; l++ {
+		m := nC3 - l - 1
+		mult *= eps
+		c[l] = mult * polyval(m, g.c3x[o:], eps)
+		o += m + 1
+	}
+}
+
+// c4f sets the coefficients C4[l] of the Fourier series for the area.
+func (g *Geodesic) c4f(eps float64, c []float64) {
+	mult, o := 1.0, 0
+	for l := 0; l < nC4; l++ {
+		m := nC4 - l - 1
+		c[l] = mult * polyval(m, g.c4x[o:], eps)
+		o += m + 1
+		mult *= eps
+	}
+}
+
+// polyval evaluates the polynomial of degree n with coefficients p, highest first, at x.
+func polyval(n int, p []float64, x float64) float64 {
+	if n < 0 {
+		return 0
+	}
+	y := p[0]
+	for i := 1; i <= n; i++ {
+		y = y*x + p[i]
+	}
+	return y
+}
+
+// accumulator is a sum of float64s kept to twice the precision of a float64.
+type accumulator struct {
+	s, t float64
+}
+
+// add adds y to the sum.
+func (acc *accumulator) add(y float64) {
+	y, u := twoSum(y, acc.t)
+	acc.s, acc.t = twoSum(y, acc.s)
+	if acc.s == 0 {
+		acc.s = u
+	} else {
+		acc.t += u
+	}
+}
+
+// remainder reduces the sum to the range [-y/2, y/2].
+func (acc *accumulator) remainder(y float64) {
+	acc.s = math.Remainder(acc.s, y)
+	acc.add(0)
+}
+
+// negate negates the sum.
+func (acc *accumulator) negate() {
+	acc.s, acc.t = -acc.s, -acc.t
+}
+
+// sum returns the sum.
+func (acc accumulator) sum() float64 {
+	return acc.s
+}
+
+// sq returns x squared.
+func sq(x float64) float64 {
+	return x * x
+}
+
+// norm returns x and y scaled so that x^2 + y^2 = 1.
+func norm(x, y float64) (float64, float64) {
+	r := math.Hypot(x, y)
+	return x / r, y / r
+}
+
+// angRound rounds tiny angles to zero so that they do not cause underflow.
+func angRound(x float64) float64 {
+	const z = 1.0 / 16
+	y := math.Abs(x)
+	if y < z {
+		y = z - (z - y)
+	}
+	return math.Copysign(y, x)
+}
+
+// angNormalize reduces an angle to the range [-180, 180].
+func angNormalize(x float64) float64 {
+	y := math.Remainder(x, 360)
+	if y == -180 {
+		return 180
+	}
+	return y
+}
+
+// latFix returns NaN for latitudes outside [-90, 90].
+func latFix(x float64) float64 {
+	if math.Abs(x) > 90 {
+		return math.NaN()
+	}
+	return x
+}
+
+// angDiff returns y - x reduced to the range [-180, 180], as d + e,
+// where e is the rounding error of d.
+func angDiff(x, y float64) (d, e float64) {
+	d, t := twoSum(math.Remainder(-x, 360), math.Remainder(y, 360))
+	d, e = twoSum(math.Remainder(d, 360), t)
+	if d == 0 || math.Abs(d) == 180 {
+		if e == 0 {
+			d = math.Copysign(d, y-x)
+		} else {
+			d = math.Copysign(d, -e)
+		}
+	}
+	return d, e
+}
+
+// sincosd returns the sine and cosine of an angle in degrees,
+// exactly for multiples of 90 degrees.
+func sincosd(x float64) (s, c float64) {
+	r := math.Mod(x, 360)
+	q := 0
+	if !math.IsNaN(r) {
+		q = int(math.Round(r / 90))
+	}
+	r -= 90 * float64(q)
+	r = toRadians(r)
+	s, c = math.Sin(r), math.Cos(r)
+	switch uint(q) & 3 {
+	case 1:
+		s, c = c, -s
+	case 2:
+		s, c = -s, -c
+	case 3:
+		s, c = -c, s
+	}
+	c += 0
+	if s == 0 {
+		s = math.Copysign(s, x)
+	}
+	return s, c
+}
+
+// atan2d returns the angle in degrees whose tangent is y/x,
+// exactly for multiples of 90 degrees.
+func atan2d(y, x float64) float64 {
+	q := 0
+	if math.Abs(y) > math.Abs(x) {
+		q = 2
+		x, y = y, x
+	}
+	if x < 0 {
+		q++
+		x = -x
+	}
+	ang := toDegrees(math.Atan2(y, x))
+	switch q {
+	case 1:
+		if y >= 0 {
+			ang = 180 - ang
+		} else {
+			ang = -180 - ang
+		}
+	case 2:
+		ang = 90 - ang
+	case 3:
+		ang = -90 + ang
+	}
+	return ang
+}
@@ -0,0 +1,153 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// The expected values in these tests come from GeographicLib.
+
+func TestGeodesicInverse(t *testing.T) {
+	for i, testcase := range []struct {
+		Lat1, Lon1, Lat2, Lon2 float64
+		S12, Azi1, Azi2        float64
+	}{
+		{Lat1: 40.6, Lon1: -73.8, Lat2: 51.6, Lon2: -0.5, S12: 5551759.400, Azi1: 51.198883, Azi2: 107.821777},
+		{Lat1: 40.6, Lon1: -73.8, Lat2: 49.01666667, Lon2: 2.55, S12: 5853226.256, Azi1: 53.470218, Azi2: 111.593670},
+		{Lat1: 0, Lon1: 0, Lat2: 0, Lon2: 90, S12: 10018754.171, Azi1: 90, Azi2: 90},
+		{Lat1: 0, Lon1: 0, Lat2: 90, Lon2: 0, S12: 10001965.729, Azi1: 0, Azi2: 0},
+		{Lat1: 0, Lon1: 0, Lat2: 0.5, Lon2: 179.5, S12: 19936288.579, Azi1: 25.671873, Azi2: 154.327085},
+		{Lat1: 10, Lon1: 20, Lat2: 10, Lon2: 20, S12: 0, Azi1: 180, Azi2: 180},
+	} {
+		s12, azi1, azi2 := WGS84.Inverse(testcase.Lat1, testcase.Lon1, testcase.Lat2, testcase.Lon2)
+		if expected, got := testcase.S12, s12; math.Abs(expected-got) > 1e-3 {
+			t.Fatalf("(case %d) expected distance %f, got %f", i, expected, got)
+		}
+		if expected, got := testcase.Azi1, azi1; math.Abs(expected-got) > 1e-6 {
+			t.Fatalf("(case %d) expected azimuth %f, got %f", i, expected, got)
+		}
+		if expected, got := testcase.Azi2, azi2; math.Abs(expected-got) > 1e-6 {
+			t.Fatalf("(case %d) expected azimuth %f, got %f", i, expected, got)
+		}
+	}
+}
+
+func TestGeodesicDirect(t *testing.T) {
+	for i, testcase := range []struct {
+		Lat1, Lon1, Azi1, S12 float64
+		Lat2, Lon2, Azi2      float64
+	}{
+		{Lat1: 40.63972222, Lon1: -73.77888889, Azi1: 53.5, S12: 5850e3, Lat2: 49.014669, Lon2: 2.561062, Azi2: 111.629467},
+		{Lat1: -32.06, Lon1: 115.74, Azi1: 225, S12: 20000e3, Lat2: 32.11195529, Lon2: -63.95925278, Azi2: -45.032435},
+		{Lat1: 0, Lon1: 0, Azi1: 90, S12: 10018754.171394622, Lat2: 0, Lon2: 90, Azi2: 90},
+	} {
+		lat2, lon2, azi2 := WGS84.Direct(testcase.Lat1, testcase.Lon1, testcase.Azi1, testcase.S12)
+		if expected, got := testcase.Lat2, lat2; math.Abs(expected-got) > 1e-6 {
+			t.Fatalf("(case %d) expected latitude %f, got %f", i, expected, got)
+		}
+		if expected, got := testcase.Lon2, lon2; math.Abs(expected-got) > 1e-6 {
+			t.Fatalf("(case %d) expected longitude %f, got %f", i, expected, got)
+		}
+		if expected, got := testcase.Azi2, azi2; math.Abs(expected-got) > 1e-6 {
+			t.Fatalf("(case %d) expected azimuth %f, got %f", i, expected, got)
+		}
+	}
+}
+
+func TestGeodesicRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		var (
+			lat1 = rng.Float64()*180 - 90
+			lon1 = rng.Float64()*360 - 180
+			lat2 = rng.Float64()*180 - 90
+			lon2 = rng.Float64()*360 - 180
+		)
+		s12, azi1, _ := WGS84.Inverse(lat1, lon1, lat2, lon2)
+		lat, lon, _ := WGS84.Direct(lat1, lon1, azi1, s12)
+		if d := WGS84.pointDistance([4]float64{lon2, lat2}, [4]float64{lon, lat}); d > 1e-6 {
+			t.Fatalf("(case %d) expected to reach (%f, %f), missed by %g m", i, lat2, lon2, d)
+		}
+	}
+}
+
+func TestGeodesicMeasure(t *testing.T) {
+	var antarctica [][4]float64
+	for _, p := range [][2]float64{
+		{-63.1, -58}, {-72.9, -74}, {-71.9, -102}, {-74.9, -102}, {-74.3, -131},
+		{-77.5, -163}, {-77.4, 163}, {-71.7, 172}, {-65.9, 140}, {-65.7, 113},
+		{-66.6, 88}, {-66.9, 59}, {-69.8, 25}, {-70.0, -4}, {-71.0, -14},
+		{-77.3, -33}, {-77.9, -46}, {-74.7, -61},
+	} {
+		antarctica = append(antarctica, [4]float64{p[1], p[0]})
+	}
+	var (
+		square    = [][4]float64{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}
+		clockwise = [][4]float64{{0, 0}, {0, 1}, {1, 1}, {1, 0}, {0, 0}}
+		hole      = [][4]float64{{0.25, 0.25}, {0.75, 0.25}, {0.75, 0.75}, {0.25, 0.75}, {0.25, 0.25}}
+	)
+	if expected, got := 13662703680020.1, WGS84.ringArea(antarctica); math.Abs(expected-got) > 0.1 {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	if expected, got := -WGS84.ringArea(square), WGS84.ringArea(clockwise); expected != got {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	for i, testcase := range []struct {
+		G         Geometry
+		Area      float64
+		Length    float64
+		Perimeter float64
+	}{
+		{
+			G:         &Polygon{antarctica},
+			Area:      13662703680020.1,
+			Perimeter: 16831067.893,
+		},
+		{
+			G:         &Polygon{clockwise},
+			Area:      12308778361.469,
+			Perimeter: WGS84.lineLength(square, true),
+		},
+		{
+			G:         &MultiPolygon{{square, hole}, {antarctica}},
+			Area:      12308778361.469 - WGS84.Area(&Polygon{hole}) + 13662703680020.1,
+			Perimeter: WGS84.lineLength(square, true) + WGS84.lineLength(hole, true) + 16831067.893,
+		},
+		{
+			G:      &Line{{-73.8, 40.6}, {-0.5, 51.6}, {-0.5, 51.6}},
+			Length: 5551759.400,
+		},
+		{
+			G:      &GeometryCollection{&MultiLine{{{-73.8, 40.6}, {-0.5, 51.6}}}, &Point{1, 2}, WithSRID(4326, &Line{{-73.8, 40.6}, {-0.5, 51.6}})},
+			Length: 2 * 5551759.400,
+		},
+		{
+			G:         &Circle{Coordinates: Point{1, 2}, Radius: 1000 / feetToMeters},
+			Area:      2 * math.Pi * earthRadiusMeters * earthRadiusMeters * (1 - math.Cos(1000/earthRadiusMeters)),
+			Perimeter: 2 * math.Pi * earthRadiusMeters * math.Sin(1000/earthRadiusMeters),
+		},
+	} {
+		if expected, got := testcase.Area, WGS84.Area(testcase.G); math.Abs(expected-got) > 0.1 {
+			t.Fatalf("(case %d) expected area %f, got %f", i, expected, got)
+		}
+		if expected, got := testcase.Length, WGS84.Length(testcase.G); math.Abs(expected-got) > 1e-3 {
+			t.Fatalf("(case %d) expected length %f, got %f", i, expected, got)
+		}
+		if expected, got := testcase.Perimeter, WGS84.Perimeter(testcase.G); math.Abs(expected-got) > 1e-3 {
+			t.Fatalf("(case %d) expected perimeter %f, got %f", i, expected, got)
+		}
+	}
+}
+
+func TestGeodesicSphere(t *testing.T) {
+	// On a sphere the distance is the great circle distance.
+	sphere := NewGeodesic(earthRadiusMeters, 0)
+	var (
+		a = Point{-73.8, 40.6}
+		b = Point{-0.5, 51.6}
+	)
+	if expected, got := (Circle{Coordinates: a}).distanceHaversine(b), sphere.pointDistance(a, b); math.Abs(expected-got) > 1e-6 {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+}
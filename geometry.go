@@ -5,7 +5,6 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
-	"strings"
 )
 
 // Geometry types.
@@ -29,8 +28,10 @@ type Geometry interface {
 	sql.Scanner
 	driver.Valuer
 
+	Bounds() Envelope
 	Equal(g Geometry) bool
 	Contains(p Point) bool
+	IsEmpty() bool
 	String() string
 	Transform(Transformer)
 	VisitCoordinates(Visitor)
@@ -46,45 +47,77 @@ type Visitor interface {
 	Visit(Point)
 }
 
+// isEmpty returns true if a geometry has no coordinates.
+// Empty points, which have NaN coordinates, are not counted.
+func isEmpty(g Geometry) bool {
+	v := &emptyVisitor{empty: true}
+	g.VisitCoordinates(v)
+	return v.empty
+}
+
+// emptyVisitor records whether it has visited any point that is not empty.
+type emptyVisitor struct {
+	empty bool
+}
+
+// Visit visits a point.
+func (v *emptyVisitor) Visit(p Point) {
+	if !p.IsEmpty() {
+		v.empty = false
+	}
+}
+
+// Is3D returns true if the layout of the geometry has a Z ordinate.
+// Geometries that are 3D are encoded with Z ordinates
+// in GeoJSON, Well Known Text and Well Known Binary.
+func Is3D(g Geometry) bool {
+	return LayoutOf(g).HasZ()
+}
+
 // ScanGeometry scans a geometry from well known text.
+// The returned Geometry has the type named by the text's tag,
+// e.g. "MULTIPOINT(1 2, 3 4)" returns a *MultiPoint.
+// Syntax errors are returned as *WKTError.
+// Hex-encoded well known binary is also accepted.
+// If the input is EWKT or EWKB with an SRID,
+// e.g. "SRID=4326;POINT(1 2)", the geometry is wrapped with WithSRID.
+// The geometry keeps its layout, see LayoutOf, even for zero ordinates,
+// e.g. "POINT Z (1 2 0)", and if it is empty it is wrapped with WithLayout.
 func ScanGeometry(s string) (Geometry, error) {
-	if i := strings.Index(s, pointWKTPrefix); i == 0 {
-		pt := &Point{}
-		if err := pt.Scan(s); err != nil {
+	if isHexWKB(s) {
+		data, err := decodeHexWKB(s)
+		if err != nil {
 			return nil, err
 		}
-		return pt, nil
+		return UnmarshalWKB(data)
 	}
-	if i := strings.Index(s, lineWKTPrefix); i == 0 {
-		l := &Line{}
-		if err := l.Scan(s); err != nil {
-			return nil, err
-		}
-		return l, nil
+	g, srid, err := parseWKT(s)
+	if err != nil {
+		return nil, err
 	}
-	if i := strings.Index(s, polygonWKTPrefix); i == 0 {
-		p := &Polygon{}
-		if err := p.Scan(s); err != nil {
-			return nil, err
-		}
-		return p, nil
+	if srid != 0 {
+		return WithSRID(srid, g), nil
 	}
-	if i := strings.Index(s, circleWKTPrefix); i == 0 {
-		c := &Circle{}
-		if err := c.Scan(s); err != nil {
-			return nil, err
-		}
-		return c, nil
-	}
-	return nil, fmt.Errorf("unrecognized geometry: %s", s)
+	return g, nil
 }
 
 // geometry is a utility type used to unmarshal geometries from JSON.
 type geometry struct {
-	Type        string          `json:"type"`
-	Coordinates json.RawMessage `json:"coordinates"`
-	Radius      float64         `json:"radius"` // For circles!
-	BBox        []float64       `json:"bbox"`
+	Type        string            `json:"type"`
+	Coordinates json.RawMessage   `json:"coordinates"`
+	Geometries  []json.RawMessage `json:"geometries"` // For geometry collections!
+	Radius      float64           `json:"radius"`     // For circles!
+	BBox        json.RawMessage   `json:"bbox"`
+}
+
+// unmarshalGeometry unmarshals a GeoJSON geometry of any type,
+// including geometry collections nested to any depth.
+func unmarshalGeometry(data json.RawMessage) (Geometry, error) {
+	g := geometry{}
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, err
+	}
+	return g.unmarshalCoordinates()
 }
 
 // Geometry returns a Geometry, or an error if Type is invalid.
@@ -93,45 +126,84 @@ func (g geometry) unmarshalCoordinates() (geom Geometry, err error) {
 	default:
 		return nil, fmt.Errorf("unrecognized geometry type: %s", g.Type)
 	case PointType:
-		pt := [3]float64{}
-		err = json.Unmarshal(g.Coordinates, &pt)
-		p := Point(pt)
+		var p Point
+		p, err = unmarshalPosition(g.Coordinates)
 		geom = &p
 	case MultiPointType:
-		mpt := [][3]float64{}
+		mpt := [][4]float64{}
 		err = json.Unmarshal(g.Coordinates, &mpt)
 		mp := MultiPoint(mpt)
 		geom = &mp
 	case LineType:
-		ln := [][3]float64{}
+		ln := [][4]float64{}
 		err = json.Unmarshal(g.Coordinates, &ln)
 		l := Line(ln)
 		geom = &l
 	case MultiLineType:
-		mln := [][][3]float64{}
+		mln := [][][4]float64{}
 		err = json.Unmarshal(g.Coordinates, &mln)
 		ml := MultiLine(mln)
 		geom = &ml
 	case PolygonType:
-		poly := [][][3]float64{}
+		poly := [][][4]float64{}
 		err = json.Unmarshal(g.Coordinates, &poly)
 		p := Polygon(poly)
 		geom = &p
 	case MultiPolygonType:
-		mpoly := [][][][3]float64{}
+		mpoly := [][][][4]float64{}
 		err = json.Unmarshal(g.Coordinates, &mpoly)
 		mp := MultiPolygon(mpoly)
 		geom = &mp
+	case GeometryCollectionType:
+		gc := make(GeometryCollection, len(g.Geometries))
+		for i, member := range g.Geometries {
+			if gc[i], err = unmarshalGeometry(member); err != nil {
+				return nil, err
+			}
+		}
+		geom = &gc
 	case CircleType:
-		center := [3]float64{}
+		center := [4]float64{}
 		err = json.Unmarshal(g.Coordinates, &center)
 		geom = &Circle{
 			Coordinates: center,
 			Radius:      g.Radius,
 		}
 	}
-	if len(g.BBox) > 0 {
-		return WithBBox(g.BBox, geom), err
+	if err != nil {
+		return nil, err
+	}
+	if g.Type != GeometryCollectionType && g.Type != CircleType {
+		geom = withDecodedLayout(positionLayout(g.Coordinates), geom)
+	}
+	return withUnmarshalledBBox(g.BBox, geom)
+}
+
+// positionLayout returns the layout of the GeoJSON positions in coordinates,
+// from the largest number of ordinates that any of them has.
+// Four ordinates are X, Y, Z and M.
+func positionLayout(coordinates json.RawMessage) Layout {
+	var (
+		commas  []int  // the number of commas in each open array
+		numbers []bool // whether each open array is a position
+		dims    int
+	)
+	for _, c := range coordinates {
+		last := len(commas) - 1
+		switch {
+		case c == '[':
+			commas, numbers = append(commas, 0), append(numbers, false)
+		case last < 0:
+		case c == ',':
+			commas[last]++
+		case c == ']':
+			if numbers[last] && commas[last]+1 > dims {
+				dims = commas[last] + 1
+			}
+			commas, numbers = commas[:last], numbers[:last]
+		case c == '-' || (c >= '0' && c <= '9'):
+			numbers[last] = true
+		}
 	}
-	return geom, err
+	return layoutFor(dims > 2, dims > 3)
 }
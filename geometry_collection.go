@@ -2,6 +2,7 @@ package geo
 
 import (
 	"database/sql/driver"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 )
@@ -28,9 +29,26 @@ func (gc GeometryCollection) Equal(g Geometry) bool {
 	return true
 }
 
-// Contains returns true if the GeometryCollection contains the point
-// and false otherwise.
+// Contains returns true if the point is in any member of the GeometryCollection,
+// i.e. the collection is treated as the union of its members.
+// It is the same as ContainsAny.
 func (gc GeometryCollection) Contains(p Point) bool {
+	return gc.ContainsAny(p)
+}
+
+// ContainsAny returns true if any member of the GeometryCollection contains the point.
+func (gc GeometryCollection) ContainsAny(p Point) bool {
+	for _, g := range gc {
+		if g.Contains(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsAll returns true if every member of the GeometryCollection contains the point.
+// An empty GeometryCollection does not contain any point.
+func (gc GeometryCollection) ContainsAll(p Point) bool {
 	if len(gc) == 0 {
 		return false
 	}
@@ -42,6 +60,27 @@ func (gc GeometryCollection) Contains(p Point) bool {
 	return true
 }
 
+// IsEmpty returns true if every member of the GeometryCollection is empty.
+func (gc GeometryCollection) IsEmpty() bool {
+	for _, g := range gc {
+		if !g.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// Bounds returns the smallest envelope that contains every member of the GeometryCollection.
+// Members with Z only contribute to the Z range of the envelope if they have Z.
+// If a member crosses the antimeridian so may the envelope, see Envelope.Union.
+func (gc GeometryCollection) Bounds() Envelope {
+	env := emptyEnvelope()
+	for _, g := range gc {
+		env = env.Union(g.Bounds())
+	}
+	return env
+}
+
 // MarshalJSON marshals the GeometryCollection to JSON.
 func (gc GeometryCollection) MarshalJSON() ([]byte, error) {
 	buf := []byte(geometryCollectionJSONPrefix)
@@ -58,22 +97,43 @@ func (gc GeometryCollection) MarshalJSON() ([]byte, error) {
 	return append(buf, ']', '}'), nil
 }
 
-// Scan scans the feature collection from WKT.
+// geoJSON marshals the GeometryCollection to JSON using the given layout for every member.
+func (gc GeometryCollection) geoJSON(layout Layout) ([]byte, error) {
+	members := make(GeometryCollection, len(gc))
+	for i, geometry := range gc {
+		members[i] = WithLayout(layout, geometry)
+	}
+	return members.MarshalJSON()
+}
+
+// MarshalWKB returns the Well Known Binary representation of the GeometryCollection
+// using the given byte order.
+func (gc GeometryCollection) MarshalWKB(order binary.ByteOrder) ([]byte, error) {
+	return marshalWKB(&gc, order)
+}
+
+// Scan scans the geometry collection from WKT.
 // This method expects a GEOMETRYCOLLECTION.
-// TODO: implement this.
 func (gc *GeometryCollection) Scan(src interface{}) error {
 	return scan(gc, src)
 }
 
-// scan scans the feature collection from WKT.
+// scan scans the geometry collection from WKT.
 // This method expects a GEOMETRYCOLLECTION.
-// TODO: implement this.
 func (gc *GeometryCollection) scan(s string) error {
+	g, _, err := parseWKT(s, geometryCollectionWKTTag)
+	if err != nil {
+		return err
+	}
+	*gc = *withoutLayout(g).(*GeometryCollection)
 	return nil
 }
 
 // String converts the feature collection to a GEOMETRYCOLLECTION.
 func (gc GeometryCollection) String() string {
+	if len(gc) == 0 {
+		return geometryCollectionWKTPrefix + " " + emptyWKTTag
+	}
 	s := geometryCollectionWKTPrefix + "("
 	for i, geometry := range gc {
 		if i == 0 {
@@ -85,17 +145,26 @@ func (gc GeometryCollection) String() string {
 	return s + ")"
 }
 
+// wkt converts the geometry collection to a GEOMETRYCOLLECTION using the given layout for every member.
+func (gc GeometryCollection) wkt(layout Layout) string {
+	members := make(GeometryCollection, len(gc))
+	for i, geometry := range gc {
+		members[i] = WithLayout(layout, geometry)
+	}
+	return members.String()
+}
+
 // geometryCollection is a utility type used to unmarshal a geojson GeometryCollection.
 type geometryCollection struct {
-	Type       string      `json:"type"`
-	Geometries []*geometry `json:"geometries"`
-	BBox       []float64   `json:"bbox"`
+	Type       string            `json:"type"`
+	Geometries []json.RawMessage `json:"geometries"`
+	BBox       json.RawMessage   `json:"bbox"`
 }
 
 func (coll geometryCollection) ToGeometryCollection() (*GeometryCollection, error) {
 	geometries := make([]Geometry, len(coll.Geometries))
 	for i, g := range coll.Geometries {
-		gg, err := g.unmarshalCoordinates()
+		gg, err := unmarshalGeometry(g)
 		if err != nil {
 			return nil, err
 		}
@@ -119,6 +188,16 @@ func (gc *GeometryCollection) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// UnmarshalWKB unmarshals the GeometryCollection from Well Known Binary.
+func (gc *GeometryCollection) UnmarshalWKB(data []byte) error {
+	g, _, err := unmarshalWKB(data, wkbGeometryCollection)
+	if err != nil {
+		return err
+	}
+	*gc = *withoutLayout(g).(*GeometryCollection)
+	return nil
+}
+
 // Value returns WKT for the geometry collection.
 // Note that this returns a GEOMETRYCOLLECTION.
 func (gc GeometryCollection) Value() (driver.Value, error) {
@@ -161,8 +240,5 @@ func unmarshalGeometryCollectionBBox(data []byte) (Geometry, error) {
 	if err != nil {
 		return nil, err
 	}
-	if len(coll.BBox) > 0 {
-		return WithBBox(coll.BBox, geometries), nil
-	}
-	return geometries, nil
+	return withUnmarshalledBBox(coll.BBox, geometries)
 }
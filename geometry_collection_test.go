@@ -45,6 +45,48 @@ func TestGeometryCollection(t *testing.T) {
 			{12, 12},
 		},
 	}.test(t)
+
+	// Contains any member
+	cases{
+		G: &GeometryCollection{
+			&Polygon{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}},
+			&Point{5, 5},
+		},
+		Inside: []Point{
+			{1, 1},
+			{5, 5},
+		},
+		Outside: []Point{
+			{3, 3},
+		},
+	}.test(t)
+}
+
+func TestGeometryCollectionContainsAnyAll(t *testing.T) {
+	gc := GeometryCollection{
+		&Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}},
+		&Polygon{{{2, 2}, {6, 2}, {6, 6}, {2, 6}, {2, 2}}},
+	}
+	for i, testcase := range []struct {
+		Point Point
+		Any   bool
+		All   bool
+	}{
+		{Point: Point{1, 1}, Any: true},
+		{Point: Point{3, 3}, Any: true, All: true},
+		{Point: Point{5, 5}, Any: true},
+		{Point: Point{7, 7}},
+	} {
+		if expected, got := testcase.Any, gc.ContainsAny(testcase.Point); expected != got {
+			t.Fatalf("(case %d) expected ContainsAny to be %t, got %t", i, expected, got)
+		}
+		if expected, got := testcase.All, gc.ContainsAll(testcase.Point); expected != got {
+			t.Fatalf("(case %d) expected ContainsAll to be %t, got %t", i, expected, got)
+		}
+	}
+	if (GeometryCollection{}).ContainsAll(Point{0, 0}) {
+		t.Fatal("expected an empty GeometryCollection to not contain anything")
+	}
 }
 
 func TestGeometryCollectionMarshal(t *testing.T) {
@@ -129,6 +171,16 @@ func TestGeometryCollectionUnmarshal(t *testing.T) {
 			},
 			Instance: &GeometryCollection{},
 		},
+		{
+			Input: []byte(`{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[1,2]},{"type":"GeometryCollection","geometries":[{"type":"GeometryCollection","geometries":[{"type":"LineString","coordinates":[[0,0],[1,1]]}]}]}]}`),
+			Expected: &GeometryCollection{
+				&Point{1, 2},
+				&GeometryCollection{
+					&GeometryCollection{&Line{{0, 0}, {1, 1}}},
+				},
+			},
+			Instance: &GeometryCollection{},
+		},
 	}.pass(t)
 
 	// Fail
@@ -148,6 +200,16 @@ func TestGeometryCollectionUnmarshal(t *testing.T) {
 			Input:    []byte(`{"type":"GeometryCollection","geometries":[{"type":"Polygon","coordinates":[{"key":"value"}]}]}`),
 			Instance: &GeometryCollection{},
 		},
+		// Bad nested geometry
+		{
+			Input:    []byte(`{"type":"GeometryCollection","geometries":[{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":"x"}]}]}`),
+			Instance: &GeometryCollection{},
+		},
+		// Null member
+		{
+			Input:    []byte(`{"type":"GeometryCollection","geometries":[null]}`),
+			Instance: &GeometryCollection{},
+		},
 	}.fail(t)
 }
 
@@ -163,3 +225,40 @@ func TestGeometryCollectionValue(t *testing.T) {
 		},
 	}.pass(t)
 }
+
+func TestGeometryCollectionNested(t *testing.T) {
+	nested := &GeometryCollection{
+		&Point{1, 2},
+		WithBBox([]float64{0, 0, 1, 1}, &GeometryCollection{&Line{{0, 0}, {1, 1}}}),
+		&GeometryCollection{&GeometryCollection{}},
+	}
+	data, err := nested.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, input := range [][]byte{
+		data,
+		[]byte(`{"type":"Feature","geometry":` + string(data) + `,"properties":null}`),
+	} {
+		g, err := UnmarshalJSON(input)
+		if err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if f, ok := g.(*Feature); ok {
+			g = f.Geometry
+		}
+		if !nested.Equal(g) {
+			t.Fatalf("(case %d) expected %s, got %s", i, nested, g)
+		}
+	}
+
+	// Well Known Text
+	wkt := `GEOMETRYCOLLECTION(POINT(1 2), GEOMETRYCOLLECTION(LINESTRING(0 0, 1 1)), GEOMETRYCOLLECTION(GEOMETRYCOLLECTION EMPTY))`
+	g, err := ScanGeometry(wkt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := wkt, g.String(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
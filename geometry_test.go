@@ -0,0 +1,160 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIs3D(t *testing.T) {
+	for i, testcase := range []struct {
+		Input    Geometry
+		Expected bool
+		WKT      string
+	}{
+		{
+			Input: &Point{1, 2},
+			WKT:   `POINT(1 2)`,
+		},
+		{
+			Input:    &Point{1, 2, 3},
+			Expected: true,
+			WKT:      `POINT Z (1 2 3)`,
+		},
+		{
+			Input:    &MultiPoint{{1, 2}, {3, 4, 5}},
+			Expected: true,
+			WKT:      `MULTIPOINT Z (1 2 0, 3 4 5)`,
+		},
+		{
+			Input:    &Polygon{{{0, 0, 1}, {1, 0, 1}, {1, 1, 1}, {0, 0, 1}}},
+			Expected: true,
+			WKT:      `POLYGON Z ((0 0 1, 1 0 1, 1 1 1, 0 0 1))`,
+		},
+		{
+			Input:    &MultiLine{{{0, 0}, {1, 1, -1}}},
+			Expected: true,
+			WKT:      `MULTILINESTRING Z ((0 0 0, 1 1 -1))`,
+		},
+		{
+			Input:    &MultiPolygon{{{{0, 0, 1}, {1, 0, 1}, {1, 1, 1}, {0, 0, 1}}}},
+			Expected: true,
+			WKT:      `MULTIPOLYGON Z (((0 0 1, 1 0 1, 1 1 1, 0 0 1)))`,
+		},
+		{
+			Input:    &GeometryCollection{&Point{1, 2}, &Point{1, 2, 3}},
+			Expected: true,
+			WKT:      `GEOMETRYCOLLECTION(POINT(1 2), POINT Z (1 2 3))`,
+		},
+	} {
+		if expected, got := testcase.Expected, Is3D(testcase.Input); expected != got {
+			t.Fatalf("(case %d) expected %t, got %t", i, expected, got)
+		}
+		if expected, got := testcase.WKT, testcase.Input.String(); expected != got {
+			t.Fatalf("(case %d) expected %s, got %s", i, expected, got)
+		}
+		g, err := ScanGeometry(testcase.WKT)
+		if err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if !testcase.Input.Equal(g) {
+			t.Fatalf("(case %d) expected %s, got %s", i, testcase.Input, g)
+		}
+	}
+}
+
+func TestIsEmpty(t *testing.T) {
+	for i, testcase := range []struct {
+		Input    Geometry
+		Expected bool
+		WKT      string
+		JSON     string
+	}{
+		{
+			Input:    &Point{math.NaN(), math.NaN()},
+			Expected: true,
+			WKT:      `POINT EMPTY`,
+			JSON:     `{"type":"Point","coordinates":[]}`,
+		},
+		{
+			Input: &Point{0, 0},
+			WKT:   `POINT(0 0)`,
+			JSON:  `{"type":"Point","coordinates":[0,0]}`,
+		},
+		{
+			Input:    &Line{},
+			Expected: true,
+			WKT:      `LINESTRING EMPTY`,
+			JSON:     `{"type":"LineString","coordinates":[]}`,
+		},
+		{
+			Input:    &Polygon{},
+			Expected: true,
+			WKT:      `POLYGON EMPTY`,
+			JSON:     `{"type":"Polygon","coordinates":[]}`,
+		},
+		{
+			Input:    &MultiPoint{},
+			Expected: true,
+			WKT:      `MULTIPOINT EMPTY`,
+			JSON:     `{"type":"MultiPoint","coordinates":[]}`,
+		},
+		{
+			Input:    &MultiLine{{}},
+			Expected: true,
+			WKT:      `MULTILINESTRING(EMPTY)`,
+			JSON:     `{"type":"MultiLineString","coordinates":[[]]}`,
+		},
+		{
+			Input: &MultiLine{{}, {{1, 2}, {3, 4}}},
+			WKT:   `MULTILINESTRING(EMPTY,(1 2, 3 4))`,
+			JSON:  `{"type":"MultiLineString","coordinates":[[],[[1,2],[3,4]]]}`,
+		},
+		{
+			Input:    &MultiPolygon{},
+			Expected: true,
+			WKT:      `MULTIPOLYGON EMPTY`,
+			JSON:     `{"type":"MultiPolygon","coordinates":[]}`,
+		},
+		{
+			Input:    &GeometryCollection{},
+			Expected: true,
+			WKT:      `GEOMETRYCOLLECTION EMPTY`,
+			JSON:     `{"type":"GeometryCollection","geometries":[]}`,
+		},
+		{
+			Input:    &GeometryCollection{&Point{math.NaN(), math.NaN()}, &Line{}},
+			Expected: true,
+			WKT:      `GEOMETRYCOLLECTION(POINT EMPTY, LINESTRING EMPTY)`,
+			JSON:     `{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[]},{"type":"LineString","coordinates":[]}]}`,
+		},
+	} {
+		if expected, got := testcase.Expected, testcase.Input.IsEmpty(); expected != got {
+			t.Fatalf("(case %d) expected %t, got %t", i, expected, got)
+		}
+		if expected, got := testcase.WKT, testcase.Input.String(); expected != got {
+			t.Fatalf("(case %d) expected %s, got %s", i, expected, got)
+		}
+		data, err := testcase.Input.MarshalJSON()
+		if err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if expected, got := testcase.JSON, string(data); expected != got {
+			t.Fatalf("(case %d) expected %s, got %s", i, expected, got)
+		}
+
+		// Round trip
+		g, err := ScanGeometry(testcase.WKT)
+		if err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if !testcase.Input.Equal(g) {
+			t.Fatalf("(case %d) expected %s, got %s", i, testcase.Input, g)
+		}
+		if g, err = UnmarshalJSON(data); err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if !testcase.Input.Equal(g) {
+			t.Fatalf("(case %d) expected %s, got %s", i, testcase.Input, g)
+		}
+	}
+}
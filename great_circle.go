@@ -0,0 +1,138 @@
+package geo
+
+import "math"
+
+// The methods in this file treat points as longitudes and latitudes in degrees
+// on a sphere with the same radius as the one Circle uses,
+// and measure distances in meters.
+// See https://www.movable-type.co.uk/scripts/latlong.html
+
+// Bearing returns the initial bearing in degrees clockwise from north
+// of the great circle path from the point to another point, in the range [0, 360).
+func (point Point) Bearing(to Point) float64 {
+	var (
+		lat1 = toRadians(point[1])
+		lat2 = toRadians(to[1])
+		dLng = toRadians(to[0] - point[0])
+		y    = math.Sin(dLng) * math.Cos(lat2)
+		x    = math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLng)
+	)
+	return normalizeBearing(toDegrees(math.Atan2(y, x)))
+}
+
+// FinalBearing returns the bearing in degrees clockwise from north
+// of the great circle path from the point to another point when it arrives there,
+// in the range [0, 360).
+func (point Point) FinalBearing(to Point) float64 {
+	return normalizeBearing(to.Bearing(point) + 180)
+}
+
+// Destination returns the point reached by travelling the given distance in meters
+// from the point along the great circle that starts at the given bearing.
+// The longitude of the destination is in the range [-180, 180],
+// and it has the same Z and M as the point.
+func (point Point) Destination(distance, bearing float64) Point {
+	var (
+		d     = distance / earthRadiusMeters
+		theta = toRadians(bearing)
+		lat1  = toRadians(point[1])
+		lng1  = toRadians(point[0])
+		lat2  = math.Asin(math.Sin(lat1)*math.Cos(d) + math.Cos(lat1)*math.Sin(d)*math.Cos(theta))
+		lng2  = lng1 + math.Atan2(math.Sin(theta)*math.Sin(d)*math.Cos(lat1), math.Cos(d)-math.Sin(lat1)*math.Sin(lat2))
+	)
+	return Point{angNormalize(toDegrees(lng2)), toDegrees(lat2), point[2], point[3]}
+}
+
+// Midpoint returns the point halfway along the great circle path from the point
+// to another point.
+func (point Point) Midpoint(to Point) Point {
+	return point.IntermediatePoint(to, 0.5)
+}
+
+// IntermediatePoint returns the point at the given fraction of the way along
+// the great circle path from the point to another point,
+// where 0 is the point and 1 is the other point.
+// Z and M are interpolated linearly.
+// The path between antipodal points is not defined, so the result is not either.
+func (point Point) IntermediatePoint(to Point, fraction float64) Point {
+	var (
+		d    = angularDistance(point, to)
+		zm   = func(i int) float64 { return point[i] + fraction*(to[i]-point[i]) }
+		lat1 = toRadians(point[1])
+		lng1 = toRadians(point[0])
+		lat2 = toRadians(to[1])
+		lng2 = toRadians(to[0])
+	)
+	if d == 0 {
+		return Point{point[0], point[1], zm(2), zm(3)}
+	}
+	var (
+		a = math.Sin((1-fraction)*d) / math.Sin(d)
+		b = math.Sin(fraction*d) / math.Sin(d)
+		x = a*math.Cos(lat1)*math.Cos(lng1) + b*math.Cos(lat2)*math.Cos(lng2)
+		y = a*math.Cos(lat1)*math.Sin(lng1) + b*math.Cos(lat2)*math.Sin(lng2)
+		z = a*math.Sin(lat1) + b*math.Sin(lat2)
+	)
+	return Point{
+		toDegrees(math.Atan2(y, x)),
+		toDegrees(math.Atan2(z, math.Hypot(x, y))),
+		zm(2),
+		zm(3),
+	}
+}
+
+// Densify returns the line with points added along the great circle path
+// between each pair of consecutive points, so that no two consecutive points
+// are more than spacing meters apart.
+// The points added to each segment are evenly spaced.
+// A copy of the line is returned if spacing is not positive.
+func (line Line) Densify(spacing float64) Line {
+	dense := Line{}
+	for i, p := range line {
+		if i > 0 && spacing > 0 {
+			prev := Point(line[i-1])
+			n := math.Ceil(earthRadiusMeters * angularDistance(prev, p) / spacing)
+			for j := 1.0; j < n; j++ {
+				dense = append(dense, prev.IntermediatePoint(p, j/n))
+			}
+		}
+		dense = append(dense, p)
+	}
+	return dense
+}
+
+// Densify densifies each line, see Line.Densify.
+func (multiLine MultiLine) Densify(spacing float64) MultiLine {
+	dense := make(MultiLine, len(multiLine))
+	for i, line := range multiLine {
+		dense[i] = Line(line).Densify(spacing)
+	}
+	return dense
+}
+
+// angularDistance returns the angle in radians between two points
+// at the center of the sphere, using the haversine formula.
+func angularDistance(a, b Point) float64 {
+	var (
+		lat1 = toRadians(a[1])
+		lat2 = toRadians(b[1])
+		dLat = toRadians(b[1] - a[1])
+		dLng = toRadians(b[0] - a[0])
+		h    = (math.Sin(dLat/2) * math.Sin(dLat/2)) +
+			(math.Cos(lat1) * math.Cos(lat2) * math.Sin(dLng/2) * math.Sin(dLng/2))
+	)
+	return 2 * math.Atan2(math.Sqrt(h), math.Sqrt(1-h))
+}
+
+// normalizeBearing reduces a bearing in degrees to the range [0, 360).
+func normalizeBearing(bearing float64) float64 {
+	bearing = math.Mod(bearing, 360)
+	if bearing < 0 {
+		bearing += 360
+	}
+	if bearing >= 360 {
+		// Adding 360 to a tiny negative bearing rounds to 360.
+		return 0
+	}
+	return bearing
+}
@@ -0,0 +1,129 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// dms converts degrees, minutes and seconds to degrees.
+func dms(d, m, s float64) float64 {
+	return math.Copysign(math.Abs(d)+m/60+s/3600, d)
+}
+
+func TestBearing(t *testing.T) {
+	var (
+		landsEnd    = Point{dms(-5, 42, 53), dms(50, 3, 59)}
+		johnOGroats = Point{dms(-3, 4, 12), dms(58, 38, 38)}
+	)
+	if expected, got := dms(9, 7, 11), landsEnd.Bearing(johnOGroats); math.Abs(expected-got) > 1e-3 {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	if expected, got := dms(11, 16, 31), landsEnd.FinalBearing(johnOGroats); math.Abs(expected-got) > 1e-3 {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	for i, testcase := range []struct {
+		From, To Point
+		Bearing  float64
+	}{
+		{From: Point{0, 0}, To: Point{0, 10}, Bearing: 0},
+		{From: Point{0, 0}, To: Point{10, 0}, Bearing: 90},
+		{From: Point{0, 10}, To: Point{0, 0}, Bearing: 180},
+		{From: Point{0, 0}, To: Point{-10, 0}, Bearing: 270},
+		{From: Point{179, 0}, To: Point{-179, 0}, Bearing: 90},
+	} {
+		if expected, got := testcase.Bearing, testcase.From.Bearing(testcase.To); math.Abs(expected-got) > 1e-9 {
+			t.Fatalf("(case %d) expected %f, got %f", i, expected, got)
+		}
+	}
+}
+
+func TestDestination(t *testing.T) {
+	var (
+		start    = Point{dms(-1, 43, 47), dms(53, 19, 14), 5, 6}
+		expected = Point{dms(0, 8, 0), dms(53, 11, 18), 5, 6}
+		got      = start.Destination(124.8e3, dms(96, 1, 18))
+	)
+	if math.Abs(expected[0]-got[0]) > 1e-3 || math.Abs(expected[1]-got[1]) > 1e-3 || expected[2] != got[2] || expected[3] != got[3] {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	if expected, got := (Point{-170, 0}), (Point{170, 0}).Destination(earthRadiusMeters*toRadians(20), 90); math.Abs(expected[0]-got[0]) > 1e-9 || math.Abs(got[1]) > 1e-9 {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	for _, bearing := range []float64{0, 45, 135, 200, 315} {
+		p := start.Destination(1000e3, bearing)
+		if d := earthRadiusMeters * angularDistance(start, p); math.Abs(d-1000e3) > 1e-6 {
+			t.Fatalf("expected a distance of 1000 km, got %f", d)
+		}
+		if got := start.Bearing(p); math.Abs(bearing-got) > 1e-9 {
+			t.Fatalf("expected %f, got %f", bearing, got)
+		}
+	}
+}
+
+func TestMidpoint(t *testing.T) {
+	var (
+		landsEnd    = Point{dms(-5, 42, 53), dms(50, 3, 59), 0, 10}
+		johnOGroats = Point{dms(-3, 4, 12), dms(58, 38, 38), 100, 20}
+		expected    = Point{dms(-4, 31, 50), dms(54, 21, 44), 50, 15}
+		got         = landsEnd.Midpoint(johnOGroats)
+	)
+	if math.Abs(expected[0]-got[0]) > 1e-3 || math.Abs(expected[1]-got[1]) > 1e-3 || expected[2] != got[2] || expected[3] != got[3] {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestIntermediatePoint(t *testing.T) {
+	var (
+		a = Point{-73.8, 40.6}
+		b = Point{-0.5, 51.6}
+		d = angularDistance(a, b)
+	)
+	for i, fraction := range []float64{0, 0.1, 0.25, 0.5, 0.9, 1} {
+		p := a.IntermediatePoint(b, fraction)
+		if expected, got := fraction*d, angularDistance(a, p); math.Abs(expected-got) > 1e-12 {
+			t.Fatalf("(case %d) expected %g, got %g", i, expected, got)
+		}
+		if expected, got := (1-fraction)*d, angularDistance(p, b); math.Abs(expected-got) > 1e-12 {
+			t.Fatalf("(case %d) expected %g, got %g", i, expected, got)
+		}
+	}
+	if expected, got := (Point{1, 2, 3, 4}), (Point{1, 2, 3, 4}).IntermediatePoint(Point{1, 2, 3, 4}, 0.5); !expected.Equal(&got) {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestDensify(t *testing.T) {
+	var (
+		line    = Line{{-73.8, 40.6}, {-0.5, 51.6}, {-0.5, 51.6}, {-0.4, 51.6}}
+		spacing = 100e3
+		dense   = line.Densify(spacing)
+	)
+	// The first segment is about 5537 km, so it is split into 56 pieces,
+	// and the other segments are shorter than the spacing.
+	if expected, got := 1+56+1+1, len(dense); expected != got {
+		t.Fatalf("expected %d points, got %d", expected, got)
+	}
+	for i := 1; i < len(dense); i++ {
+		if d := earthRadiusMeters * angularDistance(dense[i-1], dense[i]); d > spacing {
+			t.Fatalf("expected points at most %f apart, got %f", spacing, d)
+		}
+	}
+	for _, p := range line {
+		if !containsXY(dense, p) {
+			t.Fatalf("expected %v to be kept", p)
+		}
+	}
+	if expected, got := line, line.Densify(0); !expected.Equal(&got) {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	multi := MultiLine{line, {{0, 0}, {0, 1}}}.Densify(spacing)
+	if expected, got := 2, len(multi); expected != got {
+		t.Fatalf("expected %d lines, got %d", expected, got)
+	}
+	if expected, got := dense, Line(multi[0]); !expected.Equal(&got) {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if expected, got := 3, len(multi[1]); expected != got {
+		t.Fatalf("expected %d points, got %d", expected, got)
+	}
+}
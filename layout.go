@@ -0,0 +1,306 @@
+package geo
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"math"
+)
+
+// Layout describes which ordinates the coordinates of a geometry carry.
+// Every coordinate is stored as X, Y, Z, M; the layout says which of
+// Z (elevation) and M (measure) are meaningful.
+type Layout int
+
+// Coordinate layouts.
+const (
+	XY Layout = iota
+	XYZ
+	XYM
+	XYZM
+)
+
+// HasZ returns true if the layout has a Z ordinate.
+func (layout Layout) HasZ() bool {
+	return layout == XYZ || layout == XYZM
+}
+
+// HasM returns true if the layout has an M ordinate.
+func (layout Layout) HasM() bool {
+	return layout == XYM || layout == XYZM
+}
+
+// Dims returns the number of ordinates in each coordinate.
+func (layout Layout) Dims() int {
+	switch layout {
+	case XYZ, XYM:
+		return 3
+	case XYZM:
+		return 4
+	}
+	return 2
+}
+
+// String returns the name of the layout, e.g. "XYZM".
+func (layout Layout) String() string {
+	switch layout {
+	case XYZ:
+		return "XYZ"
+	case XYM:
+		return "XYM"
+	case XYZM:
+		return "XYZM"
+	}
+	return "XY"
+}
+
+// wktDimension returns the Well Known Text dimension marker for the layout.
+func (layout Layout) wktDimension() string {
+	switch layout {
+	case XYZ:
+		return zWKTTag
+	case XYM:
+		return mWKTTag
+	case XYZM:
+		return zmWKTTag
+	}
+	return ""
+}
+
+// layoutFor returns the layout with the given Z and M ordinates.
+func layoutFor(z, m bool) Layout {
+	switch {
+	case z && m:
+		return XYZM
+	case z:
+		return XYZ
+	case m:
+		return XYM
+	}
+	return XY
+}
+
+// LayoutOf returns the coordinate layout of a geometry.
+// Geometries decorated with WithLayout report that layout,
+// otherwise the layout is inferred from the coordinates:
+// a geometry has Z (or M) if any of its coordinates has a nonzero Z (or M).
+//
+// The decoders store a Z or M of zero that they read as negative zero,
+// which equals zero but counts as nonzero here, so that e.g. a Point scanned
+// from "POINT Z (1 2 0)" is still 3D. The encoders write it as 0.
+func LayoutOf(g Geometry) Layout {
+	for {
+		switch v := g.(type) {
+		case *layoutGeometry:
+			return v.Layout
+		case *boundingBox:
+			g = v.Geometry
+		case *sridGeometry:
+			g = v.Geometry
+		case *Feature:
+			g = v.Geometry
+		case nil:
+			return XY
+		default:
+			lv := &layoutVisitor{}
+			g.VisitCoordinates(lv)
+			return layoutFor(lv.z, lv.m)
+		}
+	}
+}
+
+// layoutVisitor records whether any visited point has a nonzero Z or M ordinate.
+type layoutVisitor struct {
+	z, m bool
+}
+
+// Visit visits a point.
+func (v *layoutVisitor) Visit(p Point) {
+	if p[2] != 0 || math.Signbit(p[2]) {
+		v.z = true
+	}
+	if p[3] != 0 || math.Signbit(p[3]) {
+		v.m = true
+	}
+}
+
+// decodedZero is how a decoded Z or M of zero is stored, see LayoutOf.
+var decodedZero = math.Copysign(0, -1)
+
+// layoutMarker is a Transformer that stores the Z and M ordinates of a layout
+// that are zero as decodedZero.
+type layoutMarker Layout
+
+// Transform transforms a point.
+func (lm layoutMarker) Transform(p Point) Point {
+	if Layout(lm).HasZ() && p[2] == 0 {
+		p[2] = decodedZero
+	}
+	if Layout(lm).HasM() && p[3] == 0 {
+		p[3] = decodedZero
+	}
+	return p
+}
+
+// unmarked returns an ordinate as it is encoded, i.e. zero for decodedZero.
+func unmarked(v float64) float64 {
+	if v == 0 {
+		return 0
+	}
+	return v
+}
+
+// withDecodedLayout marks the zero ordinates of a geometry that was decoded
+// with the given layout, see LayoutOf, and decorates it with the layout
+// if it still cannot be inferred, which is only the case if it is empty,
+// e.g. "LINESTRING Z EMPTY". The members of collections are marked
+// when they are decoded and keep their own layouts, so only empty
+// collections are decorated.
+func withDecodedLayout(layout Layout, g Geometry) Geometry {
+	if gc, ok := g.(*GeometryCollection); ok && len(*gc) > 0 {
+		return g
+	}
+	g.Transform(layoutMarker(layout))
+	if LayoutOf(g) == layout {
+		return g
+	}
+	return WithLayout(layout, g)
+}
+
+// withoutLayout returns the geometry under a WithLayout decorator, if there is one.
+func withoutLayout(g Geometry) Geometry {
+	if lg, ok := g.(*layoutGeometry); ok {
+		return lg.Geometry
+	}
+	return g
+}
+
+// layoutEncoder is implemented by geometries that can be encoded
+// as GeoJSON and Well Known Text with a given layout.
+type layoutEncoder interface {
+	geoJSON(layout Layout) ([]byte, error)
+	wkt(layout Layout) string
+}
+
+// WithLayout returns a geometry that is always encoded with the given layout.
+// This is useful when the inferred layout is wrong, e.g. for a 3D line
+// that lies entirely at Z=0, or for measures that are all zero.
+// GeoJSON positions have no M ordinate, so layouts with M are
+// encoded as [x, y, z, m].
+//
+// Decoded geometries keep their layout even if its ordinates are all zero,
+// see LayoutOf, so a Line scanned from "LINESTRING Z (0 0 0, 1 1 0)" is 3D.
+// Empty geometries have no coordinates to keep it, so ScanGeometry,
+// UnmarshalWKB and UnmarshalJSON decorate them with WithLayout, and
+// WithLayout(XY, &Line{}) can be used as the destination of a scan instead
+// of a Line: it replaces both the geometry and the layout with those decoded.
+func WithLayout(layout Layout, geom Geometry) Geometry {
+	return &layoutGeometry{
+		Geometry: geom,
+		Layout:   layout,
+	}
+}
+
+// layoutGeometry is a utility type for decorating geometries with a layout.
+type layoutGeometry struct {
+	Geometry
+
+	Layout Layout
+}
+
+// Equal compares two geometries that have layouts.
+func (lg *layoutGeometry) Equal(g Geometry) bool {
+	other, ok := g.(*layoutGeometry)
+	if !ok {
+		return false
+	}
+	if lg.Layout != other.Layout {
+		return false
+	}
+	return lg.Geometry.Equal(other.Geometry)
+}
+
+// Bounds returns the envelope of the geometry, which has Z if the layout does.
+func (lg *layoutGeometry) Bounds() Envelope {
+	env := lg.Geometry.Bounds()
+	if env.HasZ = lg.Layout.HasZ(); !env.HasZ && !env.IsEmpty() {
+		env.Min[2], env.Max[2] = 0, 0
+	}
+	return env
+}
+
+// MarshalJSON marshals the geometry to GeoJSON using the layout.
+func (lg *layoutGeometry) MarshalJSON() ([]byte, error) {
+	if enc, ok := lg.Geometry.(layoutEncoder); ok {
+		return enc.geoJSON(lg.Layout)
+	}
+	return lg.Geometry.MarshalJSON()
+}
+
+// MarshalWKB returns the Well Known Binary representation
+// of the geometry using the layout and the given byte order.
+func (lg *layoutGeometry) MarshalWKB(order binary.ByteOrder) ([]byte, error) {
+	return marshalWKB(lg, order)
+}
+
+// String returns the Well Known Text representation of the geometry using the layout.
+func (lg *layoutGeometry) String() string {
+	if sg, ok := lg.Geometry.(*sridGeometry); ok && sg.Geometry != nil {
+		return WithSRID(sg.SRID, WithLayout(lg.Layout, sg.Geometry)).String()
+	}
+	if enc, ok := lg.Geometry.(layoutEncoder); ok {
+		return enc.wkt(lg.Layout)
+	}
+	return lg.Geometry.String()
+}
+
+// Scan scans the geometry and its layout from Well Known Text or Well Known Binary.
+// The wrapped geometry is replaced by the one that is scanned.
+func (lg *layoutGeometry) Scan(src interface{}) error {
+	return scan(lg, src)
+}
+
+// scan scans the geometry and its layout from Well Known Text.
+func (lg *layoutGeometry) scan(s string) error {
+	g, srid, err := parseWKT(s)
+	if err != nil {
+		return err
+	}
+	lg.decoded(g, srid)
+	return nil
+}
+
+// UnmarshalJSON unmarshals the geometry and its layout from GeoJSON.
+// The wrapped geometry is replaced by the one that is unmarshalled.
+func (lg *layoutGeometry) UnmarshalJSON(data []byte) error {
+	g, err := UnmarshalJSON(data)
+	if err != nil {
+		return err
+	}
+	lg.decoded(g, 0)
+	return nil
+}
+
+// UnmarshalWKB unmarshals the geometry and its layout from Well Known Binary.
+// The wrapped geometry is replaced by the one that is unmarshalled.
+func (lg *layoutGeometry) UnmarshalWKB(data []byte) error {
+	g, srid, err := unmarshalWKB(data)
+	if err != nil {
+		return err
+	}
+	lg.decoded(g, srid)
+	return nil
+}
+
+// decoded replaces the wrapped geometry and the layout with a decoded geometry.
+func (lg *layoutGeometry) decoded(g Geometry, srid int) {
+	lg.Layout, g = LayoutOf(g), withoutLayout(g)
+	if srid != 0 {
+		g = WithSRID(srid, g)
+	}
+	lg.Geometry = g
+}
+
+// Value returns Well Known Text for the geometry using the layout.
+func (lg *layoutGeometry) Value() (driver.Value, error) {
+	return lg.String(), nil
+}
@@ -0,0 +1,330 @@
+package geo
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestLayoutOf(t *testing.T) {
+	for i, testcase := range []struct {
+		Input    Geometry
+		Expected Layout
+	}{
+		{Input: &Point{1, 2}, Expected: XY},
+		{Input: &Point{1, 2, 3}, Expected: XYZ},
+		{Input: &Point{1, 2, 0, 4}, Expected: XYM},
+		{Input: &Point{1, 2, 3, 4}, Expected: XYZM},
+		{Input: &Line{{0, 0, 1}, {1, 1, 0, 5}}, Expected: XYZM},
+		{Input: &GeometryCollection{&Point{1, 2}, &Point{1, 2, 0, 1}}, Expected: XYM},
+		{Input: WithLayout(XYZ, &Point{1, 2}), Expected: XYZ},
+		{Input: WithSRID(4326, WithLayout(XYM, &Point{1, 2})), Expected: XYM},
+		{Input: &Feature{Geometry: WithLayout(XYZM, &Point{1, 2})}, Expected: XYZM},
+	} {
+		if expected, got := testcase.Expected, LayoutOf(testcase.Input); expected != got {
+			t.Fatalf("(case %d) expected %s, got %s", i, expected, got)
+		}
+	}
+
+	for i, testcase := range []struct {
+		Layout Layout
+		Dims   int
+		Name   string
+	}{
+		{Layout: XY, Dims: 2, Name: "XY"},
+		{Layout: XYZ, Dims: 3, Name: "XYZ"},
+		{Layout: XYM, Dims: 3, Name: "XYM"},
+		{Layout: XYZM, Dims: 4, Name: "XYZM"},
+	} {
+		if expected, got := testcase.Dims, testcase.Layout.Dims(); expected != got {
+			t.Fatalf("(case %d) expected %d, got %d", i, expected, got)
+		}
+		if expected, got := testcase.Name, testcase.Layout.String(); expected != got {
+			t.Fatalf("(case %d) expected %s, got %s", i, expected, got)
+		}
+	}
+}
+
+func TestLayoutWKT(t *testing.T) {
+	for i, testcase := range []struct {
+		Input    Geometry
+		Expected string
+	}{
+		{Input: &Point{1, 2, 0, 4}, Expected: `POINT M (1 2 4)`},
+		{Input: &Point{1, 2, 3, 4}, Expected: `POINT ZM (1 2 3 4)`},
+		{Input: &Line{{0, 0, 0, 1}, {1, 1, 0, 2}}, Expected: `LINESTRING M (0 0 1, 1 1 2)`},
+		{Input: WithLayout(XYZ, &Point{1, 2}), Expected: `POINT Z (1 2 0)`},
+		{Input: WithLayout(XY, &Point{1, 2, 3}), Expected: `POINT(1 2)`},
+		{Input: WithLayout(XYM, &MultiPoint{{1, 2}, {3, 4}}), Expected: `MULTIPOINT M (1 2 0, 3 4 0)`},
+		{Input: WithLayout(XYZM, &Polygon{{{0, 0}, {1, 0}, {0, 1}, {0, 0}}}), Expected: `POLYGON ZM ((0 0 0 0, 1 0 0 0, 0 1 0 0, 0 0 0 0))`},
+		{Input: WithLayout(XYZ, &GeometryCollection{&Point{1, 2}}), Expected: `GEOMETRYCOLLECTION(POINT Z (1 2 0))`},
+	} {
+		if expected, got := testcase.Expected, testcase.Input.String(); expected != got {
+			t.Fatalf("(case %d) expected %s, got %s", i, expected, got)
+		}
+	}
+
+	// Parse
+	for i, testcase := range []struct {
+		Input    string
+		Expected Geometry
+	}{
+		{Input: `POINT M (1 2 4)`, Expected: &Point{1, 2, 0, 4}},
+		{Input: `POINTM(1 2 4)`, Expected: &Point{1, 2, 0, 4}},
+		{Input: `POINT ZM (1 2 3 4)`, Expected: &Point{1, 2, 3, 4}},
+		{Input: `POINT(1 2 3 4)`, Expected: &Point{1, 2, 3, 4}},
+		{Input: `linestring m (0 0 1, 1 1 2)`, Expected: &Line{{0, 0, 0, 1}, {1, 1, 0, 2}}},
+		{Input: `SRID=4326;MULTIPOINTM(0 0 1)`, Expected: WithSRID(4326, &MultiPoint{{0, 0, 0, 1}})},
+		{Input: `GEOMETRYCOLLECTION M (POINT(1 2 3))`, Expected: &GeometryCollection{&Point{1, 2, 0, 3}}},
+	} {
+		g, err := ScanGeometry(testcase.Input)
+		if err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if !testcase.Expected.Equal(g) {
+			t.Fatalf("(case %d) expected %s, got %s", i, testcase.Expected, g)
+		}
+	}
+
+	// A dimension marker is not a geometry type.
+	scanTestcases{
+		{Input: `POINTX(1 2)`, Instance: &Point{}},
+		{Input: `LINESTRINGM(1 2)`, Instance: &Point{}},
+	}.fail(t)
+}
+
+func TestLayoutMarshalJSON(t *testing.T) {
+	marshalTestcases{
+		{
+			Input:    &Point{1, 2, 0, 4},
+			Expected: `{"type":"Point","coordinates":[1,2,0,4]}`,
+		},
+		{
+			Input:    WithLayout(XYZ, &Point{1, 2}),
+			Expected: `{"type":"Point","coordinates":[1,2,0]}`,
+		},
+		{
+			Input:    WithLayout(XYZ, &GeometryCollection{&Line{{1, 2}}}),
+			Expected: `{"type":"GeometryCollection","geometries":[{"type":"LineString","coordinates":[[1,2,0]]}]}`,
+		},
+	}.pass(t)
+
+	p := &Point{}
+	if err := p.UnmarshalJSON([]byte(`{"type":"Point","coordinates":[1,2,3,4]}`)); err != nil {
+		t.Fatal(err)
+	}
+	if expected := (&Point{1, 2, 3, 4}); !expected.Equal(p) {
+		t.Fatalf("expected %s, got %s", expected, p)
+	}
+}
+
+func TestLayoutWKB(t *testing.T) {
+	for i, testcase := range []struct {
+		Input    Geometry
+		Expected string
+	}{
+		{
+			Input:    &Point{1, 2, 0, 4},
+			Expected: "01d1070000000000000000f03f00000000000000400000000000001040",
+		},
+		{
+			Input:    &Point{1, 2, 3, 4},
+			Expected: "01b90b0000000000000000f03f000000000000004000000000000008400000000000001040",
+		},
+		{
+			Input:    WithLayout(XYZ, &Point{1, 2}),
+			Expected: "01e9030000000000000000f03f00000000000000400000000000000000",
+		},
+		{
+			Input:    WithSRID(4326, &Point{1, 2, 0, 4}),
+			Expected: "0101000060e6100000000000000000f03f00000000000000400000000000001040",
+		},
+	} {
+		data, err := testcase.Input.(wkbMarshaler).MarshalWKB(binary.LittleEndian)
+		if err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if expected, got := testcase.Expected, hex.EncodeToString(data); expected != got {
+			t.Fatalf("(case %d) expected %s, got %s", i, expected, got)
+		}
+		if _, err := UnmarshalWKB(data); err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+	}
+
+	// Round trip measures through WKB.
+	for i, input := range []Geometry{
+		&Line{{0, 0, 0, 1}, {1, 1, 0, 2}},
+		&MultiPolygon{{{{0, 0, 1, 1}, {1, 0, 1, 2}, {0, 1, 1, 3}, {0, 0, 1, 1}}}},
+		&GeometryCollection{&Point{1, 2, 0, 3}, &Point{4, 5, 0, 6}},
+	} {
+		data, err := input.(wkbMarshaler).MarshalWKB(binary.BigEndian)
+		if err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		g, err := UnmarshalWKB(data)
+		if err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if !input.Equal(g) {
+			t.Fatalf("(case %d) expected %s, got %s", i, input, g)
+		}
+	}
+}
+
+func TestLayoutRoundTrip(t *testing.T) {
+	// Zero Z and M ordinates keep their layout through WKT and WKB.
+	for i, wkt := range []string{
+		`POINT Z (1 2 0)`,
+		`POINT M (1 2 0)`,
+		`POINT ZM (1 2 0 0)`,
+		`LINESTRING Z (0 0 0, 1 1 0)`,
+		`LINESTRING M (0 0 0, 1 1 0)`,
+		`POLYGON ZM ((0 0 0 0, 1 0 0 0, 0 1 0 0, 0 0 0 0))`,
+		`MULTIPOINT Z (1 2 0)`,
+		`GEOMETRYCOLLECTION(POINT Z (1 2 0), POINT(1 2))`,
+		`SRID=4326;LINESTRING M (0 0 0, 1 1 0)`,
+	} {
+		g, err := ScanGeometry(wkt)
+		if err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if got := g.String(); wkt != got {
+			t.Fatalf("(case %d) expected %s, got %s", i, wkt, got)
+		}
+		data, err := g.(wkbMarshaler).MarshalWKB(binary.BigEndian)
+		if err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if g, err = UnmarshalWKB(data); err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if got := g.String(); wkt != got {
+			t.Fatalf("(case %d) expected %s after WKB, got %s", i, wkt, got)
+		}
+	}
+
+	// GeoJSON positions keep their number of ordinates.
+	for i, geojson := range []string{
+		`{"type":"Point","coordinates":[1,2,0]}`,
+		`{"type":"LineString","coordinates":[[1,2,0],[3,4,0]]}`,
+		`{"type":"Polygon","coordinates":[[[0,0,0,0],[1,0,0,0],[0,1,0,0],[0,0,0,0]]]}`,
+		`{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[1,2,0]}]}`,
+		`{"type":"Feature","geometry":{"type":"LineString","coordinates":[[1,2,0],[3,4,0]]},"properties":null}`,
+	} {
+		g, err := UnmarshalJSON([]byte(geojson))
+		if err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		data, err := g.MarshalJSON()
+		if err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if got := string(data); geojson != got {
+			t.Fatalf("(case %d) expected %s, got %s", i, geojson, got)
+		}
+	}
+
+	// Geometries that are scanned or unmarshalled keep zero Z and M ordinates.
+	for i, testcase := range []struct {
+		WKT      string
+		Instance Geometry
+	}{
+		{WKT: `POINT Z (1 2 0)`, Instance: &Point{}},
+		{WKT: `POINT M (1 2 0)`, Instance: &Point{}},
+		{WKT: `LINESTRING Z (0 0 0, 1 1 0)`, Instance: &Line{}},
+		{WKT: `LINESTRING M (0 0 0, 1 1 0)`, Instance: &Line{}},
+		{WKT: `POLYGON ZM ((0 0 0 0, 1 0 0 0, 0 1 0 0, 0 0 0 0))`, Instance: &Polygon{}},
+		{WKT: `MULTIPOINT M (1 2 0)`, Instance: &MultiPoint{}},
+		{WKT: `MULTILINESTRING Z ((0 0 0, 1 1 0))`, Instance: &MultiLine{}},
+	} {
+		if err := testcase.Instance.Scan(testcase.WKT); err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if expected, got := testcase.WKT, testcase.Instance.String(); expected != got {
+			t.Fatalf("(case %d) expected %s, got %s", i, expected, got)
+		}
+		data, err := testcase.Instance.(wkbMarshaler).MarshalWKB(binary.LittleEndian)
+		if err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if err := testcase.Instance.(wkbUnmarshaler).UnmarshalWKB(data); err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if expected, got := testcase.WKT, testcase.Instance.String(); expected != got {
+			t.Fatalf("(case %d) expected %s after WKB, got %s", i, expected, got)
+		}
+	}
+	line := &Line{}
+	if err := line.UnmarshalJSON([]byte(`{"type":"LineString","coordinates":[[1,2,0],[3,4,0]]}`)); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := `LINESTRING Z (1 2 0, 3 4 0)`, line.String(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+
+	// The zero ordinates are encoded as zero.
+	pt := &Point{}
+	if err := pt.Scan(`POINT Z (1 2 0)`); err != nil {
+		t.Fatal(err)
+	}
+	if expected := (&Point{1, 2}); !expected.Equal(pt) {
+		t.Fatalf("expected %s, got %s", expected, pt)
+	}
+	data, err := pt.MarshalWKB(binary.LittleEndian)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := "01e9030000000000000000f03f00000000000000400000000000000000", hex.EncodeToString(data); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if data, err = pt.MarshalJSON(); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := `{"type":"Point","coordinates":[1,2,0]}`, string(data); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+
+	// An empty geometry keeps its layout when it is scanned into WithLayout.
+	empty := WithLayout(XY, &Line{})
+	if err := empty.Scan(`LINESTRING Z EMPTY`); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := XYZ, LayoutOf(empty); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestLayoutWKBMembers(t *testing.T) {
+	// The members of multi geometries must have the layout of the multi geometry.
+	for i, input := range []string{
+		// MULTIPOINT with a POINT Z
+		"0104000000010000000101000080000000000000f03f00000000000000400000000000000000",
+		// MULTILINESTRING M with a LINESTRING
+		"01d50700000100000001020000000100000000000000000000000000000000000000",
+		// MULTIPOLYGON Z with a POLYGON ZM
+		"01ee0300000100000001bb0b000000000000",
+	} {
+		data, err := hex.DecodeString(input)
+		if err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if _, err := UnmarshalWKB(data); err == nil || !strings.Contains(err.Error(), "layout") {
+			t.Fatalf("(case %d) expected a layout error, got %v", i, err)
+		}
+	}
+
+	// The members of collections may have any layout.
+	gc, err := UnmarshalWKB([]byte{
+		0x01, 0x07, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00,
+		0x01, 0xe9, 0x03, 0x00, 0x00, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f, 0, 0, 0, 0, 0, 0, 0, 0x40, 0, 0, 0, 0, 0, 0, 0, 0,
+		0x01, 0x01, 0x00, 0x00, 0x00, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f, 0, 0, 0, 0, 0, 0, 0, 0x40,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := `GEOMETRYCOLLECTION(POINT Z (1 2 0), POINT(1 2))`, gc.String(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
@@ -2,18 +2,17 @@ package geo
 
 import (
 	"database/sql/driver"
-	"fmt"
+	"encoding/binary"
 )
 
 const (
 	lineWKTEmpty   = `LINESTRING EMPTY`
-	lineWKTPrefix  = `LINESTRING`
 	lineJSONPrefix = `{"type":"LineString","coordinates":[`
 	lineJSONSuffix = `]}`
 )
 
 // Line is a line.
-type Line [][3]float64
+type Line [][4]float64
 
 // Equal compares one line to another.
 func (line Line) Equal(g Geometry) bool {
@@ -24,32 +23,53 @@ func (line Line) Equal(g Geometry) bool {
 	return pointsEqual(line, *ls)
 }
 
-// Contains determines if the line contains a point.
+// Contains determines if the line contains a point,
+// including the line's endpoints.
 func (line Line) Contains(p Point) bool {
-	return pointsContain(line, p)
+	return line.Locate(p) != Exterior
 }
 
-// segmentContains returns true if p lies on the line segment that connects s1 and s2.
-func segmentContains(s1, s2, p [3]float64) bool {
-	// Return false if p is outside of the bounding box around s1 and s2.
-	if (p[0] > s1[0] && p[0] > s2[0]) || (p[0] < s1[0] && p[0] < s2[0]) {
-		return false
+// Locate returns the location of the point relative to the line.
+// The endpoints of a line are its boundary, unless the line is closed.
+func (line Line) Locate(p Point) Location {
+	if p.IsEmpty() {
+		return Exterior
 	}
-	if (p[1] > s1[1] && p[1] > s2[1]) || (p[1] < s1[1] && p[1] < s2[1]) {
-		return false
+	for _, end := range lineEndpoints(line) {
+		if end[0] == p[0] && end[1] == p[1] {
+			return Boundary
+		}
+	}
+	if lineContains(line, p) {
+		return Interior
 	}
-	// Equal the slope of the segment between s1 and p
-	// to the slope of the segment between s1 and s2.
-	var (
-		segmentSlope = (s2[1] - s1[1]) / (s2[0] - s1[0])
-		pSlope       = (p[1] - s1[1]) / (p[0] - s1[0])
-	)
-	return segmentSlope == pSlope
+	return Exterior
+}
+
+// IsEmpty returns true if the line has no coordinates.
+func (line Line) IsEmpty() bool {
+	return isEmpty(&line)
+}
+
+// Bounds returns the envelope of the line.
+func (line Line) Bounds() Envelope {
+	return bounds(&line)
 }
 
 // MarshalJSON marshals the line to JSON.
 func (line Line) MarshalJSON() ([]byte, error) {
-	return pointsMarshalJSON(line, lineJSONPrefix, lineJSONSuffix), nil
+	return line.geoJSON(LayoutOf(&line))
+}
+
+// geoJSON marshals the line to JSON using the given layout.
+func (line Line) geoJSON(layout Layout) ([]byte, error) {
+	return pointsMarshalJSON(line, lineJSONPrefix, lineJSONSuffix, layout), nil
+}
+
+// MarshalWKB returns the Well Known Binary representation of the line
+// using the given byte order.
+func (line Line) MarshalWKB(order binary.ByteOrder) ([]byte, error) {
+	return marshalWKB(&line, order)
 }
 
 // Scan scans a line from Well Known Text.
@@ -59,23 +79,25 @@ func (line *Line) Scan(src interface{}) error {
 
 // scan scans a line from a Well Known Text string.
 func (line *Line) scan(s string) error {
-	if len(s) <= len(lineWKTPrefix) {
-		return fmt.Errorf("could not scan line from %s", s)
-	}
-	points, err := pointsScan(s[len(lineWKTPrefix):])
+	g, _, err := parseWKT(s, lineWKTTag)
 	if err != nil {
 		return err
 	}
-	*line = points
+	*line = *withoutLayout(g).(*Line)
 	return nil
 }
 
 // String converts the line to a string.
 func (line Line) String() string {
+	return line.wkt(LayoutOf(&line))
+}
+
+// wkt converts the line to a string using the given layout.
+func (line Line) wkt(layout Layout) string {
 	if len(line) == 0 {
 		return lineWKTEmpty
 	}
-	return lineWKTPrefix + pointsString(line)
+	return wktPrefix(lineWKTTag, layout) + pointsString(line, layout)
 }
 
 // UnmarshalJSON unmarshals a line from GeoJSON.
@@ -88,6 +110,16 @@ func (line *Line) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// UnmarshalWKB unmarshals the line from Well Known Binary.
+func (line *Line) UnmarshalWKB(data []byte) error {
+	g, _, err := unmarshalWKB(data, wkbLine)
+	if err != nil {
+		return err
+	}
+	*line = *withoutLayout(g).(*Line)
+	return nil
+}
+
 // Value returns a driver Value.
 func (line Line) Value() (driver.Value, error) {
 	return line.String(), nil
@@ -95,9 +127,9 @@ func (line Line) Value() (driver.Value, error) {
 
 // Transform transforms the geometry point by point.
 func (line *Line) Transform(t Transformer) {
-	nl := make([][3]float64, len(*line))
+	nl := make([][4]float64, len(*line))
 	for i, point := range *line {
-		nl[i] = [3]float64(t.Transform(point))
+		nl[i] = [4]float64(t.Transform(point))
 	}
 	*line = nl
 }
@@ -15,6 +15,7 @@ func TestLineEqual(t *testing.T) {
 			&Line{{1.2, 3.4}, {5.6, 7.8}, {1.4, 9.3}},
 			&Line{{1.2, 3.4}, {5.6, 7.8}, {1.4, 9.3}, {-1.4, 7.3}},
 			&Line{{1.2, 3.4}, {5.6, 7.8}, {1.4, 9.3}, {-1.7, 7.5}},
+			&Line{{1.2, 3.4, 1}, {5.6, 7.8}, {1.4, 9.3}, {-1.7, 7.3}},
 			&Polygon{{{1.2, 3.4}, {5.6, 7.8}, {1.4, 9.3}, {-1.7, 7.5}}},
 			&Point{1.2, 3.4},
 		},
@@ -49,6 +50,10 @@ func TestLineMarshal(t *testing.T) {
 			Input:    &Line{{1.2, 3.4}, {5.6, 7.8}},
 			Expected: `{"type":"LineString","coordinates":[[1.2,3.4],[5.6,7.8]]}`,
 		},
+		{
+			Input:    &Line{{1.2, 3.4, 100}, {5.6, 7.8, 0}},
+			Expected: `{"type":"LineString","coordinates":[[1.2,3.4,100],[5.6,7.8,0]]}`,
+		},
 	}.pass(t)
 }
 
@@ -107,6 +112,10 @@ func TestLineString(t *testing.T) {
 			},
 			Expected: `LINESTRING(1.2 3.4, 5.6 7.8, 5.8 1.6)`,
 		},
+		{
+			Input:    Line{{1.2, 3.4, 10}, {5.6, 7.8, 20}},
+			Expected: `LINESTRING Z (1.2 3.4 10, 5.6 7.8 20)`,
+		},
 	} {
 		if expected, got := testcase.Expected, testcase.Input.String(); expected != got {
 			t.Fatalf("expected %s, got %s", expected, got)
@@ -121,6 +130,11 @@ func TestLineUnmarshal(t *testing.T) {
 			Instance: &Line{},
 			Expected: &Line{{0, 0}, {1, 1}},
 		},
+		{
+			Input:    []byte(`{"type":"LineString","coordinates":[[0,0,10],[1,1,20]]}`),
+			Instance: &Line{},
+			Expected: &Line{{0, 0, 10}, {1, 1, 20}},
+		},
 	}.pass(t)
 
 	unmarshalTestcases{
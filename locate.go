@@ -0,0 +1,181 @@
+package geo
+
+// Location is the location of a point relative to a geometry,
+// as defined by the OGC Simple Features specification.
+type Location int
+
+// Locations.
+const (
+	// Exterior means the point is outside the geometry.
+	Exterior Location = iota
+
+	// Boundary means the point is on the geometry's boundary,
+	// e.g. on an edge of a polygon or at an end of a line.
+	Boundary
+
+	// Interior means the point is inside the geometry.
+	Interior
+)
+
+// String returns the name of the location.
+func (loc Location) String() string {
+	switch loc {
+	case Boundary:
+		return "Boundary"
+	case Interior:
+		return "Interior"
+	}
+	return "Exterior"
+}
+
+// Locate returns the location of a point relative to any geometry.
+// The boundary of a line is its endpoints, unless it is closed,
+// and the boundary of a MultiLine is the endpoints that belong to
+// an odd number of its lines (the "mod 2" rule).
+// Points and MultiPoints have no boundary, and unlike MultiPoint.Contains,
+// Locate does not treat the points of a MultiPoint as a line.
+// Collections are treated as the union of their members,
+// so a point in the interior of any member is in the interior of the collection,
+// and so is a point on an edge that two polygons of the collection share.
+// Empty points are always in the exterior.
+func Locate(g Geometry, p Point) Location {
+	if p.IsEmpty() {
+		return Exterior
+	}
+	switch v := g.(type) {
+	case *Point:
+		if v.Contains(p) {
+			return Interior
+		}
+	case *MultiPoint:
+		for _, q := range *v {
+			if q[0] == p[0] && q[1] == p[1] {
+				return Interior
+			}
+		}
+	case *Line:
+		return v.Locate(p)
+	case *MultiLine:
+		return v.Locate(p)
+	case *Polygon:
+		return v.Locate(p)
+	case *MultiPolygon:
+		return v.Locate(p)
+	case *Circle:
+		return v.Locate(p)
+	case *Feature:
+		if v.Geometry != nil {
+			return Locate(v.Geometry, p)
+		}
+	case *GeometryCollection:
+		return locateUnion(p, []Geometry(*v)...)
+	case *FeatureCollection:
+		members := make([]Geometry, len(*v))
+		for i, feat := range *v {
+			members[i] = feat
+		}
+		return locateUnion(p, members...)
+	case *boundingBox:
+		return Locate(v.Geometry, p)
+	case *sridGeometry:
+		return Locate(v.Geometry, p)
+	case *layoutGeometry:
+		return Locate(v.Geometry, p)
+	}
+	return Exterior
+}
+
+// locateUnion locates a point relative to the union of several geometries.
+// A point on the boundaries of members whose union is all around it,
+// e.g. on an edge that two polygons share, is in the interior of the union,
+// which is found from the edges at the point as it is by Relate.
+func locateUnion(p Point, members ...Geometry) Location {
+	loc := Exterior
+	for _, g := range members {
+		switch Locate(g, p) {
+		case Interior:
+			return Interior
+		case Boundary:
+			loc = Boundary
+		}
+	}
+	if loc != Boundary {
+		return loc
+	}
+	gc := GeometryCollection(members)
+	graph := newRelateGraph(newRelateGeometry(&gc), relateGeometry{points: [][4]float64{p}})
+	if graph.locateNode(0, graph.nodeAt[[2]float64{p[0], p[1]}]) == Interior {
+		return Interior
+	}
+	return Boundary
+}
+
+// locateInRing returns the location of a point relative to a ring,
+// which is treated as closed even if its last point is not its first.
+// It counts how many ring edges a horizontal ray from the point to
+// positive infinity crosses, using the orientation of each edge relative
+// to the point so that points on an edge or vertex are always found.
+func locateInRing(ring [][4]float64, p Point) Location {
+	crossings := 0
+	for i, a := range ring {
+		b := ring[(i+1)%len(ring)]
+
+		// The edge is entirely to the left of the point.
+		if a[0] < p[0] && b[0] < p[0] {
+			continue
+		}
+		if b[0] == p[0] && b[1] == p[1] {
+			return Boundary
+		}
+		// Horizontal edges are only checked for the point being on them.
+		if a[1] == p[1] && b[1] == p[1] {
+			if onSegment(a, b, p) {
+				return Boundary
+			}
+			continue
+		}
+		// The edge crosses the ray's line, counting its lower end but not its upper end.
+		if (a[1] > p[1] && b[1] <= p[1]) || (b[1] > p[1] && a[1] <= p[1]) {
+			orientation := orient2d(a, b, p)
+			if orientation == 0 {
+				return Boundary
+			}
+			if b[1] < a[1] {
+				orientation = -orientation
+			}
+			if orientation > 0 {
+				crossings++
+			}
+		}
+	}
+	if crossings%2 == 1 {
+		return Interior
+	}
+	return Exterior
+}
+
+// lineEndpoints returns the endpoints of a line that are on its boundary,
+// i.e. none if the line is closed or has fewer than two points.
+func lineEndpoints(line [][4]float64) [][4]float64 {
+	if len(line) < 2 {
+		return nil
+	}
+	first, last := line[0], line[len(line)-1]
+	if first[0] == last[0] && first[1] == last[1] {
+		return nil
+	}
+	return [][4]float64{first, last}
+}
+
+// lineContains returns true if the point is on any segment of the line.
+func lineContains(line [][4]float64, p Point) bool {
+	for i, vertex := range line {
+		if vertex[0] == p[0] && vertex[1] == p[1] {
+			return true
+		}
+		if i > 0 && onSegment(line[i-1], vertex, p) {
+			return true
+		}
+	}
+	return false
+}
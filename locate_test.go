@@ -0,0 +1,197 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// locateTestcases is a helper type for Locate tests.
+type locateTestcases []struct {
+	G        Geometry
+	Interior []Point
+	Boundary []Point
+	Exterior []Point
+}
+
+// test checks that Locate returns the expected location for every point.
+func (tests locateTestcases) test(t *testing.T) {
+	for i, testcase := range tests {
+		for expected, points := range map[Location][]Point{
+			Interior: testcase.Interior,
+			Boundary: testcase.Boundary,
+			Exterior: testcase.Exterior,
+		} {
+			for _, p := range points {
+				if got := Locate(testcase.G, p); expected != got {
+					t.Fatalf("(case %d) expected %s to be in the %s of %s, got %s", i, p, expected, testcase.G, got)
+				}
+			}
+		}
+	}
+}
+
+func TestLocate(t *testing.T) {
+	square := Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}}
+
+	locateTestcases{
+		{
+			G:        &square,
+			Interior: []Point{{2, 2}, {0.5, 3.9}},
+			Boundary: []Point{{0, 0}, {4, 4}, {2, 0}, {4, 1}, {0, 3.5}},
+			Exterior: []Point{{-1, 2}, {5, 2}, {2, 5}, {4.000001, 4}, {math.NaN(), math.NaN()}},
+		},
+		{
+			// Clockwise and not closed.
+			G:        &Polygon{{{0, 0}, {0, 4}, {4, 4}, {4, 0}}},
+			Interior: []Point{{2, 2}},
+			Boundary: []Point{{4, 2}, {2, 0}},
+			Exterior: []Point{{5, 2}},
+		},
+		{
+			// With a hole.
+			G: &Polygon{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+				{{2, 2}, {2, 8}, {8, 8}, {8, 2}, {2, 2}},
+			},
+			Interior: []Point{{1, 1}, {9, 5}},
+			Boundary: []Point{{2, 5}, {8, 8}, {10, 5}},
+			Exterior: []Point{{5, 5}, {11, 5}},
+		},
+		{
+			// The horizontal ray passes through vertices.
+			G:        &Polygon{{{0, 0}, {0, 4}, {2, 4}, {3, 2}, {4, 4}, {6, 4}, {8, 2}, {6, 0}, {0, 0}}},
+			Interior: []Point{{1, 2}, {4, 2}, {7, 2}},
+			Boundary: []Point{{3, 2}, {8, 2}, {7, 1}},
+			Exterior: []Point{{-1, 2}, {3, 3}, {9, 2}},
+		},
+		{
+			G:        &Polygon{},
+			Exterior: []Point{{0, 0}},
+		},
+		{
+			G: &MultiPolygon{
+				square,
+				{{{10, 0}, {14, 0}, {14, 4}, {10, 4}, {10, 0}}},
+			},
+			Interior: []Point{{2, 2}, {12, 2}},
+			Boundary: []Point{{4, 2}, {10, 2}},
+			Exterior: []Point{{7, 2}},
+		},
+		{
+			G:        &Line{{0, 0}, {2, 0}, {2, 2}},
+			Interior: []Point{{1, 0}, {2, 0}, {2, 1}},
+			Boundary: []Point{{0, 0}, {2, 2}},
+			Exterior: []Point{{1, 1}, {3, 3}},
+		},
+		{
+			// A closed line has no boundary.
+			G:        &Line{{0, 0}, {2, 0}, {2, 2}, {0, 0}},
+			Interior: []Point{{0, 0}, {1, 1}},
+			Exterior: []Point{{1, 0.5}},
+		},
+		{
+			// The shared endpoint belongs to two lines, so it is in the interior.
+			G:        &MultiLine{{{0, 0}, {1, 0}}, {{1, 0}, {1, 1}}, {{5, 5}, {6, 6}}},
+			Interior: []Point{{1, 0}, {0.5, 0}, {5.5, 5.5}},
+			Boundary: []Point{{0, 0}, {1, 1}, {5, 5}, {6, 6}},
+			Exterior: []Point{{2, 0}},
+		},
+		{
+			G:        &Point{1, 2},
+			Interior: []Point{{1, 2}},
+			Exterior: []Point{{2, 1}},
+		},
+		{
+			G:        &MultiPoint{{1, 2}, {3, 4}},
+			Interior: []Point{{3, 4}},
+			Exterior: []Point{{2, 3}},
+		},
+		{
+			G:        &GeometryCollection{&Line{{0, 2}, {2, 2}}, &square},
+			Interior: []Point{{1, 2}, {3, 3}},
+			Boundary: []Point{{4, 4}},
+			Exterior: []Point{{5, 5}},
+		},
+		{
+			G:        &FeatureCollection{{}, {Geometry: &square}},
+			Interior: []Point{{1, 1}},
+			Boundary: []Point{{0, 1}},
+			Exterior: []Point{{5, 5}},
+		},
+		{
+			// Two squares that share an edge, and meet a third at a corner.
+			G: &GeometryCollection{
+				&Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+				&Polygon{{{1, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 0}}},
+				&Polygon{{{2, 1}, {3, 1}, {3, 2}, {2, 2}, {2, 1}}},
+			},
+			Interior: []Point{{1, 0.5}},
+			Boundary: []Point{{1, 0}, {1, 1}, {2, 0.5}, {2, 1}},
+			Exterior: []Point{{1.5, 1.5}},
+		},
+		{
+			G:        &FeatureCollection{{Geometry: &Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}}}, {Geometry: &Polygon{{{1, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 0}}}}},
+			Interior: []Point{{1, 0.5}},
+			Boundary: []Point{{1, 1}},
+		},
+		{
+			G:        WithSRID(4326, WithBBox([]float64{0, 0, 4, 4}, WithLayout(XYZ, &square))),
+			Interior: []Point{{1, 1}},
+			Boundary: []Point{{0, 1}},
+			Exterior: []Point{{5, 5}},
+		},
+		{
+			G:        &Feature{},
+			Exterior: []Point{{0, 0}},
+		},
+		{
+			G:        badGeom{},
+			Exterior: []Point{{0, 0}},
+		},
+	}.test(t)
+}
+
+func TestLocateCircle(t *testing.T) {
+	defer func(method string) { CircleContainsMethod = method }(CircleContainsMethod)
+
+	for _, method := range []string{
+		ContainsMethodEquirectangular,
+		ContainsMethodHaversine,
+		ContainsMethodSphericalCosines,
+	} {
+		CircleContainsMethod = method
+
+		c := &Circle{Coordinates: Point{0, 4}, Radius: 100000}
+		locateTestcases{
+			{
+				G:        c,
+				Interior: []Point{{0.0001, 4}},
+				Exterior: []Point{{4, 4}},
+			},
+		}.test(t)
+	}
+
+	// The center of a circle with no radius is its boundary.
+	CircleContainsMethod = ContainsMethodEquirectangular
+	if expected, got := Boundary, (&Circle{Coordinates: Point{0, 4}}).Locate(Point{0, 4}); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if expected, got := Exterior, (&Circle{Coordinates: Point{math.NaN(), math.NaN()}}).Locate(Point{0, 4}); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestLocationString(t *testing.T) {
+	for i, testcase := range []struct {
+		Location Location
+		Expected string
+	}{
+		{Location: Exterior, Expected: "Exterior"},
+		{Location: Boundary, Expected: "Boundary"},
+		{Location: Interior, Expected: "Interior"},
+	} {
+		if expected, got := testcase.Expected, testcase.Location.String(); expected != got {
+			t.Fatalf("(case %d) expected %s, got %s", i, expected, got)
+		}
+	}
+}
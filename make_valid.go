@@ -0,0 +1,276 @@
+package geo
+
+import (
+	"math"
+	"sort"
+)
+
+// MakeValid repairs an invalid Polygon or MultiPolygon.
+//
+// Rings are closed, points with coordinates that are not finite are dropped,
+// and repeated and collinear vertices are removed, along with any ring that
+// collapses to fewer than three vertices.
+// Rings that cross or touch themselves, like a bow tie, are split into
+// simple rings at the points where they meet, and the area of the ring is
+// the area inside an odd number of those, so a ring that loops around
+// a hole keeps it. Each polygon is the area of its exterior ring less
+// the areas of its holes, so a hole that crosses its exterior ring is
+// clipped to it, and the polygons of a MultiPolygon that overlap are merged,
+// see Union. Holes that are outside their exterior ring are moved to the
+// other polygons of a MultiPolygon that they are in, or dropped.
+// The exterior rings are wound counterclockwise and the holes clockwise,
+// as RFC 7946 recommends.
+//
+// A Polygon that is split becomes a MultiPolygon. In the rare cases where
+// the overlay cannot find a valid result, see Union, the polygons that
+// spoil it are left out, so the result is always valid.
+// The members of collections and the geometries of features are repaired,
+// and other geometries are returned as they are.
+func MakeValid(g Geometry) Geometry {
+	switch v := g.(type) {
+	case *Polygon:
+		polys := makeValidPolygons([][][][4]float64{*v})
+		if len(polys) > 1 {
+			mp := MultiPolygon(polys)
+			return &mp
+		}
+		p := Polygon{}
+		if len(polys) == 1 {
+			p = Polygon(polys[0])
+		}
+		return &p
+	case *MultiPolygon:
+		mp := MultiPolygon(makeValidPolygons(*v))
+		return &mp
+	case *Feature:
+		if v.Geometry == nil {
+			return v
+		}
+		feat := *v
+		feat.Geometry = MakeValid(v.Geometry)
+		return &feat
+	case *GeometryCollection:
+		gc := make(GeometryCollection, len(*v))
+		for i, member := range *v {
+			gc[i] = MakeValid(member)
+		}
+		return &gc
+	case *FeatureCollection:
+		fc := make(FeatureCollection, len(*v))
+		for i, feat := range *v {
+			fc[i] = MakeValid(feat).(*Feature)
+		}
+		return &fc
+	case *boundingBox:
+		return &boundingBox{Geometry: MakeValid(v.Geometry), Box: v.Box, computed: v.computed}
+	case *sridGeometry:
+		return &sridGeometry{Geometry: MakeValid(v.Geometry), SRID: v.SRID}
+	case *layoutGeometry:
+		return &layoutGeometry{Geometry: MakeValid(v.Geometry), Layout: v.Layout}
+	}
+	return g
+}
+
+// makeValidPolygons repairs the rings of the polygons and arranges them into valid polygons.
+// Each polygon is the area of its exterior ring less the areas of its holes,
+// and the polygons are unioned, so the result is built by the overlay and is valid.
+// The errors of the overlay are ignored: the results that come with them
+// are still valid, which is what matters here.
+// The parts of holes that are outside their exterior ring are then cut from
+// the other polygons, so a hole that was put in the wrong polygon is moved.
+func makeValidPolygons(polys [][][][4]float64) [][][][4]float64 {
+	var parts, strays []Geometry
+	for _, poly := range polys {
+		if len(poly) == 0 {
+			continue
+		}
+		shell := ringArea(cleanRing(poly[0]))
+		holes := make([]Geometry, 0, len(poly)-1)
+		for _, ring := range poly[1:] {
+			hole := ringArea(cleanRing(ring))
+			holes = append(holes, &hole)
+		}
+		holeArea, _ := unionAll(holes)
+		part, _ := Difference(&shell, &holeArea)
+		parts = append(parts, &part)
+		if len(polys) > 1 && len(holeArea) > 0 {
+			stray, _ := Difference(&holeArea, &shell)
+			strays = append(strays, &stray)
+		}
+	}
+	result, _ := unionAll(parts)
+	if len(strays) == 0 {
+		return result
+	}
+	strayArea, _ := unionAll(strays)
+	result, _ = Difference(&result, &strayArea)
+	return result
+}
+
+// ringArea returns the area enclosed by a clean ring that may cross or touch itself.
+// The ring is split into simple loops at the points where it meets itself,
+// and a point is inside the ring if it is inside an odd number of loops,
+// so a loop that is nested in another is a hole.
+//
+// Crossings are rounded, and when three edges nearly meet at a point they may
+// be rounded to different points, leaving slivers that cross other loops.
+// So the ring is first snap rounded to the finest grid of the overlay,
+// after which it only meets itself at its vertices.
+func ringArea(ring [][4]float64) MultiPolygon {
+	area := MultiPolygon{}
+	if ring == nil {
+		return area
+	}
+	rg := relateGeometry{polygons: [][][][4]float64{{ring}}}
+	rg = snapRound([]relateGeometry{rg}, gridSize(rg.magnitude()*overlayGrids[0]))[0]
+	if ring = cleanRing(rg.polygons[0][0]); ring == nil {
+		return area
+	}
+	for _, loop := range splitLoops(nodeRing(ring)) {
+		if loop = cleanRing(loop); loop != nil {
+			area, _ = SymDifference(&area, &MultiPolygon{{loop}})
+		}
+	}
+	return area
+}
+
+// nestHoles makes a polygon of each shell, with the holes that are inside it
+// and not inside any smaller shell, and drops the holes that are outside every shell.
+// The exterior rings are wound counterclockwise and the holes clockwise.
+func nestHoles(shells, holes [][][4]float64) [][][][4]float64 {
+	polys := make([][][][4]float64, len(shells))
+	for i, shell := range shells {
+		polys[i] = [][][4]float64{shell}
+	}
+	for _, hole := range holes {
+		smallest := -1
+		for i, shell := range shells {
+			if !ringInside(hole, shell) {
+				continue
+			}
+			if smallest < 0 || math.Abs(signedArea(shell)) < math.Abs(signedArea(shells[smallest])) {
+				smallest = i
+			}
+		}
+		if smallest >= 0 {
+			polys[smallest] = append(polys[smallest], hole)
+		}
+	}
+	for _, poly := range polys {
+		rewindPolygon(poly)
+	}
+	return polys
+}
+
+// cleanRing returns a copy of a ring without coordinates that are not finite
+// and without repeated or collinear vertices, closing it if it is not closed.
+// The tips of spikes are collinear too, so they are removed along with them,
+// which leaves the points that are inside the ring an odd number of times.
+// It returns nil if fewer than three vertices are left.
+func cleanRing(ring [][4]float64) [][4]float64 {
+	var open [][4]float64
+	for _, p := range ring {
+		if math.IsNaN(p[0]) || math.IsNaN(p[1]) || math.IsInf(p[0], 0) || math.IsInf(p[1], 0) {
+			continue
+		}
+		open = appendClean(open, p)
+	}
+	// The ring wraps around, so the vertices at either end are cleaned too.
+	for len(open) >= 3 {
+		n := len(open)
+		switch {
+		case open[n-1][0] == open[0][0] && open[n-1][1] == open[0][1]:
+			open = open[:n-1]
+		case orient2d(open[n-2], open[n-1], open[0]) == 0:
+			open = open[:n-1]
+		case orient2d(open[n-1], open[0], open[1]) == 0:
+			open = open[1:]
+		default:
+			return append(open, open[0])
+		}
+	}
+	return nil
+}
+
+// appendClean appends a vertex to a path, first removing the vertices at its end
+// that the vertex repeats or makes collinear.
+func appendClean(path [][4]float64, p [4]float64) [][4]float64 {
+	for {
+		n := len(path)
+		if n > 0 && path[n-1][0] == p[0] && path[n-1][1] == p[1] {
+			return path
+		}
+		if n < 2 || orient2d(path[n-2], path[n-1], p) != 0 {
+			return append(path, p)
+		}
+		path = path[:n-1]
+	}
+}
+
+// nodeRing returns a ring with a vertex added wherever it crosses or touches itself.
+func nodeRing(ring [][4]float64) [][4]float64 {
+	var (
+		segs = ringSegments(ring, 0)
+		cuts = make([][][4]float64, len(segs))
+	)
+	sweepSegments(segs, func(s, t segment) bool {
+		for _, p := range segmentIntersection(s.a, s.b, t.a, t.b) {
+			if !isEndpoint(s, p) {
+				cuts[s.seq] = append(cuts[s.seq], p)
+			}
+			if !isEndpoint(t, p) {
+				cuts[t.seq] = append(cuts[t.seq], p)
+			}
+		}
+		return true
+	})
+	noded := make([][4]float64, 0, len(ring))
+	for i, s := range segs {
+		noded = append(noded, s.a)
+		points := cuts[i]
+		sort.Slice(points, func(j, k int) bool {
+			return squaredDistance(s.a, points[j]) < squaredDistance(s.a, points[k])
+		})
+		for _, p := range points {
+			noded = appendDistinct(noded, p)
+		}
+	}
+	return append(noded, ring[len(ring)-1])
+}
+
+// splitLoops splits a closed ring into loops that do not visit any vertex twice.
+func splitLoops(ring [][4]float64) [][][4]float64 {
+	var (
+		loops [][][4]float64
+		stack [][4]float64
+		seen  = map[[2]float64]int{}
+	)
+	for _, p := range ring[:len(ring)-1] {
+		key := [2]float64{p[0], p[1]}
+		k, ok := seen[key]
+		if !ok {
+			seen[key] = len(stack)
+			stack = append(stack, p)
+			continue
+		}
+		loop := append(append([][4]float64{}, stack[k:]...), p)
+		loops = append(loops, loop)
+		for _, q := range stack[k+1:] {
+			delete(seen, [2]float64{q[0], q[1]})
+		}
+		stack = stack[:k+1]
+	}
+	return append(loops, append(stack, stack[0]))
+}
+
+// ringInside returns true if a ring is inside another ring that it does not cross.
+func ringInside(ring, other [][4]float64) bool {
+	inside, outside := ringLocations(ring, other)
+	return inside && !outside
+}
+
+// squaredDistance returns the square of the distance between two points in the XY plane.
+func squaredDistance(a, b [4]float64) float64 {
+	dx, dy := a[0]-b[0], a[1]-b[1]
+	return dx*dx + dy*dy
+}
@@ -0,0 +1,267 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestMakeValid(t *testing.T) {
+	square := [][4]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}
+
+	for i, testcase := range []struct {
+		G        Geometry
+		Expected Geometry
+	}{
+		{
+			G:        &Polygon{square},
+			Expected: &Polygon{square},
+		},
+		{
+			G:        &Polygon{},
+			Expected: &Polygon{},
+		},
+		{
+			// Unclosed and clockwise.
+			G:        &Polygon{{{0, 0}, {0, 10}, {10, 10}, {10, 0}}},
+			Expected: &Polygon{square},
+		},
+		{
+			// Repeated and collinear vertices, and a spike.
+			G:        &Polygon{{{0, 0}, {5, 0}, {10, 0}, {10, 0}, {10, 10}, {10, 20}, {10, 10}, {0, 10}, {0, 0}}},
+			Expected: &Polygon{square},
+		},
+		{
+			// Coordinates that are not finite.
+			G:        &Polygon{{{0, 0}, {10, 0}, {math.NaN(), 5}, {10, 10}, {0, 10}, {0, math.Inf(1)}, {0, 0}}},
+			Expected: &Polygon{square},
+		},
+		{
+			// Collapsed.
+			G:        &Polygon{{{0, 0}, {1, 1}, {2, 2}, {0, 0}}},
+			Expected: &Polygon{},
+		},
+		{
+			// Bow tie.
+			G: &Polygon{{{0, 0}, {10, 10}, {10, 0}, {0, 10}, {0, 0}}},
+			Expected: &MultiPolygon{
+				{{{5, 5}, {10, 0}, {10, 10}, {5, 5}}},
+				{{{0, 0}, {5, 5}, {0, 10}, {0, 0}}},
+			},
+		},
+		{
+			// Ring that touches itself.
+			G: &Polygon{{{0, 0}, {10, 0}, {10, 10}, {5, 0}, {0, 10}, {0, 0}}},
+			Expected: &MultiPolygon{
+				{{{5, 0}, {10, 0}, {10, 10}, {5, 0}}},
+				{{{0, 0}, {5, 0}, {0, 10}, {0, 0}}},
+			},
+		},
+		{
+			// Ring that touches itself around a hole.
+			G: &Polygon{{{0, 0}, {5, 0}, {7, 5}, {3, 5}, {5, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}},
+			Expected: &Polygon{
+				{{10, 0}, {10, 10}, {0, 10}, {0, 0}, {10, 0}},
+				{{5, 0}, {3, 5}, {7, 5}, {5, 0}},
+			},
+		},
+		{
+			// Counterclockwise hole, and a hole outside the shell.
+			G: &Polygon{
+				square,
+				{{2, 2}, {4, 2}, {4, 4}, {2, 4}, {2, 2}},
+				{{20, 20}, {21, 20}, {21, 21}, {20, 20}},
+			},
+			Expected: &Polygon{
+				square,
+				{{4, 2}, {2, 2}, {2, 4}, {4, 4}, {4, 2}},
+			},
+		},
+		{
+			// Hole in the wrong polygon.
+			G: &MultiPolygon{
+				{{{20, 20}, {30, 20}, {30, 30}, {20, 20}}, {{2, 2}, {2, 4}, {4, 4}, {4, 2}, {2, 2}}},
+				{square},
+			},
+			Expected: &MultiPolygon{
+				{square, {{4, 2}, {2, 2}, {2, 4}, {4, 4}, {4, 2}}},
+				{{{20, 20}, {30, 20}, {30, 30}, {20, 20}}},
+			},
+		},
+		{
+			G: &MultiPolygon{
+				{{{0, 0}, {10, 10}, {10, 0}, {0, 10}, {0, 0}}},
+				{{{20, 20}, {30, 20}, {30, 30}}},
+			},
+			Expected: &MultiPolygon{
+				{{{5, 5}, {10, 0}, {10, 10}, {5, 5}}},
+				{{{0, 0}, {5, 5}, {0, 10}, {0, 0}}},
+				{{{20, 20}, {30, 20}, {30, 30}, {20, 20}}},
+			},
+		},
+		{
+			G:        &Point{1, 2},
+			Expected: &Point{1, 2},
+		},
+		{
+			G:        &GeometryCollection{&Point{1, 2}, &Polygon{{{0, 0}, {0, 10}, {10, 10}, {10, 0}}}},
+			Expected: &GeometryCollection{&Point{1, 2}, &Polygon{square}},
+		},
+		{
+			G:        &FeatureCollection{{Geometry: &Polygon{{{0, 0}, {0, 10}, {10, 10}, {10, 0}}}}, {}},
+			Expected: &FeatureCollection{{Geometry: &Polygon{square}}, {}},
+		},
+		{
+			G:        WithSRID(4326, &Polygon{{{0, 0}, {0, 10}, {10, 10}, {10, 0}}}),
+			Expected: WithSRID(4326, &Polygon{square}),
+		},
+	} {
+		got := MakeValid(testcase.G)
+		if expected := testcase.Expected; !expected.Equal(got) {
+			t.Fatalf("(case %d) expected %s, got %s", i, expected, got)
+		}
+		if errs := Validate(got); len(errs) > 0 {
+			t.Fatalf("(case %d) expected %s to be valid, got %s", i, got, errs)
+		}
+	}
+}
+
+func TestMakeValidDoesNotModify(t *testing.T) {
+	var (
+		g        = &Polygon{{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}}}
+		expected = &Polygon{{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}}}
+	)
+	MakeValid(g)
+	if !expected.Equal(g) {
+		t.Fatalf("expected %s, got %s", expected, g)
+	}
+}
+
+func TestMakeValidArea(t *testing.T) {
+	for i, testcase := range []struct {
+		G    Polygon
+		Area float64
+	}{
+		{
+			G:    Polygon{{{1, 3}, {5, 1}, {4, 0}, {3, 2}, {4, 1}, {3, 2}, {4, 1}}},
+			Area: evenOddArea([][4]float64{{1, 3}, {5, 1}, {4, 0}, {3, 2}, {4, 1}, {3, 2}, {4, 1}}),
+		},
+		{
+			G:    Polygon{{{2, 0}, {5, 1}, {0, 5}, {0, 2}, {2, 4}, {3, 1}, {5, 4}}},
+			Area: evenOddArea([][4]float64{{2, 0}, {5, 1}, {0, 5}, {0, 2}, {2, 4}, {3, 1}, {5, 4}}),
+		},
+		{
+			// Edge that is traced three times.
+			G:    Polygon{{{0, 4}, {2, 5}, {4, 6}, {0, 0}, {4, 1}, {9, 7}, {0, 4}, {4, 6}}},
+			Area: evenOddArea([][4]float64{{0, 4}, {2, 5}, {4, 6}, {0, 0}, {4, 1}, {9, 7}, {0, 4}, {4, 6}}),
+		},
+		{
+			// Crossings that are rounded apart.
+			G:    Polygon{{{2, 1}, {0, 7}, {5, 0}, {4, 5}, {2, 1}, {8, 9}, {4, 2}, {5, 5}}},
+			Area: evenOddArea([][4]float64{{2, 1}, {0, 7}, {5, 0}, {4, 5}, {2, 1}, {8, 9}, {4, 2}, {5, 5}}),
+		},
+		{
+			// Hole that crosses the exterior ring.
+			G: Polygon{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+				{{5, -1}, {6, -1}, {6, 11}, {5, 11}, {5, -1}},
+			},
+			Area: 90,
+		},
+		{
+			// Polygons that overlap.
+			G: Polygon{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+				{{2, 2}, {4, 2}, {4, 4}, {2, 4}, {2, 2}},
+				{{3, 3}, {5, 3}, {5, 5}, {3, 5}, {3, 3}},
+			},
+			Area: 93,
+		},
+	} {
+		got := MakeValid(&testcase.G)
+		if errs := Validate(got); len(errs) > 0 {
+			t.Fatalf("(case %d) expected %s to be valid, got %s", i, got, errs)
+		}
+		if expected, got := testcase.Area, Area(got); math.Abs(expected-got) > 1e-9 {
+			t.Fatalf("(case %d) expected area %f, got %f", i, expected, got)
+		}
+	}
+}
+
+func TestMakeValidRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randomRing := func() [][4]float64 {
+		ring := make([][4]float64, 3+r.Intn(6))
+		for i := range ring {
+			ring[i] = [4]float64{float64(r.Intn(11)), float64(r.Intn(11))}
+		}
+		return ring
+	}
+	for i := 0; i < 1000; i++ {
+		ring := randomRing()
+		g := MakeValid(&Polygon{ring})
+		if errs := Validate(g); len(errs) > 0 {
+			t.Fatalf("(case %d) expected %s to be valid for %v, got %s", i, g, ring, errs)
+		}
+		if expected, got := evenOddArea(ring), Area(g); math.Abs(expected-got) > 1e-9 {
+			t.Fatalf("(case %d) expected area %f for %v, got %f (%s)", i, expected, ring, got, g)
+		}
+
+		if i%4 != 0 {
+			continue
+		}
+		mp := MultiPolygon{{ring, randomRing()}, {randomRing(), randomRing()}}
+		if g := MakeValid(&mp); len(Validate(g)) > 0 {
+			t.Fatalf("(case %d) expected %s to be valid for %v, got %s", i, g, mp, Validate(g))
+		}
+	}
+}
+
+// evenOddArea returns the area of the points that are inside a ring
+// an odd number of times. The plane is cut into vertical slabs at the
+// vertices and crossings, where no edges cross, and the gaps between
+// alternate edges are added up at the middle of each slab.
+func evenOddArea(ring [][4]float64) float64 {
+	if ring[0] != ring[len(ring)-1] {
+		ring = append(ring, ring[0])
+	}
+	var xs []float64
+	for i := 0; i < len(ring)-1; i++ {
+		xs = append(xs, ring[i][0])
+		for j := i + 1; j < len(ring)-1; j++ {
+			a, b, c, d := ring[i], ring[i+1], ring[j], ring[j+1]
+			den := (b[0]-a[0])*(d[1]-c[1]) - (b[1]-a[1])*(d[0]-c[0])
+			if den == 0 {
+				continue
+			}
+			s := ((c[0]-a[0])*(d[1]-c[1]) - (c[1]-a[1])*(d[0]-c[0])) / den
+			u := ((c[0]-a[0])*(b[1]-a[1]) - (c[1]-a[1])*(b[0]-a[0])) / den
+			if s >= 0 && s <= 1 && u >= 0 && u <= 1 {
+				xs = append(xs, a[0]+s*(b[0]-a[0]))
+			}
+		}
+	}
+	sort.Float64s(xs)
+
+	area := 0.0
+	for k := 1; k < len(xs); k++ {
+		x0, x1 := xs[k-1], xs[k]
+		if x1 <= x0 {
+			continue
+		}
+		xm := (x0 + x1) / 2
+		var ys []float64
+		for i := 0; i < len(ring)-1; i++ {
+			a, b := ring[i], ring[i+1]
+			if (a[0] < xm) != (b[0] < xm) {
+				ys = append(ys, a[1]+(xm-a[0])*(b[1]-a[1])/(b[0]-a[0]))
+			}
+		}
+		sort.Float64s(ys)
+		for j := 1; j < len(ys); j += 2 {
+			area += (ys[j] - ys[j-1]) * (x1 - x0)
+		}
+	}
+	return area
+}
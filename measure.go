@@ -0,0 +1,302 @@
+package geo
+
+import "math"
+
+// The measurements in this file are Cartesian: they treat the X and Y
+// of every coordinate as a position in the plane and ignore Z and M,
+// so they are in the units of the coordinates (or their squares).
+//
+// A Circle is measured as the ellipse that fills its bounding box,
+// since its radius is in feet rather than in the units of its coordinates.
+
+// Area returns the area of the polygons in a geometry.
+// The area of a polygon is the area of its exterior ring minus the areas of its holes,
+// whichever way the rings are wound.
+// The area of a collection is the sum of the areas of its members,
+// and points and lines have no area.
+func Area(g Geometry) float64 {
+	area := 0.0
+	visitMeasurable(g, func(g Geometry) {
+		switch v := g.(type) {
+		case *Polygon:
+			area += polygonArea(*v)
+		case *MultiPolygon:
+			for _, poly := range *v {
+				area += polygonArea(poly)
+			}
+		case *Circle:
+			a, b := circleAxes(*v)
+			area += math.Pi * a * b
+		}
+	})
+	return area
+}
+
+// Length returns the length of the lines in a geometry.
+// The length of a collection is the sum of the lengths of its members,
+// and points and polygons have no length, see Perimeter.
+func Length(g Geometry) float64 {
+	length := 0.0
+	visitMeasurable(g, func(g Geometry) {
+		switch v := g.(type) {
+		case *Line:
+			length += lineLength(*v)
+		case *MultiLine:
+			for _, line := range *v {
+				length += lineLength(line)
+			}
+		}
+	})
+	return length
+}
+
+// Perimeter returns the length of the boundaries of the polygons in a geometry,
+// including the boundaries of their holes.
+// The perimeter of a collection is the sum of the perimeters of its members,
+// and points and lines have no perimeter.
+func Perimeter(g Geometry) float64 {
+	perimeter := 0.0
+	visitMeasurable(g, func(g Geometry) {
+		switch v := g.(type) {
+		case *Polygon:
+			for _, ring := range *v {
+				perimeter += ringLength(ring)
+			}
+		case *MultiPolygon:
+			for _, poly := range *v {
+				for _, ring := range poly {
+					perimeter += ringLength(ring)
+				}
+			}
+		case *Circle:
+			// Ramanujan's approximation of the perimeter of an ellipse.
+			a, b := circleAxes(*v)
+			perimeter += math.Pi * (3*(a+b) - math.Sqrt((3*a+b)*(a+3*b)))
+		}
+	})
+	return perimeter
+}
+
+// Centroid returns the center of mass of a geometry.
+//
+// Only the parts of the geometry with the highest dimension count:
+// the centroid of a geometry with any area is weighted by area,
+// otherwise the centroid of a geometry with any length is weighted by length,
+// otherwise it is the average of its points.
+// The centroid of a Circle is its center.
+// The centroid of an empty geometry is an empty point.
+func Centroid(g Geometry) Point {
+	var c centroid
+	visitMeasurable(g, c.add)
+	return c.point()
+}
+
+// visitMeasurable calls f for every geometry in g that is not a collection,
+// a feature or a decorator.
+func visitMeasurable(g Geometry, f func(g Geometry)) {
+	switch v := g.(type) {
+	case *Feature:
+		if v.Geometry != nil {
+			visitMeasurable(v.Geometry, f)
+		}
+	case *FeatureCollection:
+		for _, feat := range *v {
+			visitMeasurable(feat, f)
+		}
+	case *GeometryCollection:
+		for _, member := range *v {
+			visitMeasurable(member, f)
+		}
+	case *boundingBox:
+		visitMeasurable(v.Geometry, f)
+	case *sridGeometry:
+		visitMeasurable(v.Geometry, f)
+	case *layoutGeometry:
+		visitMeasurable(v.Geometry, f)
+	default:
+		f(g)
+	}
+}
+
+// centroid accumulates the weighted sums needed to find a centroid,
+// separately for each dimension.
+type centroid struct {
+	area, areaX, areaY       float64
+	length, lengthX, lengthY float64
+	points, pointX, pointY   float64
+}
+
+// add adds a geometry that is not a collection.
+func (c *centroid) add(g Geometry) {
+	switch v := g.(type) {
+	case *Point:
+		c.addPoint(*v)
+	case *MultiPoint:
+		for _, p := range *v {
+			c.addPoint(p)
+		}
+	case *Line:
+		c.addLine(*v, false)
+	case *MultiLine:
+		for _, line := range *v {
+			c.addLine(line, false)
+		}
+	case *Polygon:
+		c.addPolygon(*v)
+	case *MultiPolygon:
+		for _, poly := range *v {
+			c.addPolygon(poly)
+		}
+	case *Circle:
+		if v.IsEmpty() {
+			return
+		}
+		a, b := circleAxes(*v)
+		area := math.Pi * a * b
+		c.area += area
+		c.areaX += area * v.Coordinates[0]
+		c.areaY += area * v.Coordinates[1]
+		c.addPoint(v.Coordinates)
+	}
+}
+
+// addPoint adds a point, unless it is empty.
+func (c *centroid) addPoint(p Point) {
+	if p.IsEmpty() {
+		return
+	}
+	c.points++
+	c.pointX += p[0]
+	c.pointY += p[1]
+}
+
+// addLine adds the segments of a line, closing it first if it is a ring,
+// and its points in case it has no length.
+func (c *centroid) addLine(line [][4]float64, ring bool) {
+	for i := 1; i < len(line); i++ {
+		c.addSegment(line[i-1], line[i])
+	}
+	if ring && len(line) > 0 {
+		c.addSegment(line[len(line)-1], line[0])
+	}
+	for _, p := range line {
+		c.addPoint(p)
+	}
+}
+
+// addSegment adds a segment weighted by its length.
+func (c *centroid) addSegment(a, b [4]float64) {
+	length := math.Hypot(b[0]-a[0], b[1]-a[1])
+	c.length += length
+	c.lengthX += length * (a[0] + b[0]) / 2
+	c.lengthY += length * (a[1] + b[1]) / 2
+}
+
+// addPolygon adds the area of a polygon, and its rings in case it has no area.
+func (c *centroid) addPolygon(poly [][][4]float64) {
+	for i, ring := range poly {
+		area, x, y := ringCentroid(ring)
+		area = math.Abs(area)
+		if i > 0 {
+			area = -area
+		}
+		c.area += area
+		c.areaX += area * x
+		c.areaY += area * y
+		c.addLine(ring, true)
+	}
+}
+
+// point returns the centroid of what has been added.
+func (c *centroid) point() Point {
+	switch {
+	case c.area != 0:
+		return Point{c.areaX / c.area, c.areaY / c.area}
+	case c.length != 0:
+		return Point{c.lengthX / c.length, c.lengthY / c.length}
+	case c.points != 0:
+		return Point{c.pointX / c.points, c.pointY / c.points}
+	}
+	return emptyPoint()
+}
+
+// ringCentroid returns the signed area and the centroid of a ring,
+// which is closed if its last point is not its first.
+// The area is positive if the ring is counterclockwise.
+// Coordinates are taken relative to the first point to reduce rounding errors.
+func ringCentroid(ring [][4]float64) (area, x, y float64) {
+	if len(ring) < 3 {
+		return 0, 0, 0
+	}
+	var (
+		origin = ring[0]
+		sum    float64
+		sumX   float64
+		sumY   float64
+	)
+	for i := range ring {
+		var (
+			a     = ring[i]
+			b     = ring[(i+1)%len(ring)]
+			ax    = a[0] - origin[0]
+			ay    = a[1] - origin[1]
+			bx    = b[0] - origin[0]
+			by    = b[1] - origin[1]
+			cross = ax*by - bx*ay
+		)
+		sum += cross
+		sumX += (ax + bx) * cross
+		sumY += (ay + by) * cross
+	}
+	if sum == 0 {
+		return 0, 0, 0
+	}
+	return sum / 2, origin[0] + sumX/(3*sum), origin[1] + sumY/(3*sum)
+}
+
+// polygonArea returns the area of the exterior ring of a polygon minus the areas of its holes.
+func polygonArea(poly [][][4]float64) float64 {
+	area := 0.0
+	for i, ring := range poly {
+		a, _, _ := ringCentroid(ring)
+		if i == 0 {
+			area += math.Abs(a)
+		} else {
+			area -= math.Abs(a)
+		}
+	}
+	return area
+}
+
+// lineLength returns the length of a line.
+func lineLength(line [][4]float64) float64 {
+	length := 0.0
+	for i := 1; i < len(line); i++ {
+		length += math.Hypot(line[i][0]-line[i-1][0], line[i][1]-line[i-1][1])
+	}
+	return length
+}
+
+// ringLength returns the length of a ring, which is closed if its last point is not its first.
+func ringLength(ring [][4]float64) float64 {
+	if len(ring) == 0 {
+		return 0
+	}
+	last, first := ring[len(ring)-1], ring[0]
+	return lineLength(ring) + math.Hypot(first[0]-last[0], first[1]-last[1])
+}
+
+// circleAxes returns the semi-axes of the ellipse that fills the bounding box of a circle.
+// The bounding box of a circle that crosses the antimeridian wraps around,
+// so its width is taken the long way round from west to east.
+func circleAxes(c Circle) (a, b float64) {
+	env := c.Bounds()
+	if env.IsEmpty() {
+		return 0, 0
+	}
+	width := env.Max[0] - env.Min[0]
+	if env.CrossesAntimeridian() {
+		width += 360
+	}
+	return width / 2, (env.Max[1] - env.Min[1]) / 2
+}
@@ -0,0 +1,155 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// closeTo returns true if two floats are within 1e-9 of each other.
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9
+}
+
+func TestMeasure(t *testing.T) {
+	var (
+		square = [][4]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}
+		hole   = [][4]float64{{2, 2}, {2, 4}, {4, 4}, {4, 2}, {2, 2}}
+	)
+	for i, testcase := range []struct {
+		G         Geometry
+		Area      float64
+		Length    float64
+		Perimeter float64
+		Centroid  Point
+	}{
+		{
+			G:        &Point{1, 2},
+			Centroid: Point{1, 2},
+		},
+		{
+			G:        &MultiPoint{{0, 0}, {2, 0}, {4, 6}},
+			Centroid: Point{2, 2},
+		},
+		{
+			G:        &Line{{0, 0}, {3, 4}, {3, 10}},
+			Length:   11,
+			Centroid: Point{(5*1.5 + 6*3) / 11.0, (5*2 + 6*7) / 11.0},
+		},
+		{
+			G:        &MultiLine{{{0, 0}, {2, 0}}, {{0, 10}, {0, 12}}},
+			Length:   4,
+			Centroid: Point{0.5, 5.5},
+		},
+		{
+			G:         &Polygon{square},
+			Area:      100,
+			Perimeter: 40,
+			Centroid:  Point{5, 5},
+		},
+		{
+			// Clockwise and not closed.
+			G:         &Polygon{{{0, 0}, {0, 10}, {10, 10}, {10, 0}}},
+			Area:      100,
+			Perimeter: 40,
+			Centroid:  Point{5, 5},
+		},
+		{
+			G:         &Polygon{square, hole},
+			Area:      96,
+			Perimeter: 48,
+			Centroid:  Point{(100*5 - 4*3) / 96.0, (100*5 - 4*3) / 96.0},
+		},
+		{
+			G:         &MultiPolygon{{square}, {{{20, 0}, {30, 0}, {30, 10}, {20, 10}, {20, 0}}}},
+			Area:      200,
+			Perimeter: 80,
+			Centroid:  Point{15, 5},
+		},
+		{
+			// Far from the origin.
+			G:         &Polygon{{{1e9, 1e9}, {1e9 + 1, 1e9}, {1e9 + 1, 1e9 + 1}, {1e9, 1e9 + 1}, {1e9, 1e9}}},
+			Area:      1,
+			Perimeter: 4,
+			Centroid:  Point{1e9 + 0.5, 1e9 + 0.5},
+		},
+		{
+			// Collapsed to a line.
+			G:         &Polygon{{{0, 0}, {4, 0}, {0, 0}}},
+			Perimeter: 8,
+			Centroid:  Point{2, 0},
+		},
+		{
+			// Only the polygon counts.
+			G:         &GeometryCollection{&Point{100, 100}, &Line{{50, 50}, {60, 60}}, &Polygon{square}},
+			Area:      100,
+			Length:    math.Sqrt(200),
+			Perimeter: 40,
+			Centroid:  Point{5, 5},
+		},
+		{
+			G:        &FeatureCollection{{Geometry: &Point{0, 0}}, {Geometry: WithSRID(4326, &Point{2, 4})}, {}},
+			Centroid: Point{1, 2},
+		},
+		{
+			G:        &GeometryCollection{},
+			Centroid: Point{math.NaN(), math.NaN()},
+		},
+		{
+			G:        &Line{},
+			Centroid: Point{math.NaN(), math.NaN()},
+		},
+	} {
+		if expected, got := testcase.Area, Area(testcase.G); !closeTo(expected, got) {
+			t.Fatalf("(case %d) expected area %g, got %g", i, expected, got)
+		}
+		if expected, got := testcase.Length, Length(testcase.G); !closeTo(expected, got) {
+			t.Fatalf("(case %d) expected length %g, got %g", i, expected, got)
+		}
+		if expected, got := testcase.Perimeter, Perimeter(testcase.G); !closeTo(expected, got) {
+			t.Fatalf("(case %d) expected perimeter %g, got %g", i, expected, got)
+		}
+		expected, got := testcase.Centroid, Centroid(testcase.G)
+		if expected.IsEmpty() != got.IsEmpty() || !expected.IsEmpty() && (!closeTo(expected[0], got[0]) || !closeTo(expected[1], got[1])) {
+			t.Fatalf("(case %d) expected centroid %s, got %s", i, expected, got)
+		}
+	}
+}
+
+func TestMeasureCircle(t *testing.T) {
+	c := &Circle{Coordinates: Point{-73.8, 40.6}, Radius: 5280}
+	env := c.Bounds()
+	var (
+		a = (env.Max[0] - env.Min[0]) / 2
+		b = (env.Max[1] - env.Min[1]) / 2
+	)
+	if expected, got := math.Pi*a*b, Area(c); !closeTo(expected, got) {
+		t.Fatalf("expected %g, got %g", expected, got)
+	}
+	if a <= b {
+		t.Fatalf("expected the circle to be wider than it is tall in degrees, got %g and %g", a, b)
+	}
+	if perimeter := Perimeter(c); perimeter < 2*math.Pi*b || perimeter > 2*math.Pi*a {
+		t.Fatalf("expected perimeter between %g and %g, got %g", 2*math.Pi*b, 2*math.Pi*a, perimeter)
+	}
+	if expected, got := c.Coordinates, Centroid(c); !expected.Equal(&got) {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if expected, got := c.Coordinates, Centroid(&GeometryCollection{c, &Point{0, 0}}); !expected.Equal(&got) {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+
+	// A circle that crosses the antimeridian measures the same as one that does not.
+	var (
+		across = &Circle{Coordinates: Point{179.999, 0}, Radius: 5000}
+		inside = &Circle{Coordinates: Point{0, 0}, Radius: 5000}
+	)
+	if expected, got := Area(inside), Area(across); expected <= 0 || !closeTo(expected, got) {
+		t.Fatalf("expected %g, got %g", expected, got)
+	}
+	if expected, got := Perimeter(inside), Perimeter(across); !closeTo(expected, got) {
+		t.Fatalf("expected %g, got %g", expected, got)
+	}
+	if expected, got := (Point{90, 0}), Centroid(&GeometryCollection{across, &Circle{Coordinates: Point{0.001, 0}, Radius: 5000}}); !closeTo(expected[0], got[0]) {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
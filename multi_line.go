@@ -2,14 +2,9 @@ package geo
 
 import (
 	"database/sql/driver"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
-	"strings"
-)
-
-const (
-	mlWKTPrefix = `MULTILINESTRING(`
-	mlWKTSuffix = `)`
 )
 
 var (
@@ -18,7 +13,7 @@ var (
 )
 
 // MultiLine is an array of Line's.
-type MultiLine [][][3]float64
+type MultiLine [][][4]float64
 
 // Equal compares one MultiLine to another.
 func (ml MultiLine) Equal(g Geometry) bool {
@@ -41,22 +36,54 @@ func (ml MultiLine) Equal(g Geometry) bool {
 	return true
 }
 
-// Contains uses the ray casting algorithm to decide
-// if the point is contained in the MultiLine.
+// Contains returns true if the point lies on any line in the MultiLine.
 func (ml MultiLine) Contains(point Point) bool {
-	intersections := 0
-	for _, poly := range ml {
-		for j, vertex := range poly {
-			if point.RayhIntersects(poly[(j+1)%len(poly)], vertex) {
-				intersections++
+	return ml.Locate(point) != Exterior
+}
+
+// Locate returns the location of the point relative to the MultiLine.
+// Following the "mod 2" rule, the boundary of a MultiLine is made up of the
+// endpoints that belong to an odd number of lines that are not closed.
+func (ml MultiLine) Locate(point Point) Location {
+	if point.IsEmpty() {
+		return Exterior
+	}
+	ends := 0
+	for _, line := range ml {
+		for _, end := range lineEndpoints(line) {
+			if end[0] == point[0] && end[1] == point[1] {
+				ends++
 			}
 		}
 	}
-	return (intersections % 2) == 1
+	if ends%2 == 1 {
+		return Boundary
+	}
+	for _, line := range ml {
+		if lineContains(line, point) {
+			return Interior
+		}
+	}
+	return Exterior
+}
+
+// IsEmpty returns true if the MultiLine has no coordinates.
+func (ml MultiLine) IsEmpty() bool {
+	return isEmpty(&ml)
+}
+
+// Bounds returns the envelope of the MultiLine.
+func (ml MultiLine) Bounds() Envelope {
+	return bounds(&ml)
 }
 
 // MarshalJSON returns the GeoJSON representation of the MultiLine.
 func (ml MultiLine) MarshalJSON() ([]byte, error) {
+	return ml.geoJSON(LayoutOf(&ml))
+}
+
+// geoJSON returns the GeoJSON representation of the MultiLine using the given layout.
+func (ml MultiLine) geoJSON(layout Layout) ([]byte, error) {
 	s := mlJSONPrefix
 	for i, poly := range ml {
 		if i == 0 {
@@ -64,12 +91,18 @@ func (ml MultiLine) MarshalJSON() ([]byte, error) {
 		} else {
 			s = append(s, ',', '[')
 		}
-		s = append(s, pointsMarshalJSON(poly, "", "")...)
+		s = append(s, pointsMarshalJSON(poly, "", "", layout)...)
 		s = append(s, ']')
 	}
 	return append(s, mlJSONSuffix...), nil
 }
 
+// MarshalWKB returns the Well Known Binary representation of the MultiLine
+// using the given byte order.
+func (ml MultiLine) MarshalWKB(order binary.ByteOrder) ([]byte, error) {
+	return marshalWKB(&ml, order)
+}
+
 // Scan scans a MultiLine from Well Known Text.
 func (ml *MultiLine) Scan(src interface{}) error {
 	return scan(ml, src)
@@ -77,45 +110,33 @@ func (ml *MultiLine) Scan(src interface{}) error {
 
 // scan scans a MultiLine from a Well Known Text string.
 func (ml *MultiLine) scan(s string) error {
-	if i := strings.Index(s, mlWKTPrefix); i != 0 {
-		return fmt.Errorf("malformed MultiLine %s", s)
-	}
-	l := len(s)
-
-	if s[l-len(mlWKTSuffix):] != mlWKTSuffix {
-		return fmt.Errorf("malformed MultiLine %s", s)
-	}
-	s = s[len(mlWKTPrefix) : l-len(mlWKTSuffix)]
-
-	// empty the MultiLine
-	*ml = MultiLine{}
-
-	// get the coordinates
-	mls := strings.Split(s, "),(")
-	for _, ss := range mls {
-		points, err := pointsScan(ss)
-		if err != nil {
-			return err
-		}
-		*ml = append(*ml, points)
+	g, _, err := parseWKT(s, multiLineWKTTag)
+	if err != nil {
+		return err
 	}
+	*ml = *withoutLayout(g).(*MultiLine)
 	return nil
 }
 
 // String converts the MultiLine to a string.
 func (ml MultiLine) String() string {
+	return ml.wkt(LayoutOf(&ml))
+}
+
+// wkt returns the Well Known Text representation of the MultiLine using the given layout.
+func (ml MultiLine) wkt(layout Layout) string {
 	if len(ml) == 0 {
 		return "MULTILINESTRING EMPTY"
 	}
-	s := mlWKTPrefix
+	s := wktPrefix(multiLineWKTTag, layout) + "("
 	for i, points := range ml {
 		if i == 0 {
-			s += pointsString(points)
+			s += pointsString(points, layout)
 		} else {
-			s += "," + pointsString(points)
+			s += "," + pointsString(points, layout)
 		}
 	}
-	return s + mlWKTSuffix
+	return s + ")"
 }
 
 // UnmarshalJSON unmarshals the ml from GeoJSON.
@@ -129,14 +150,25 @@ func (ml *MultiLine) UnmarshalJSON(data []byte) error {
 		return fmt.Errorf("expected type %s, got %s", expected, got)
 	}
 
-	p := [][][3]float64{}
+	p := [][][4]float64{}
 
 	if err := json.Unmarshal(g.Coordinates, &p); err != nil {
 		return err
 	}
 
 	*ml = MultiLine(p)
+	ml.Transform(layoutMarker(positionLayout(g.Coordinates)))
+
+	return nil
+}
 
+// UnmarshalWKB unmarshals the MultiLine from Well Known Binary.
+func (ml *MultiLine) UnmarshalWKB(data []byte) error {
+	g, _, err := unmarshalWKB(data, wkbMultiLine)
+	if err != nil {
+		return err
+	}
+	*ml = *withoutLayout(g).(*MultiLine)
 	return nil
 }
 
@@ -147,9 +179,9 @@ func (ml MultiLine) Value() (driver.Value, error) {
 
 // Transform transforms the geometry point by point.
 func (ml *MultiLine) Transform(t Transformer) {
-	np := make([][][3]float64, len(*ml))
+	np := make([][][4]float64, len(*ml))
 	for i, line := range *ml {
-		nl := make([][3]float64, len(line))
+		nl := make([][4]float64, len(line))
 		for j, point := range line {
 			nl[j] = t.Transform(point)
 		}
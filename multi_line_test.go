@@ -77,67 +77,46 @@ func TestMultiLineContains(t *testing.T) {
 			},
 		},
 		Inside: []Point{
-			{1, 1},
+			{0, 0},
+			{1, 0},
+			{2, 1.5},
 		},
 		Outside: []Point{
+			{1, 1},
 			{4, 1},
 		},
 	}.test(t)
 
-	// Contains (hexagon)
+	// Contains (two lines)
 	cases{
 		G: &MultiLine{
 			{
-				{0, 1},
-				{1, 2},
-				{2, 1},
-				{2, 0},
-				{1, -1},
 				{0, 0},
-				{0, 1},
+				{1, 1},
 			},
-		},
-		Inside: []Point{
-			{1, 0},
-		},
-	}.test(t)
-
-	// Contains (quadrilateral)
-	cases{
-		G: &MultiLine{
 			{
-				{-1, 10},
-				{10, 1},
-				{1, -10},
-				{-10, -1},
-				{-1, 10},
+				{10, 0},
+				{10, 5},
+				{12, 5},
 			},
 		},
 		Inside: []Point{
+			{0.5, 0.5},
+			{10, 3},
+			{11, 5},
+		},
+		Outside: []Point{
 			{2, 2},
-			{2, -2},
+			{10, 6},
+			{11, 4},
 		},
 	}.test(t)
 
-	// Contains (horizontal ray intersects two vertices)
+	// Contains (empty)
 	cases{
-		G: &MultiLine{
-			{
-				{0, 0},
-				{0, 4},
-				{2, 4},
-				{3, 2},
-				{4, 4},
-				{6, 4},
-				{8, 2},
-				{6, 0},
-			},
-		},
-		Inside: []Point{
-			{1, 2},
-		},
+		G: &MultiLine{},
 		Outside: []Point{
-			{-1, 2},
+			{0, 0},
 		},
 	}.test(t)
 }
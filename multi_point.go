@@ -1,16 +1,18 @@
 package geo
 
-import "database/sql/driver"
+import (
+	"database/sql/driver"
+	"encoding/binary"
+)
 
 const (
 	mpWKTEmpty   = `MULTIPOINT EMPTY`
-	mpWKTPrefix  = `MULTIPOINT`
 	mpJSONPrefix = `{"type":"MultiPoint","coordinates":[`
 	mpJSONSuffix = `]}`
 )
 
 // MultiPoint is a collection of points.
-type MultiPoint [][3]float64
+type MultiPoint [][4]float64
 
 // Equal compares one MultiPoint to another.
 func (mp MultiPoint) Equal(g Geometry) bool {
@@ -26,9 +28,30 @@ func (mp MultiPoint) Contains(p Point) bool {
 	return pointsContain(mp, p)
 }
 
+// IsEmpty returns true if the MultiPoint has no coordinates.
+func (mp MultiPoint) IsEmpty() bool {
+	return isEmpty(&mp)
+}
+
+// Bounds returns the envelope of the MultiPoint.
+func (mp MultiPoint) Bounds() Envelope {
+	return bounds(&mp)
+}
+
 // MarshalJSON marshals the MultiPoint to JSON.
 func (mp MultiPoint) MarshalJSON() ([]byte, error) {
-	return pointsMarshalJSON(mp, mpJSONPrefix, mpJSONSuffix), nil
+	return mp.geoJSON(LayoutOf(&mp))
+}
+
+// geoJSON marshals the MultiPoint to JSON using the given layout.
+func (mp MultiPoint) geoJSON(layout Layout) ([]byte, error) {
+	return pointsMarshalJSON(mp, mpJSONPrefix, mpJSONSuffix, layout), nil
+}
+
+// MarshalWKB returns the Well Known Binary representation of the MultiPoint
+// using the given byte order.
+func (mp MultiPoint) MarshalWKB(order binary.ByteOrder) ([]byte, error) {
+	return marshalWKB(&mp, order)
 }
 
 // Scan scans a MultiPoint from Well Known Text.
@@ -38,20 +61,25 @@ func (mp *MultiPoint) Scan(src interface{}) error {
 
 // scan scans a MultiPoint from a Well Known Text string.
 func (mp *MultiPoint) scan(s string) error {
-	points, err := pointsScanPrefix(s, mpWKTPrefix, MultiPointType)
+	g, _, err := parseWKT(s, multiPointWKTTag)
 	if err != nil {
 		return err
 	}
-	*mp = points
+	*mp = *withoutLayout(g).(*MultiPoint)
 	return nil
 }
 
 // String converts the MultiPoint to a string.
 func (mp MultiPoint) String() string {
+	return mp.wkt(LayoutOf(&mp))
+}
+
+// wkt converts the MultiPoint to a string using the given layout.
+func (mp MultiPoint) wkt(layout Layout) string {
 	if len(mp) == 0 {
 		return mpWKTEmpty
 	}
-	return mpWKTPrefix + pointsString(mp)
+	return wktPrefix(multiPointWKTTag, layout) + pointsString(mp, layout)
 }
 
 // UnmarshalJSON unmarshals a MultiPoint from GeoJSON.
@@ -64,6 +92,16 @@ func (mp *MultiPoint) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// UnmarshalWKB unmarshals the MultiPoint from Well Known Binary.
+func (mp *MultiPoint) UnmarshalWKB(data []byte) error {
+	g, _, err := unmarshalWKB(data, wkbMultiPoint)
+	if err != nil {
+		return err
+	}
+	*mp = *withoutLayout(g).(*MultiPoint)
+	return nil
+}
+
 // Value returns a driver Value.
 func (mp MultiPoint) Value() (driver.Value, error) {
 	return mp.String(), nil
@@ -71,9 +109,9 @@ func (mp MultiPoint) Value() (driver.Value, error) {
 
 // Transform transforms the geometry point by point.
 func (mp *MultiPoint) Transform(t Transformer) {
-	nl := make([][3]float64, len(*mp))
+	nl := make([][4]float64, len(*mp))
 	for i, point := range *mp {
-		nl[i] = [3]float64(t.Transform(point))
+		nl[i] = [4]float64(t.Transform(point))
 	}
 	*mp = nl
 }
@@ -2,16 +2,9 @@ package geo
 
 import (
 	"database/sql/driver"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
-	"strings"
-
-	"github.com/pkg/errors"
-)
-
-const (
-	multiPolygonWKTPrefix = `MULTIPOLYGON(`
-	multiPolygonWKTSuffix = `)`
 )
 
 var (
@@ -20,7 +13,7 @@ var (
 )
 
 // MultiPolygon is a GeoJSON MultiPolygon.
-type MultiPolygon [][][][3]float64
+type MultiPolygon [][][][4]float64
 
 // Equal compares one polygon to another.
 func (multiPolygon MultiPolygon) Equal(g Geometry) bool {
@@ -45,19 +38,43 @@ func (multiPolygon MultiPolygon) Equal(g Geometry) bool {
 	return true
 }
 
-// Contains uses the ray casting algorithm to decide
-// if the point is contained in the polygon.
+// Contains returns true if any polygon in the MultiPolygon contains the point,
+// including points on the boundary of a polygon.
 func (multiPolygon MultiPolygon) Contains(point Point) bool {
+	return multiPolygon.Locate(point) != Exterior
+}
+
+// Locate returns the location of the point relative to the MultiPolygon.
+func (multiPolygon MultiPolygon) Locate(point Point) Location {
+	loc := Exterior
 	for _, poly := range multiPolygon {
-		if !Polygon(poly).Contains(point) {
-			return false
+		switch Polygon(poly).Locate(point) {
+		case Interior:
+			return Interior
+		case Boundary:
+			loc = Boundary
 		}
 	}
-	return true
+	return loc
+}
+
+// IsEmpty returns true if the MultiPolygon has no coordinates.
+func (multiPolygon MultiPolygon) IsEmpty() bool {
+	return isEmpty(&multiPolygon)
+}
+
+// Bounds returns the envelope of the MultiPolygon.
+func (multiPolygon MultiPolygon) Bounds() Envelope {
+	return bounds(&multiPolygon)
 }
 
 // MarshalJSON returns the GeoJSON representation of the polygon.
 func (multiPolygon MultiPolygon) MarshalJSON() ([]byte, error) {
+	return multiPolygon.geoJSON(LayoutOf(&multiPolygon))
+}
+
+// geoJSON returns the GeoJSON representation of the polygon using the given layout.
+func (multiPolygon MultiPolygon) geoJSON(layout Layout) ([]byte, error) {
 	s := multiPolygonJSONPrefix
 	for i, poly := range multiPolygon {
 		if i == 0 {
@@ -71,7 +88,7 @@ func (multiPolygon MultiPolygon) MarshalJSON() ([]byte, error) {
 			} else {
 				s = append(s, ',', '[')
 			}
-			s = append(s, pointsMarshalJSON(line, "", "")...)
+			s = append(s, pointsMarshalJSON(line, "", "", layout)...)
 			s = append(s, ']')
 		}
 		s = append(s, ']')
@@ -79,6 +96,12 @@ func (multiPolygon MultiPolygon) MarshalJSON() ([]byte, error) {
 	return append(s, multiPolygonJSONSuffix...), nil
 }
 
+// MarshalWKB returns the Well Known Binary representation of the polygon
+// using the given byte order.
+func (multiPolygon MultiPolygon) MarshalWKB(order binary.ByteOrder) ([]byte, error) {
+	return marshalWKB(&multiPolygon, order)
+}
+
 // Scan scans a polygon from Well Known Text.
 func (multiPolygon *MultiPolygon) Scan(src interface{}) error {
 	return scan(multiPolygon, src)
@@ -86,50 +109,25 @@ func (multiPolygon *MultiPolygon) Scan(src interface{}) error {
 
 // scan scans a polygon from a Well Known Text string.
 func (multiPolygon *MultiPolygon) scan(s string) error {
-	if i := strings.Index(s, multiPolygonWKTPrefix); i != 0 {
-		return errors.Errorf("malformed multi polygon %s", s)
-	}
-	l := len(s)
-
-	if s[l-len(multiPolygonWKTSuffix):] != multiPolygonWKTSuffix {
-		return errors.Errorf("malformed multi polygon %s", s)
-	}
-	s = s[len(multiPolygonWKTPrefix) : l-len(multiPolygonWKTSuffix)]
-
-	// empty the polygon
-	*multiPolygon = MultiPolygon{}
-
-	// Split the string into polygons.
-	// We have to split on double parens because single parens
-	// would split the polygons themselves.
-	// The first polygon will have a leading double parens, i.e. "(("
-	// and the last polygon will have a trailing double parens, i.e. "))"
-	polygons := strings.Split(s, ")),((")
-
-	// Get the coordinates.
-	for _, polys := range polygons {
-		var (
-			poly   = [][][3]float64{}
-			polyss = strings.Split(polys, "),(")
-		)
-		for _, ss := range polyss {
-			points, err := pointsScan(ss)
-			if err != nil {
-				return err
-			}
-			poly = append(poly, points)
-		}
-		*multiPolygon = append(*multiPolygon, poly)
+	g, _, err := parseWKT(s, multiPolygonWKTTag)
+	if err != nil {
+		return err
 	}
+	*multiPolygon = *withoutLayout(g).(*MultiPolygon)
 	return nil
 }
 
 // String converts the polygon to a string.
 func (multiPolygon MultiPolygon) String() string {
+	return multiPolygon.wkt(LayoutOf(&multiPolygon))
+}
+
+// wkt returns the Well Known Text representation of the polygon using the given layout.
+func (multiPolygon MultiPolygon) wkt(layout Layout) string {
 	if len(multiPolygon) == 0 {
 		return "MULTIPOLYGON EMPTY"
 	}
-	s := multiPolygonWKTPrefix
+	s := wktPrefix(multiPolygonWKTTag, layout) + "("
 	for i, polys := range multiPolygon {
 		if i == 0 {
 			s += "("
@@ -138,14 +136,14 @@ func (multiPolygon MultiPolygon) String() string {
 		}
 		for j, points := range polys {
 			if j == 0 {
-				s += pointsString(points)
+				s += pointsString(points, layout)
 			} else {
-				s += "," + pointsString(points)
+				s += "," + pointsString(points, layout)
 			}
 		}
 		s += ")"
 	}
-	return s + multiPolygonWKTSuffix
+	return s + ")"
 }
 
 // UnmarshalJSON unmarshals the polygon from GeoJSON.
@@ -158,11 +156,22 @@ func (multiPolygon *MultiPolygon) UnmarshalJSON(data []byte) error {
 	if expected, got := MultiPolygonType, g.Type; expected != got {
 		return fmt.Errorf("expected type %s, got %s", expected, got)
 	}
-	p := [][][][3]float64{}
+	p := [][][][4]float64{}
 	if err := json.Unmarshal(g.Coordinates, &p); err != nil {
 		return err
 	}
 	*multiPolygon = MultiPolygon(p)
+	multiPolygon.Transform(layoutMarker(positionLayout(g.Coordinates)))
+	return nil
+}
+
+// UnmarshalWKB unmarshals the polygon from Well Known Binary.
+func (multiPolygon *MultiPolygon) UnmarshalWKB(data []byte) error {
+	g, _, err := unmarshalWKB(data, wkbMultiPolygon)
+	if err != nil {
+		return err
+	}
+	*multiPolygon = *withoutLayout(g).(*MultiPolygon)
 	return nil
 }
 
@@ -173,11 +182,11 @@ func (multiPolygon MultiPolygon) Value() (driver.Value, error) {
 
 // Transform transforms the geometry point by point.
 func (multiPolygon *MultiPolygon) Transform(t Transformer) {
-	nmp := make([][][][3]float64, len(*multiPolygon))
+	nmp := make([][][][4]float64, len(*multiPolygon))
 	for i, poly := range *multiPolygon {
-		np := make([][][3]float64, len(poly))
+		np := make([][][4]float64, len(poly))
 		for j, line := range poly {
-			nl := make([][3]float64, len(line))
+			nl := make([][4]float64, len(line))
 			for k, point := range line {
 				nl[k] = t.Transform(point)
 			}
@@ -174,6 +174,27 @@ func TestMultiPolygonContains(t *testing.T) {
 			{-1, 2},
 		},
 	}.test(t)
+
+	// Contains (islands)
+	cases{
+		G: &MultiPolygon{
+			{
+				{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}},
+			},
+			{
+				{{10, 10}, {14, 10}, {14, 14}, {10, 14}, {10, 10}},
+				{{11, 11}, {13, 11}, {13, 13}, {11, 13}, {11, 11}},
+			},
+		},
+		Inside: []Point{
+			{1, 1},
+			{10.5, 12},
+		},
+		Outside: []Point{
+			{5, 5},
+			{12, 12},
+		},
+	}.test(t)
 }
 
 func TestMultiPolygonEmpty(t *testing.T) {
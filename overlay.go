@@ -0,0 +1,232 @@
+package geo
+
+import (
+	"errors"
+	"math"
+)
+
+// The overlay operations in this file combine the areas of two geometries.
+// Only their polygons count: a Circle is treated as a polygon with 64 vertices
+// on its circumference, and points and lines are ignored.
+// The polygons of a geometry should be valid, see Validate and MakeValid,
+// except that the polygons of a MultiPolygon or a collection may overlap,
+// in which case they are treated as their union.
+//
+// The result is always a MultiPolygon that passes Validate, with its exterior
+// rings wound counterclockwise and its holes clockwise. Polygons that only touch
+// at a point are kept apart, parts of the result that have no area are dropped,
+// and an empty result is an empty MultiPolygon.
+//
+// Rarely, edges are so nearly parallel or so close together that no valid
+// result can be found, even after the geometries are snap rounded to a grid.
+// The polygons of the result that are valid are then returned with an error,
+// since some of the area is missing from them.
+
+// errOverlayInvalid is returned by the overlay operations along with a result
+// that had to be made valid by dropping some of its polygons.
+var errOverlayInvalid = errors.New("overlay: could not find a valid result, some polygons were dropped")
+
+// Union returns the area that is inside either geometry.
+// The union of a geometry with an empty one merges its overlapping polygons.
+func Union(a, b Geometry) (MultiPolygon, error) {
+	return overlay(a, b, func(inA, inB bool) bool {
+		return inA || inB
+	})
+}
+
+// Intersection returns the area that is inside both geometries,
+// e.g. the part of a service area that is in a county.
+func Intersection(a, b Geometry) (MultiPolygon, error) {
+	return overlay(a, b, func(inA, inB bool) bool {
+		return inA && inB
+	})
+}
+
+// Difference returns the area that is inside a but not inside b.
+func Difference(a, b Geometry) (MultiPolygon, error) {
+	return overlay(a, b, func(inA, inB bool) bool {
+		return inA && !inB
+	})
+}
+
+// SymDifference returns the area that is inside exactly one of the geometries.
+func SymDifference(a, b Geometry) (MultiPolygon, error) {
+	return overlay(a, b, func(inA, inB bool) bool {
+		return inA != inB
+	})
+}
+
+// overlayEdge is an edge of the result of an overlay,
+// directed so that the result is on its left.
+type overlayEdge struct {
+	from, to [4]float64
+	used     bool
+}
+
+// overlayGrids are the sizes of the grids, relative to the largest coordinate,
+// that the geometries of an overlay are snap rounded to if the result cannot be trusted.
+var overlayGrids = []float64{1e-12, 1e-10, 1e-8}
+
+// overlay returns the area where op is true, given whether a point is inside each geometry.
+//
+// Points where edges cross are rounded, so when edges are nearly parallel
+// or vertices are within a few units in the last place of other edges,
+// the pieces of the edges may not quite fit together. If the result has rings
+// that cannot be closed or is not valid, the overlay is tried again with the
+// geometries snap rounded to increasingly coarse grids, which makes every
+// crossing a vertex so that the rest of the overlay is exact.
+// If even that fails, the polygons that spoil the last result are dropped,
+// so that the result is valid, and errOverlayInvalid is returned with it
+// since it may be missing some area.
+func overlay(a, b Geometry, op func(inA, inB bool) bool) (MultiPolygon, error) {
+	var (
+		ga, gb     = polygonalParts(a), polygonalParts(b)
+		result, ok = overlayParts(ga, gb, op)
+	)
+	if ok && Validate(&result) == nil {
+		return result, nil
+	}
+	magnitude := math.Max(ga.magnitude(), gb.magnitude())
+	for _, size := range overlayGrids {
+		snapped := snapRound([]relateGeometry{ga, gb}, gridSize(magnitude*size))
+		result, ok = overlayParts(snapped[0], snapped[1], op)
+		if ok && Validate(&result) == nil {
+			return result, nil
+		}
+	}
+	return validPolygons(result), errOverlayInvalid
+}
+
+// validPolygons returns the polygons of a MultiPolygon that are valid
+// and do not overlap any polygon before them, which together are valid.
+func validPolygons(mp MultiPolygon) MultiPolygon {
+	var (
+		valid  = MultiPolygon{}
+		bounds []Envelope
+	)
+	for _, poly := range mp {
+		if validatePolygon(poly) != nil {
+			continue
+		}
+		env, overlaps := Polygon(poly).Bounds(), false
+		for i, other := range valid {
+			if !bounds[i].Intersects(env) {
+				continue
+			}
+			if _, overlaps = polygonsOverlap(poly, other); overlaps {
+				break
+			}
+		}
+		if !overlaps {
+			valid, bounds = append(valid, poly), append(bounds, env)
+		}
+	}
+	return valid
+}
+
+// overlayParts returns the area where op is true for the polygons of two geometries,
+// and false if any of its rings could not be closed.
+//
+// The rings of both geometries are split wherever they meet, and the pieces that
+// separate the result from the rest of the plane are linked into rings at the
+// nodes, turning as sharply left as possible so that each ring goes around
+// a single face. Rings that still visit a node twice are split there,
+// and the counterclockwise rings become the exterior rings of the result
+// and the clockwise ones its holes.
+func overlayParts(ga, gb relateGeometry, op func(inA, inB bool) bool) (MultiPolygon, bool) {
+	var (
+		graph = newRelateGraph(ga, gb)
+		edges []*overlayEdge
+		out   = map[[2]float64][]*overlayEdge{}
+	)
+	for _, e := range graph.edges {
+		var (
+			_, leftA, rightA = graph.locateEdge(0, e)
+			_, leftB, rightB = graph.locateEdge(1, e)
+			left, right      = op(leftA, leftB), op(rightA, rightB)
+			edge             *overlayEdge
+		)
+		switch {
+		case left && !right:
+			edge = &overlayEdge{from: e.a, to: e.b}
+		case right && !left:
+			edge = &overlayEdge{from: e.b, to: e.a}
+		default:
+			continue
+		}
+		edges = append(edges, edge)
+		key := [2]float64{edge.from[0], edge.from[1]}
+		out[key] = append(out[key], edge)
+	}
+
+	var (
+		shells, holes [][][4]float64
+		closed        = true
+	)
+	for _, start := range edges {
+		if start.used {
+			continue
+		}
+		ring, ok := traceRing(start, out)
+		closed = closed && ok
+		for _, loop := range splitLoops(nodeRing(ring)) {
+			loop = cleanRing(loop)
+			switch area := signedArea(loop); {
+			case area > 0:
+				shells = append(shells, loop)
+			case area < 0:
+				holes = append(holes, loop)
+			}
+		}
+	}
+	return MultiPolygon(nestHoles(shells, holes)), closed
+}
+
+// traceRing follows the unused edges of an overlay from start back to start,
+// marking them as used, and returns the ring that they make.
+// If it runs out of edges before it gets back, the ring is closed anyway
+// and false is returned.
+func traceRing(start *overlayEdge, out map[[2]float64][]*overlayEdge) ([][4]float64, bool) {
+	ring := [][4]float64{start.from}
+	for edge := start; ; {
+		edge.used = true
+		ring = append(ring, edge.to)
+
+		// Turn as sharply left as possible, which is the last way out
+		// turning counterclockwise from the way back.
+		var next *overlayEdge
+		for _, candidate := range out[[2]float64{edge.to[0], edge.to[1]}] {
+			if candidate.used && candidate != start {
+				continue
+			}
+			if next == nil || ccwBefore(edge.to, edge.from, next.to, candidate.to) {
+				next = candidate
+			}
+		}
+		if next == start {
+			return ring, true
+		}
+		if next == nil {
+			return append(ring, start.from), false
+		}
+		edge = next
+	}
+}
+
+// polygonalParts returns the polygons of a geometry, broken into parts.
+func polygonalParts(g Geometry) relateGeometry {
+	return relateGeometry{polygons: newRelateGeometry(g).polygons}
+}
+
+// magnitude returns the largest absolute value of the coordinates of the polygons.
+func (rg relateGeometry) magnitude() float64 {
+	max := 0.0
+	for _, poly := range rg.polygons {
+		for _, ring := range poly {
+			for _, p := range ring {
+				max = math.Max(max, math.Max(math.Abs(p[0]), math.Abs(p[1])))
+			}
+		}
+	}
+	return max
+}
@@ -0,0 +1,223 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// overlayTestcases is a helper type for tests of the overlay operations.
+type overlayTestcases []struct {
+	A, B     Geometry
+	Op       func(a, b Geometry) (MultiPolygon, error)
+	Polygons int
+	Holes    int
+	Area     float64
+}
+
+// test checks the number of polygons and holes and the area of each result,
+// and that it is valid without an error.
+func (tests overlayTestcases) test(t *testing.T) {
+	for i, testcase := range tests {
+		result, err := testcase.Op(testcase.A, testcase.B)
+		if err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if errs := Validate(&result); errs != nil {
+			t.Fatalf("(case %d) expected a valid result, got %s (%s)", i, errs, result)
+		}
+		if expected, got := testcase.Polygons, len(result); expected != got {
+			t.Fatalf("(case %d) expected %d polygons, got %d (%s)", i, expected, got, result)
+		}
+		holes := 0
+		for _, poly := range result {
+			holes += len(poly) - 1
+		}
+		if expected, got := testcase.Holes, holes; expected != got {
+			t.Fatalf("(case %d) expected %d holes, got %d (%s)", i, expected, got, result)
+		}
+		if expected, got := testcase.Area, Area(&result); math.Abs(expected-got) > 1e-9 {
+			t.Fatalf("(case %d) expected area %f, got %f", i, expected, got)
+		}
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	var (
+		square  = &Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+		shifted = &Polygon{{{5, 5}, {15, 5}, {15, 15}, {5, 15}, {5, 5}}}
+	)
+	overlayTestcases{
+		{A: square, B: shifted, Op: Union, Polygons: 1, Area: 175},
+		{A: square, B: shifted, Op: Intersection, Polygons: 1, Area: 25},
+		{A: square, B: shifted, Op: Difference, Polygons: 1, Area: 75},
+		{A: shifted, B: square, Op: Difference, Polygons: 1, Area: 75},
+		{
+			// Two L shapes that touch at two corners.
+			A: square, B: shifted, Op: SymDifference, Polygons: 2, Area: 150,
+		},
+		{A: square, B: &Polygon{{{20, 0}, {30, 0}, {30, 10}, {20, 0}}}, Op: Union, Polygons: 2, Area: 150},
+		{A: square, B: &Polygon{{{20, 0}, {30, 0}, {30, 10}, {20, 0}}}, Op: Intersection, Area: 0},
+		{A: square, B: &Polygon{}, Op: Union, Polygons: 1, Area: 100},
+		{A: &Polygon{}, B: square, Op: Difference, Area: 0},
+		{A: &MultiPolygon{}, B: &GeometryCollection{}, Op: Union, Area: 0},
+		{
+			// A corner that overlaps the other triangle by a tiny amount.
+			A:  &Polygon{{{0, 0}, {10, 0}, {5, 5 + 1e-13}, {0, 0}}},
+			B:  &Polygon{{{0, 5}, {10, 5}, {5, 10}, {0, 5}}},
+			Op: Union, Polygons: 1, Area: 50,
+		},
+	}.test(t)
+}
+
+func TestOverlaySharedEdges(t *testing.T) {
+	var (
+		square   = &Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+		adjacent = &Polygon{{{10, 0}, {20, 0}, {20, 10}, {10, 10}, {10, 0}}}
+	)
+	overlayTestcases{
+		{A: square, B: adjacent, Op: Union, Polygons: 1, Area: 200},
+		{A: square, B: adjacent, Op: Intersection, Area: 0},
+		{A: square, B: adjacent, Op: Difference, Polygons: 1, Area: 100},
+		{A: square, B: adjacent, Op: SymDifference, Polygons: 1, Area: 200},
+		{
+			// Sharing part of an edge.
+			A: square, B: &Polygon{{{10, 2}, {20, 2}, {20, 8}, {10, 8}, {10, 2}}}, Op: Union, Polygons: 1, Area: 160,
+		},
+		{
+			// Sharing an edge from the inside.
+			A: square, B: &Polygon{{{0, 0}, {5, 0}, {5, 10}, {0, 10}, {0, 0}}}, Op: Difference, Polygons: 1, Area: 50,
+		},
+		{A: square, B: square, Op: Union, Polygons: 1, Area: 100},
+		{A: square, B: square, Op: Intersection, Polygons: 1, Area: 100},
+		{A: square, B: square, Op: Difference, Area: 0},
+		{A: square, B: square, Op: SymDifference, Area: 0},
+		{
+			// Touching at a corner.
+			A: square, B: &Polygon{{{10, 10}, {20, 10}, {20, 20}, {10, 20}, {10, 10}}}, Op: Union, Polygons: 2, Area: 200,
+		},
+		{
+			A: square, B: &Polygon{{{10, 10}, {20, 10}, {20, 20}, {10, 20}, {10, 10}}}, Op: Intersection, Area: 0,
+		},
+	}.test(t)
+
+	// The shared edge is dissolved.
+	union, err := Union(square, adjacent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := 5, len(union[0][0]); expected != got {
+		t.Fatalf("expected %d points, got %d (%s)", expected, got, union)
+	}
+}
+
+func TestOverlayHoles(t *testing.T) {
+	var (
+		square = &Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+		donut  = &Polygon{
+			{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+			{{2, 2}, {2, 8}, {8, 8}, {8, 2}, {2, 2}},
+		}
+	)
+	overlayTestcases{
+		{A: square, B: &Polygon{{{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}}}, Op: Difference, Polygons: 1, Holes: 1, Area: 64},
+		{
+			// An island in the hole.
+			A: donut, B: &Polygon{{{3, 3}, {7, 3}, {7, 7}, {3, 7}, {3, 3}}}, Op: Union, Polygons: 2, Holes: 1, Area: 80,
+		},
+		{
+			// Filling the hole.
+			A: donut, B: &Polygon{{{1, 1}, {9, 1}, {9, 9}, {1, 9}, {1, 1}}}, Op: Union, Polygons: 1, Area: 100,
+		},
+		{
+			// Exactly filling the hole.
+			A: donut, B: &Polygon{{{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}}}, Op: Union, Polygons: 1, Area: 100,
+		},
+		{A: donut, B: &Polygon{{{3, 3}, {7, 3}, {7, 7}, {3, 7}, {3, 3}}}, Op: Intersection, Area: 0},
+		{
+			// Across the hole.
+			A: donut, B: &Polygon{{{-1, 4}, {11, 4}, {11, 6}, {-1, 6}, {-1, 4}}}, Op: Intersection, Polygons: 2, Area: 8,
+		},
+		{A: donut, B: &Polygon{{{-1, 4}, {11, 4}, {11, 6}, {-1, 6}, {-1, 4}}}, Op: Difference, Polygons: 2, Area: 56},
+		{
+			// A hole that touches the shell at a point.
+			A: square, B: &Polygon{{{5, 0}, {7, 3}, {3, 3}, {5, 0}}}, Op: Difference, Polygons: 1, Holes: 1, Area: 94,
+		},
+		{
+			// A hole that touches the shell at two points splits the polygon.
+			A: square, B: &Polygon{{{0, 5}, {5, 2}, {10, 5}, {5, 8}, {0, 5}}}, Op: Difference, Polygons: 2, Area: 70,
+		},
+	}.test(t)
+}
+
+func TestOverlayCollections(t *testing.T) {
+	var (
+		county       = &Feature{Geometry: &Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}}
+		serviceAreas = &FeatureCollection{
+			{Geometry: &Polygon{{{-5, -5}, {5, -5}, {5, 5}, {-5, 5}, {-5, -5}}}},
+			{Geometry: &Polygon{{{8, 8}, {8, 12}, {12, 12}, {12, 8}, {8, 8}}}},
+			{Geometry: &Point{5, 5}},
+		}
+		overlapping = &MultiPolygon{
+			{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}},
+			{{{5, 5}, {15, 5}, {15, 15}, {5, 15}, {5, 5}}},
+		}
+	)
+	overlayTestcases{
+		{A: serviceAreas, B: county, Op: Intersection, Polygons: 2, Area: 29},
+		{A: serviceAreas, B: county, Op: Difference, Polygons: 2, Area: 87},
+		{A: overlapping, B: &MultiPolygon{}, Op: Union, Polygons: 1, Area: 175},
+		{A: WithSRID(4326, overlapping), B: &Polygon{{{0, 0}, {15, 0}, {15, 15}, {0, 15}, {0, 0}}}, Op: SymDifference, Polygons: 2, Area: 50},
+		{
+			// Rings that are not closed and wound the wrong way.
+			A: &Polygon{{{0, 0}, {0, 10}, {10, 10}, {10, 0}}}, B: &Polygon{{{5, 0}, {5, 10}, {20, 10}, {20, 0}}}, Op: Difference, Polygons: 1, Area: 50,
+		},
+	}.test(t)
+
+	// Exterior rings are counterclockwise and holes are clockwise.
+	result, err := Difference(county, &Polygon{{{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signedArea(result[0][0]) <= 0 || signedArea(result[0][1]) >= 0 {
+		t.Fatalf("expected a counterclockwise shell and a clockwise hole, got %s", result)
+	}
+}
+
+func TestValidPolygons(t *testing.T) {
+	var (
+		square  = [][][4]float64{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+		overlap = [][][4]float64{{{5, 5}, {15, 5}, {15, 15}, {5, 15}, {5, 5}}}
+		bowtie  = [][][4]float64{{{20, 0}, {30, 10}, {30, 0}, {20, 10}, {20, 0}}}
+		touches = [][][4]float64{{{10, 10}, {20, 10}, {20, 20}, {10, 10}}}
+	)
+	valid := validPolygons(MultiPolygon{square, bowtie, overlap, touches})
+	if errs := Validate(&valid); errs != nil {
+		t.Fatalf("expected a valid result, got %s", errs)
+	}
+	if expected := (MultiPolygon{square, touches}); !expected.Equal(&valid) {
+		t.Fatalf("expected %s, got %s", expected, valid)
+	}
+}
+
+func TestOverlayInvalid(t *testing.T) {
+	// A sliver whose vertices are two crossings of three nearly concurrent edges,
+	// rounded to different points, which none of the grids make valid.
+	var (
+		a = &MultiPolygon{
+			{{{2.9411764705882355, 2.88235294117647}, {3.1707317073170733, 2.560975609756097}, {5, 0}, {4.444444444444445, 2.7777777777777777}, {4, 2}, {4.375, 3.125}, {4.2105263157894735, 3.9473684210526314}, {4.2105263157894735, 3.947368421052632}, {4, 5}, {2.9411764705882355, 2.88235294117647}}},
+			{{{4.375, 3.125}, {4.444444444444445, 2.7777777777777777}, {8, 9}, {5, 5}, {4.375, 3.125}}},
+			{{{2, 1}, {2.9411764705882355, 2.88235294117647}, {0, 7}, {2, 1}}},
+		}
+		b = &Polygon{{{3.1707317073170733, 2.560975609756097}, {4.2105263157894735, 3.9473684210526314}, {5, 5}, {4.2105263157894735, 3.947368421052632}, {3.1707317073170733, 2.560975609756097}}}
+	)
+	result, err := SymDifference(a, b)
+	if err != errOverlayInvalid {
+		t.Fatalf("expected %v, got %v", errOverlayInvalid, err)
+	}
+	if errs := Validate(&result); errs != nil {
+		t.Fatalf("expected a valid result, got %s (%s)", errs, result)
+	}
+	if len(result) == 0 || Area(&result) >= Area(a) {
+		t.Fatalf("expected some but not all of the area, got %f of %f (%s)", Area(&result), Area(a), result)
+	}
+}
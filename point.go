@@ -2,20 +2,18 @@ package geo
 
 import (
 	"database/sql/driver"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"math"
-	"strconv"
 )
 
 const (
-	pointWKTPrefix  = `POINT`
-	pointWKT        = `(%f %f)`
-	pointJSONPrefix = `{"type":"Point","coordinates":[`
+	pointJSONPrefix = `{"type":"Point","coordinates":`
 )
 
 // Point defines a point.
-type Point [3]float64
+type Point [4]float64
 
 // Equal compares one point to another.
 func (point Point) Equal(g Geometry) bool {
@@ -23,18 +21,28 @@ func (point Point) Equal(g Geometry) bool {
 	if !ok {
 		return false
 	}
+	if point.IsEmpty() || pt.IsEmpty() {
+		return point.IsEmpty() && pt.IsEmpty()
+	}
 	if point[0] != (*pt)[0] {
 		return false
 	}
 	if point[1] != (*pt)[1] {
 		return false
 	}
+	if point[2] != (*pt)[2] {
+		return false
+	}
+	if point[3] != (*pt)[3] {
+		return false
+	}
 	return true
 }
 
-// Contains is the exact same as Equal.
+// Contains returns true if the other point has the same X and Y.
+// Like Locate, it ignores the Z and M ordinates.
 func (point Point) Contains(other Point) bool {
-	return point.Equal(&other)
+	return point[0] == other[0] && point[1] == other[1]
 }
 
 // DistanceFrom computes the distance from one point to another.
@@ -42,62 +50,83 @@ func (point Point) DistanceFrom(other Point) float64 {
 	return math.Sqrt(math.Pow(point[1]-other[1], 2) + math.Pow(point[0]-other[0], 2))
 }
 
+// IsEmpty returns true if the point is empty, i.e. POINT EMPTY.
+// Empty points have NaN coordinates.
+func (point Point) IsEmpty() bool {
+	return math.IsNaN(point[0]) || math.IsNaN(point[1])
+}
+
+// Bounds returns the envelope of the point.
+func (point Point) Bounds() Envelope {
+	return bounds(&point)
+}
+
 // MarshalJSON returns the GeoJSON representation of the point.
 func (point Point) MarshalJSON() ([]byte, error) {
-	s := pointJSONPrefix
-	s += strconv.FormatFloat(point[0], 'f', -1, 64) + ","
-	s += strconv.FormatFloat(point[1], 'f', -1, 64) + "]}"
-	return []byte(s), nil
+	return point.geoJSON(LayoutOf(&point))
+}
+
+// geoJSON returns the GeoJSON representation of the point using the given layout.
+func (point Point) geoJSON(layout Layout) ([]byte, error) {
+	if point.IsEmpty() {
+		return []byte(pointJSONPrefix + "[]}"), nil
+	}
+	s := pointJSONPrefix + coordJSON(point, layout)
+	return []byte(s + "}"), nil
+}
+
+// MarshalWKB returns the Well Known Binary representation of the point
+// using the given byte order.
+func (point Point) MarshalWKB(order binary.ByteOrder) ([]byte, error) {
+	return marshalWKB(&point, order)
 }
 
 // RayhIntersects returns true if the horizontal ray going from
 // point to positive infinity intersects the line that connects a and b.
+// The lower end of the line counts as an intersection but the upper end does not,
+// so horizontal lines are never intersected.
+// A point that is on the line intersects it.
 func (point Point) RayhIntersects(a, b Point) bool {
-	var (
-		left   = math.Min(a[0], b[0])
-		right  = math.Max(a[0], b[0])
-		bottom = math.Min(a[1], b[1])
-		top    = math.Max(a[1], b[1])
-	)
-	if point[0] > right {
-		return false
+	lower, upper := a, b
+	if lower[1] > upper[1] {
+		lower, upper = upper, lower
 	}
-	if point[1] >= top || point[1] < bottom {
+	if point[1] >= upper[1] || point[1] < lower[1] {
 		return false
 	}
-	if point[0] <= left {
-		return true
-	}
-	slope := (b[1] - a[1]) / (b[0] - a[0])
-	if slope >= 0 {
-		return ((point[1] - bottom) / (point[0] - left)) >= slope
+	if point[0] > lower[0] && point[0] > upper[0] {
+		return false
 	}
-	return ((point[1] - top) / (point[0] - left)) <= slope
+	// The line is to the right of the point if the point is to the left of it going up.
+	return orient2d(lower, upper, point) >= 0
 }
 
 // Scan scans a point from Well Known Text.
 func (point *Point) Scan(src interface{}) error {
-	switch v := src.(type) {
-	case []byte:
-		if _, err := fmt.Sscanf(string(v), pointWKTPrefix+pointWKT, &point[0], &point[1]); err != nil {
-			return err
-		}
-	case string:
-		if _, err := fmt.Sscanf(v, pointWKTPrefix+pointWKT, &point[0], &point[1]); err != nil {
-			return err
-		}
-	default:
-		return fmt.Errorf("could not scan point from %T", src)
+	return scan(point, src)
+}
+
+// scan scans a point from a Well Known Text string.
+func (point *Point) scan(s string) error {
+	g, _, err := parseWKT(s, pointWKTTag)
+	if err != nil {
+		return err
 	}
+	*point = *withoutLayout(g).(*Point)
 	return nil
 }
 
 // String convert the point to a string.
 func (point Point) String() string {
-	s := "POINT("
-	s += strconv.FormatFloat(point[0], 'f', -1, 64)
-	s += " " + strconv.FormatFloat(point[1], 'f', -1, 64) + ")"
-	return s
+	return point.wkt(LayoutOf(&point))
+}
+
+// wkt converts the point to a string using the given layout.
+func (point Point) wkt(layout Layout) string {
+	if point.IsEmpty() {
+		return pointWKTTag + " " + emptyWKTTag
+	}
+	return wktPrefix(pointWKTTag, layout) + "(" + coordString(point, layout) + ")"
 }
 
 // UnmarshalJSON unmarshals a point from GeoJSON.
@@ -111,11 +140,36 @@ func (point *Point) UnmarshalJSON(data []byte) error {
 		return fmt.Errorf("expected %s type, got %s", expected, got)
 	}
 
-	pt := [3]float64{}
-	if err := json.Unmarshal(g.Coordinates, &pt); err != nil {
+	pt, err := unmarshalPosition(g.Coordinates)
+	if err != nil {
+		return err
+	}
+	*point = layoutMarker(positionLayout(g.Coordinates)).Transform(pt)
+	return nil
+}
+
+// unmarshalPosition unmarshals a GeoJSON position.
+// An empty array is an empty point.
+func unmarshalPosition(data json.RawMessage) (Point, error) {
+	pos := []float64{}
+	if err := json.Unmarshal(data, &pos); err != nil {
+		return Point{}, err
+	}
+	if len(pos) == 0 {
+		return Point{math.NaN(), math.NaN()}, nil
+	}
+	pt := Point{}
+	copy(pt[:], pos)
+	return pt, nil
+}
+
+// UnmarshalWKB unmarshals the point from Well Known Binary.
+func (point *Point) UnmarshalWKB(data []byte) error {
+	g, _, err := unmarshalWKB(data, wkbPoint)
+	if err != nil {
 		return err
 	}
-	*point = pt
+	*point = *withoutLayout(g).(*Point)
 	return nil
 }
 
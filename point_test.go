@@ -9,6 +9,7 @@ func TestPointEqual(t *testing.T) {
 		Different: []Geometry{
 			&Point{1.2, 3.7},
 			&Point{9.2, 3.7},
+			&Point{1.2, 3.4, 5.6},
 			&Line{{9.2, 3.7}},
 			&Polygon{{{9.2, 3.7}}},
 		},
@@ -18,8 +19,8 @@ func TestPointEqual(t *testing.T) {
 func TestPointContains(t *testing.T) {
 	cases{
 		G:       &Point{1, 1},
-		Inside:  []Point{{1, 1}},
-		Outside: []Point{{1, 1.2}},
+		Inside:  []Point{{1, 1}, {1, 1, 5}, {1, 1, 0, 7}},
+		Outside: []Point{{1, 1.2}, {1, 1.2, 5}},
 	}.test(t)
 }
 
@@ -29,6 +30,10 @@ func TestPointMarshalJSON(t *testing.T) {
 			Input:    &Point{1.2, 3.4},
 			Expected: `{"type":"Point","coordinates":[1.2,3.4]}`,
 		},
+		{
+			Input:    &Point{1.2, 3.4, 5.6},
+			Expected: `{"type":"Point","coordinates":[1.2,3.4,5.6]}`,
+		},
 	}.pass(t)
 }
 
@@ -40,6 +45,11 @@ func TestPointScan(t *testing.T) {
 			Instance: &Point{},
 			Expected: &Point{1.2, 3.4},
 		},
+		{
+			Input:    `POINT Z (1.2 3.4 5.6)`,
+			Instance: &Point{},
+			Expected: &Point{1.2, 3.4, 5.6},
+		},
 	}.pass(t)
 
 	// Bad
@@ -66,6 +76,11 @@ func TestPointUnmarshal(t *testing.T) {
 			Instance: &Point{},
 			Expected: &Point{1, 1},
 		},
+		{
+			Input:    []byte(`{"type":"Point","coordinates":[1,1,2]}`),
+			Instance: &Point{},
+			Expected: &Point{1, 1, 2},
+		},
 	}.pass(t)
 
 	unmarshalTestcases{
@@ -97,3 +112,17 @@ func TestPointValue(t *testing.T) {
 		t.Fatalf("expected %s, got %s", expected, got)
 	}
 }
+
+func TestPointString(t *testing.T) {
+	for i, testcase := range []struct {
+		Input    Point
+		Expected string
+	}{
+		{Input: Point{1, 2}, Expected: `POINT(1 2)`},
+		{Input: Point{1, 2, 3}, Expected: `POINT Z (1 2 3)`},
+	} {
+		if expected, got := testcase.Expected, testcase.Input.String(); expected != got {
+			t.Fatalf("(case %d) expected %s, got %s", i, expected, got)
+		}
+	}
+}
@@ -4,11 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
-	"strings"
 )
 
 // pointsEqual compares two slices of points.
-func pointsEqual(p1, p2 [][3]float64) bool {
+func pointsEqual(p1, p2 [][4]float64) bool {
 	if len(p1) != len(p2) {
 		return false
 	}
@@ -19,12 +18,18 @@ func pointsEqual(p1, p2 [][3]float64) bool {
 		if vertex[1] != p2[i][1] {
 			return false
 		}
+		if vertex[2] != p2[i][2] {
+			return false
+		}
+		if vertex[3] != p2[i][3] {
+			return false
+		}
 	}
 	return true
 }
 
-// pointsContain returns true if the
-func pointsContain(pts [][3]float64, pt [3]float64) bool {
+// pointsContain returns true if the point is on the line that connects the points.
+func pointsContain(pts [][4]float64, pt [4]float64) bool {
 	if len(pts) < 2 {
 		return false
 	}
@@ -35,96 +40,82 @@ func pointsContain(pts [][3]float64, pt [3]float64) bool {
 		if i == 0 {
 			continue
 		}
-		if segmentContains(vertex, pts[i-1], pt) {
+		if onSegment(pts[i-1], vertex, pt) {
 			return true
 		}
 	}
 	return false
 }
 
-// pointsMarshalJSON converts a list of points to JSON.
-func pointsMarshalJSON(points [][3]float64, prefix, suffix string) []byte {
-	s := prefix
-	for i, point := range points {
-		if i == 0 {
-			s += "[" + strconv.FormatFloat(point[0], 'f', -1, 64) + ","
-			s += strconv.FormatFloat(point[1], 'f', -1, 64) + "]"
-		} else {
-			s += ",[" + strconv.FormatFloat(point[0], 'f', -1, 64) + ","
-			s += strconv.FormatFloat(point[1], 'f', -1, 64) + "]"
-		}
-	}
-	return []byte(s + suffix)
+// formatOrdinate formats an ordinate as a decimal number.
+func formatOrdinate(v float64) string {
+	return strconv.FormatFloat(unmarked(v), 'f', -1, 64)
 }
 
-// pointsScan scans a list of points from Well Known Text.
-// The points should look like this: X0 Y0,X1 Y1,X2 Y2.
-// It is also acceptable to have leading and trailing parens, these will be stripped.
-// Examples:
-// * (X0 Y0,X1 Y1,X2 Y2
-// * X0 Y0,X1 Y1,X2 Y2)
-// * (X0 Y0,X1 Y1,X2 Y2)
-func pointsScan(s string) ([][3]float64, error) {
-	// Trim leading and trailing parens.
-	leadingIdx := 0
-	for s[leadingIdx] == '(' {
-		leadingIdx++
+// coordJSON converts a coordinate to a GeoJSON position.
+// GeoJSON has no M ordinate, so layouts with M are written as [x, y, z, m].
+func coordJSON(coord [4]float64, layout Layout) string {
+	s := "[" + formatOrdinate(coord[0])
+	s += "," + formatOrdinate(coord[1])
+	if layout != XY {
+		s += "," + formatOrdinate(coord[2])
 	}
-	s = s[leadingIdx:]
-	trailingIdx := len(s)
-	if trailingIdx > 0 {
-		for s[trailingIdx-1] == ')' {
-			trailingIdx--
-		}
-		s = s[:trailingIdx]
+	if layout.HasM() {
+		s += "," + formatOrdinate(coord[3])
 	}
+	return s + "]"
+}
 
-	// At this point s should look like this:
-	// x x, x x, x x, ...
-	// Where x is a float.
-	points := [][3]float64{}
-	for _, coords := range strings.Split(s, ",") {
-		var (
-			pair = [3]float64{}
-			xy   = strings.Split(strings.TrimSpace(coords), " ")
-		)
-		if len(xy) != 2 {
-			return nil, fmt.Errorf("could not scan points from %s (xy=%s)", s, xy)
-		}
-		for i, val := range xy {
-			f, err := strconv.ParseFloat(val, 64)
-			if err != nil {
-				return nil, err
-			}
-			pair[i] = f
-		}
-		points = append(points, pair)
+// coordString converts a coordinate to Well Known Text.
+func coordString(coord [4]float64, layout Layout) string {
+	s := formatOrdinate(coord[0])
+	s += " " + formatOrdinate(coord[1])
+	if layout.HasZ() {
+		s += " " + formatOrdinate(coord[2])
+	}
+	if layout.HasM() {
+		s += " " + formatOrdinate(coord[3])
 	}
-	return points, nil
+	return s
 }
 
-// pointsScanPrefix scans a string form points, and expects the given prefix.
-func pointsScanPrefix(s, prefix, typeName string) ([][3]float64, error) {
-	if len(s) <= len(prefix) {
-		return nil, fmt.Errorf("could not scan %s from %s", typeName, s)
+// pointsMarshalJSON converts a list of points to JSON.
+func pointsMarshalJSON(points [][4]float64, prefix, suffix string, layout Layout) []byte {
+	s := prefix
+	for i, point := range points {
+		if i == 0 {
+			s += coordJSON(point, layout)
+		} else {
+			s += "," + coordJSON(point, layout)
+		}
 	}
-	return pointsScan(s[len(prefix):])
+	return []byte(s + suffix)
 }
 
 // pointsString converts a slice of points to Well Known Text.
-func pointsString(points [][3]float64) string {
-	s := "("
-	s += strconv.FormatFloat(points[0][0], 'f', -1, 64)
-	s += " " + strconv.FormatFloat(points[0][1], 'f', -1, 64)
+// An empty slice is written as EMPTY.
+func pointsString(points [][4]float64, layout Layout) string {
+	if len(points) == 0 {
+		return emptyWKTTag
+	}
+	s := "(" + coordString(points[0], layout)
 	for _, coord := range points[1:] {
-		s += ", " + strconv.FormatFloat(coord[0], 'f', -1, 64)
-		s += " " + strconv.FormatFloat(coord[1], 'f', -1, 64)
+		s += ", " + coordString(coord, layout)
 	}
 	return s + ")"
 }
 
+// wktPrefix returns a Well Known Text tag followed by
+// the dimension marker of the layout, e.g. "POINT ZM ".
+func wktPrefix(tag string, layout Layout) string {
+	if layout == XY {
+		return tag
+	}
+	return tag + " " + layout.wktDimension() + " "
+}
+
 // pointsUnmarshal unmarshals a slice of points
-func pointsUnmarshal(data []byte, expectedType string) ([][3]float64, error) {
+func pointsUnmarshal(data []byte, expectedType string) ([][4]float64, error) {
 	g := geometry{}
 
 	// Never fails because data is always valid JSON.
@@ -134,9 +125,13 @@ func pointsUnmarshal(data []byte, expectedType string) ([][3]float64, error) {
 		return nil, fmt.Errorf("expected type %s, got %s", expected, got)
 	}
 
-	pts := [][3]float64{}
+	pts := [][4]float64{}
 	if err := json.Unmarshal(g.Coordinates, &pts); err != nil {
 		return nil, err
 	}
+	marker := layoutMarker(positionLayout(g.Coordinates))
+	for i, pt := range pts {
+		pts[i] = marker.Transform(pt)
+	}
 	return pts, nil
 }
@@ -2,14 +2,9 @@ package geo
 
 import (
 	"database/sql/driver"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
-	"strings"
-)
-
-const (
-	polygonWKTPrefix = `POLYGON(`
-	polygonWKTSuffix = `)`
 )
 
 var (
@@ -18,7 +13,7 @@ var (
 )
 
 // Polygon is a GeoJSON Polygon.
-type Polygon [][][3]float64
+type Polygon [][][4]float64
 
 // Equal compares one polygon to another.
 func (polygon Polygon) Equal(g Geometry) bool {
@@ -41,22 +36,53 @@ func (polygon Polygon) Equal(g Geometry) bool {
 	return true
 }
 
-// Contains uses the ray casting algorithm to decide
-// if the point is contained in the polygon.
+// Contains returns true if the point is inside the polygon or on its boundary.
+// Use Locate to tell the two apart.
 func (polygon Polygon) Contains(point Point) bool {
-	intersections := 0
-	for _, poly := range polygon {
-		for j, vertex := range poly {
-			if point.RayhIntersects(poly[(j+1)%len(poly)], vertex) {
-				intersections++
-			}
+	return polygon.Locate(point) != Exterior
+}
+
+// Locate returns the location of the point relative to the polygon.
+// The first ring is the polygon's exterior ring and the rest are holes.
+// The boundary of the polygon is made up of all its rings.
+func (polygon Polygon) Locate(point Point) Location {
+	if point.IsEmpty() || len(polygon) == 0 {
+		return Exterior
+	}
+	switch locateInRing(polygon[0], point) {
+	case Exterior:
+		return Exterior
+	case Boundary:
+		return Boundary
+	}
+	for _, hole := range polygon[1:] {
+		switch locateInRing(hole, point) {
+		case Interior:
+			return Exterior
+		case Boundary:
+			return Boundary
 		}
 	}
-	return (intersections % 2) == 1
+	return Interior
+}
+
+// IsEmpty returns true if the polygon has no coordinates.
+func (polygon Polygon) IsEmpty() bool {
+	return isEmpty(&polygon)
+}
+
+// Bounds returns the envelope of the polygon.
+func (polygon Polygon) Bounds() Envelope {
+	return bounds(&polygon)
 }
 
 // MarshalJSON returns the GeoJSON representation of the polygon.
 func (polygon Polygon) MarshalJSON() ([]byte, error) {
+	return polygon.geoJSON(LayoutOf(&polygon))
+}
+
+// geoJSON returns the GeoJSON representation of the polygon using the given layout.
+func (polygon Polygon) geoJSON(layout Layout) ([]byte, error) {
 	s := polygonJSONPrefix
 	for i, poly := range polygon {
 		if i == 0 {
@@ -64,12 +90,18 @@ func (polygon Polygon) MarshalJSON() ([]byte, error) {
 		} else {
 			s = append(s, ',', '[')
 		}
-		s = append(s, pointsMarshalJSON(poly, "", "")...)
+		s = append(s, pointsMarshalJSON(poly, "", "", layout)...)
 		s = append(s, ']')
 	}
 	return append(s, polygonJSONSuffix...), nil
 }
 
+// MarshalWKB returns the Well Known Binary representation of the polygon
+// using the given byte order.
+func (polygon Polygon) MarshalWKB(order binary.ByteOrder) ([]byte, error) {
+	return marshalWKB(&polygon, order)
+}
+
 // Scan scans a polygon from Well Known Text.
 func (polygon *Polygon) Scan(src interface{}) error {
 	return scan(polygon, src)
@@ -77,45 +109,33 @@ func (polygon *Polygon) Scan(src interface{}) error {
 
 // scan scans a polygon from a Well Known Text string.
 func (polygon *Polygon) scan(s string) error {
-	if i := strings.Index(s, polygonWKTPrefix); i != 0 {
-		return fmt.Errorf("malformed polygon %s", s)
-	}
-	l := len(s)
-
-	if s[l-len(polygonWKTSuffix):] != polygonWKTSuffix {
-		return fmt.Errorf("malformed polygon %s", s)
-	}
-	s = s[len(polygonWKTPrefix) : l-len(polygonWKTSuffix)]
-
-	// empty the polygon
-	*polygon = Polygon{}
-
-	// get the coordinates
-	polygons := strings.Split(s, "),(")
-	for _, ss := range polygons {
-		points, err := pointsScan(ss)
-		if err != nil {
-			return err
-		}
-		*polygon = append(*polygon, points)
+	g, _, err := parseWKT(s, polygonWKTTag)
+	if err != nil {
+		return err
 	}
+	*polygon = *withoutLayout(g).(*Polygon)
 	return nil
 }
 
 // String converts the polygon to a string.
 func (polygon Polygon) String() string {
+	return polygon.wkt(LayoutOf(&polygon))
+}
+
+// wkt returns the Well Known Text representation of the polygon using the given layout.
+func (polygon Polygon) wkt(layout Layout) string {
 	if len(polygon) == 0 {
 		return "POLYGON EMPTY"
 	}
-	s := polygonWKTPrefix
+	s := wktPrefix(polygonWKTTag, layout) + "("
 	for i, points := range polygon {
 		if i == 0 {
-			s += pointsString(points)
+			s += pointsString(points, layout)
 		} else {
-			s += "," + pointsString(points)
+			s += "," + pointsString(points, layout)
 		}
 	}
-	return s + polygonWKTSuffix
+	return s + ")"
 }
 
 // UnmarshalJSON unmarshals the polygon from GeoJSON.
@@ -129,17 +149,28 @@ func (polygon *Polygon) UnmarshalJSON(data []byte) error {
 		return fmt.Errorf("expected type %s, got %s", expected, got)
 	}
 
-	p := [][][3]float64{}
+	p := [][][4]float64{}
 
 	if err := json.Unmarshal(g.Coordinates, &p); err != nil {
 		return err
 	}
 
 	*polygon = Polygon(p)
+	polygon.Transform(layoutMarker(positionLayout(g.Coordinates)))
 
 	return nil
 }
 
+// UnmarshalWKB unmarshals the polygon from Well Known Binary.
+func (polygon *Polygon) UnmarshalWKB(data []byte) error {
+	g, _, err := unmarshalWKB(data, wkbPolygon)
+	if err != nil {
+		return err
+	}
+	*polygon = *withoutLayout(g).(*Polygon)
+	return nil
+}
+
 // Value converts a point to Well Known Text.
 func (polygon Polygon) Value() (driver.Value, error) {
 	return polygon.String(), nil
@@ -147,9 +178,9 @@ func (polygon Polygon) Value() (driver.Value, error) {
 
 // Transform transforms the geometry point by point.
 func (polygon *Polygon) Transform(t Transformer) {
-	np := make([][][3]float64, len(*polygon))
+	np := make([][][4]float64, len(*polygon))
 	for i, line := range *polygon {
-		nl := make([][3]float64, len(line))
+		nl := make([][4]float64, len(line))
 		for j, point := range line {
 			nl[j] = t.Transform(point)
 		}
@@ -64,6 +64,29 @@ func TestPolygonEqual(t *testing.T) {
 }
 
 func TestPolygonContains(t *testing.T) {
+	// Contains (boundary)
+	cases{
+		G: &Polygon{
+			{
+				{0, 0},
+				{2, 0},
+				{2, 2},
+				{0, 2},
+				{0, 0},
+			},
+		},
+		Inside: []Point{
+			{0, 0},
+			{1, 0},
+			{2, 1},
+			{2, 2},
+		},
+		Outside: []Point{
+			{2.5, 2},
+			{-1, 0},
+		},
+	}.test(t)
+
 	// Contains (square)
 	cases{
 		// Square
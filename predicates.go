@@ -0,0 +1,252 @@
+package geo
+
+// The geometric predicates in this file are adaptive: they are evaluated
+// with float64 arithmetic first, and only if the result is too close to zero
+// to trust its sign are they evaluated again exactly, using the expansion
+// arithmetic described by Jonathan Richard Shewchuk in "Adaptive Precision
+// Floating-Point Arithmetic and Fast Robust Geometric Predicates" (1997).
+// See https://www.cs.cmu.edu/~quake/robust.html
+
+const (
+	// epsilon is half of the machine epsilon for float64, i.e. 2^-53.
+	epsilon = 1.0 / (1 << 53)
+
+	// splitter is used to split a float64 into two halves, i.e. 2^27 + 1.
+	splitter = 1<<27 + 1
+
+	// Error bound for the float64 evaluation of orient2d.
+	// Products are converted to float64 explicitly wherever a fused
+	// multiply-add would invalidate this bound, see the Go spec.
+	orient2dErrorBound = (3 + 16*epsilon) * epsilon
+)
+
+// orient2d returns a positive value if a, b and c are in counterclockwise order,
+// a negative value if they are in clockwise order, and zero if they are collinear.
+// The value approximates twice the signed area of the triangle abc,
+// and its sign is always correct.
+func orient2d(a, b, c [4]float64) float64 {
+	var (
+		left  = float64((a[0] - c[0]) * (b[1] - c[1]))
+		right = float64((a[1] - c[1]) * (b[0] - c[0]))
+		det   = left - right
+		sum   float64
+	)
+	switch {
+	case left > 0:
+		if right <= 0 {
+			return det
+		}
+		sum = left + right
+	case left < 0:
+		if right >= 0 {
+			return det
+		}
+		sum = -left - right
+	default:
+		return det
+	}
+	if bound := orient2dErrorBound * sum; det >= bound || -det >= bound {
+		return det
+	}
+	return orient2dExact(a, b, c)
+}
+
+// orient2dExact evaluates orient2d exactly.
+func orient2dExact(a, b, c [4]float64) float64 {
+	var (
+		acx = diffExpansion(a[0], c[0])
+		acy = diffExpansion(a[1], c[1])
+		bcx = diffExpansion(b[0], c[0])
+		bcy = diffExpansion(b[1], c[1])
+	)
+	return acx.mul(bcy).sub(acy.mul(bcx)).estimate()
+}
+
+// onSegment returns true if p lies on the closed line segment from a to b.
+func onSegment(a, b, p [4]float64) bool {
+	if p[0] < a[0] && p[0] < b[0] || p[0] > a[0] && p[0] > b[0] {
+		return false
+	}
+	if p[1] < a[1] && p[1] < b[1] || p[1] > a[1] && p[1] > b[1] {
+		return false
+	}
+	return orient2d(a, b, p) == 0
+}
+
+// segmentsIntersect returns true if the closed line segments ab and cd
+// have at least one point in common.
+func segmentsIntersect(a, b, c, d [4]float64) bool {
+	var (
+		abc = orient2d(a, b, c)
+		abd = orient2d(a, b, d)
+		cda = orient2d(c, d, a)
+		cdb = orient2d(c, d, b)
+	)
+	if (abc > 0 && abd < 0 || abc < 0 && abd > 0) && (cda > 0 && cdb < 0 || cda < 0 && cdb > 0) {
+		return true
+	}
+	return abc == 0 && onSegment(a, b, c) ||
+		abd == 0 && onSegment(a, b, d) ||
+		cda == 0 && onSegment(c, d, a) ||
+		cdb == 0 && onSegment(c, d, b)
+}
+
+// segmentIntersection returns the points that the closed line segments ab and cd
+// have in common: none, a single point, or the two ends of the segment
+// where collinear segments overlap.
+// The point where two segments cross is rounded to the nearest float64 values,
+// but endpoints that lie on the other segment are returned exactly.
+func segmentIntersection(a, b, c, d [4]float64) [][4]float64 {
+	if !segmentsIntersect(a, b, c, d) {
+		return nil
+	}
+	var (
+		abc = orient2d(a, b, c)
+		abd = orient2d(a, b, d)
+	)
+	if abc == 0 && abd == 0 {
+		// Collinear, so the overlap is made of the endpoints that lie on the other segment.
+		var overlap [][4]float64
+		for _, p := range [][4]float64{a, b, c, d} {
+			on := onSegment(a, b, p) && onSegment(c, d, p)
+			if on && !containsXY(overlap, p) {
+				overlap = append(overlap, p)
+			}
+		}
+		return overlap
+	}
+	// An endpoint that lies on the other segment is the intersection.
+	switch {
+	case abc == 0:
+		return [][4]float64{c}
+	case abd == 0:
+		return [][4]float64{d}
+	}
+	var (
+		cda = orient2d(c, d, a)
+		cdb = orient2d(c, d, b)
+	)
+	switch {
+	case cda == 0:
+		return [][4]float64{a}
+	case cdb == 0:
+		return [][4]float64{b}
+	}
+	t := cda / (cda - cdb)
+	return [][4]float64{{a[0] + t*(b[0]-a[0]), a[1] + t*(b[1]-a[1])}}
+}
+
+// containsXY returns true if any of the points has the same X and Y as p.
+func containsXY(points [][4]float64, p [4]float64) bool {
+	for _, q := range points {
+		if q[0] == p[0] && q[1] == p[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// expansion is an exact sum of float64 components that do not overlap,
+// in increasing order of magnitude, so the last component has the sign
+// of the sum and approximates it.
+type expansion []float64
+
+// diffExpansion returns a - b as an expansion.
+func diffExpansion(a, b float64) expansion {
+	x, y := twoDiff(a, b)
+	return expansion{y, x}
+}
+
+// add returns e + f.
+func (e expansion) add(f expansion) expansion {
+	for _, component := range f {
+		e = e.grow(component)
+	}
+	return e
+}
+
+// sub returns e - f.
+func (e expansion) sub(f expansion) expansion {
+	for _, component := range f {
+		e = e.grow(-component)
+	}
+	return e
+}
+
+// mul returns e * f.
+func (e expansion) mul(f expansion) expansion {
+	product := expansion{}
+	for _, component := range f {
+		product = product.add(e.scale(component))
+	}
+	return product
+}
+
+// scale returns e * b.
+func (e expansion) scale(b float64) expansion {
+	product := expansion{}
+	for _, component := range e {
+		x, y := twoProduct(component, b)
+		product = product.grow(y).grow(x)
+	}
+	return product
+}
+
+// grow returns e + b, leaving out components that are zero.
+func (e expansion) grow(b float64) expansion {
+	var (
+		sum = make(expansion, 0, len(e)+1)
+		q   = b
+		h   float64
+	)
+	for _, component := range e {
+		if q, h = twoSum(q, component); h != 0 {
+			sum = append(sum, h)
+		}
+	}
+	if q != 0 || len(sum) == 0 {
+		sum = append(sum, q)
+	}
+	return sum
+}
+
+// estimate returns the most significant component of the expansion,
+// which has the same sign as its exact value.
+func (e expansion) estimate() float64 {
+	if len(e) == 0 {
+		return 0
+	}
+	return e[len(e)-1]
+}
+
+// twoSum returns a + b as x + y, where x is the float64 sum and y is its roundoff error.
+func twoSum(a, b float64) (x, y float64) {
+	x = a + b
+	bVirtual := x - a
+	aVirtual := x - bVirtual
+	return x, (a - aVirtual) + (b - bVirtual)
+}
+
+// twoDiff returns a - b as x + y, where x is the float64 difference and y is its roundoff error.
+func twoDiff(a, b float64) (x, y float64) {
+	x = a - b
+	bVirtual := a - x
+	aVirtual := x + bVirtual
+	return x, (a - aVirtual) + (bVirtual - b)
+}
+
+// twoProduct returns a * b as x + y, where x is the float64 product and y is its roundoff error.
+func twoProduct(a, b float64) (x, y float64) {
+	x = float64(a * b)
+	aHi, aLo := split(a)
+	bHi, bLo := split(b)
+	err := x - float64(aHi*bHi) - float64(aLo*bHi) - float64(aHi*bLo)
+	return x, float64(aLo*bLo) - err
+}
+
+// split splits a into two halves with at most 26 significant bits each.
+func split(a float64) (hi, lo float64) {
+	c := float64(splitter * a)
+	hi = c - (c - a)
+	return hi, a - hi
+}
@@ -0,0 +1,182 @@
+package geo
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// exactOrient2d returns the sign of orient2d computed with rational arithmetic.
+func exactOrient2d(a, b, c [4]float64) int {
+	var (
+		acx = ratDiff(a[0], c[0])
+		acy = ratDiff(a[1], c[1])
+		bcx = ratDiff(b[0], c[0])
+		bcy = ratDiff(b[1], c[1])
+	)
+	left := new(big.Rat).Mul(acx, bcy)
+	right := new(big.Rat).Mul(acy, bcx)
+	return left.Sub(left, right).Sign()
+}
+
+// ratDiff returns a - b exactly.
+func ratDiff(a, b float64) *big.Rat {
+	x := new(big.Rat).SetFloat64(a)
+	return x.Sub(x, new(big.Rat).SetFloat64(b))
+}
+
+// sign returns the sign of x.
+func sign(x float64) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
+
+func TestOrient2d(t *testing.T) {
+	for i, testcase := range []struct {
+		A, B, C  [4]float64
+		Expected int
+	}{
+		{A: [4]float64{0, 0}, B: [4]float64{1, 0}, C: [4]float64{0, 1}, Expected: 1},
+		{A: [4]float64{0, 0}, B: [4]float64{0, 1}, C: [4]float64{1, 0}, Expected: -1},
+		{A: [4]float64{0, 0}, B: [4]float64{1, 1}, C: [4]float64{2, 2}, Expected: 0},
+		{A: [4]float64{0, 0}, B: [4]float64{0, 1}, C: [4]float64{0, 7}, Expected: 0},
+		{A: [4]float64{0.1, 0.1}, B: [4]float64{0.2, 0.2}, C: [4]float64{0.3, 0.3}, Expected: exactOrient2d([4]float64{0.1, 0.1}, [4]float64{0.2, 0.2}, [4]float64{0.3, 0.3})},
+	} {
+		if expected, got := testcase.Expected, sign(orient2d(testcase.A, testcase.B, testcase.C)); expected != got {
+			t.Fatalf("(case %d) expected %d, got %d", i, expected, got)
+		}
+	}
+
+	// Points on a grid of the smallest possible steps near the line y = x,
+	// where the float64 determinant is often wrong.
+	// See Kettner et al, "Classroom examples of robustness problems in geometric computations".
+	var (
+		q = [4]float64{12, 12}
+		r = [4]float64{24, 24}
+	)
+	for i := 0; i < 64; i++ {
+		for j := 0; j < 64; j++ {
+			p := [4]float64{0.5 + float64(i)*math.Pow(2, -53), 0.5 + float64(j)*math.Pow(2, -53)}
+			if expected, got := exactOrient2d(p, q, r), sign(orient2d(p, q, r)); expected != got {
+				t.Fatalf("(%d, %d) expected %d, got %d", i, j, expected, got)
+			}
+			if expected, got := exactOrient2d(p, q, r), sign(orient2dExact(p, q, r)); expected != got {
+				t.Fatalf("(%d, %d) expected %d, got %d", i, j, expected, got)
+			}
+		}
+	}
+
+	// Nearly collinear points with very different magnitudes.
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		var (
+			a = [4]float64{rng.Float64() * 1e6, rng.Float64() * 1e6}
+			b = [4]float64{rng.Float64() * 1e-6, rng.Float64() * 1e-6}
+			f = rng.Float64()
+			c = [4]float64{a[0] + f*(b[0]-a[0]), a[1] + f*(b[1]-a[1])}
+		)
+		if expected, got := exactOrient2d(a, b, c), sign(orient2d(a, b, c)); expected != got {
+			t.Fatalf("(case %d) expected %d, got %d", i, expected, got)
+		}
+	}
+}
+
+func TestSegmentIntersection(t *testing.T) {
+	for i, testcase := range []struct {
+		A, B, C, D [4]float64
+		Expected   [][4]float64
+	}{
+		{
+			// Crossing.
+			A: [4]float64{0, 0}, B: [4]float64{2, 2}, C: [4]float64{0, 2}, D: [4]float64{2, 0},
+			Expected: [][4]float64{{1, 1}},
+		},
+		{
+			// Disjoint.
+			A: [4]float64{0, 0}, B: [4]float64{1, 1}, C: [4]float64{0, 2}, D: [4]float64{0.9, 1.1},
+		},
+		{
+			// Touching at an endpoint.
+			A: [4]float64{0, 0}, B: [4]float64{2, 0}, C: [4]float64{1, 0}, D: [4]float64{1, 5},
+			Expected: [][4]float64{{1, 0}},
+		},
+		{
+			A: [4]float64{1, 0}, B: [4]float64{1, 5}, C: [4]float64{0, 0}, D: [4]float64{2, 0},
+			Expected: [][4]float64{{1, 0}},
+		},
+		{
+			// Sharing an endpoint.
+			A: [4]float64{0, 0}, B: [4]float64{1, 1}, C: [4]float64{1, 1}, D: [4]float64{2, 0},
+			Expected: [][4]float64{{1, 1}},
+		},
+		{
+			// Collinear and overlapping.
+			A: [4]float64{0, 0}, B: [4]float64{4, 0}, C: [4]float64{2, 0}, D: [4]float64{6, 0},
+			Expected: [][4]float64{{4, 0}, {2, 0}},
+		},
+		{
+			// Collinear and disjoint.
+			A: [4]float64{0, 0}, B: [4]float64{1, 1}, C: [4]float64{2, 2}, D: [4]float64{3, 3},
+		},
+		{
+			// Vertical and collinear.
+			A: [4]float64{0, 0}, B: [4]float64{0, 4}, C: [4]float64{0, 4}, D: [4]float64{0, 9},
+			Expected: [][4]float64{{0, 4}},
+		},
+	} {
+		got := segmentIntersection(testcase.A, testcase.B, testcase.C, testcase.D)
+		if expected := testcase.Expected; !pointsEqual(expected, got) {
+			t.Fatalf("(case %d) expected %v, got %v", i, expected, got)
+		}
+		if expected, got := len(testcase.Expected) > 0, segmentsIntersect(testcase.A, testcase.B, testcase.C, testcase.D); expected != got {
+			t.Fatalf("(case %d) expected %t, got %t", i, expected, got)
+		}
+	}
+}
+
+func TestOnSegment(t *testing.T) {
+	for i, testcase := range []struct {
+		A, B, P  [4]float64
+		Expected bool
+	}{
+		{A: [4]float64{0, 0}, B: [4]float64{0, 2}, P: [4]float64{0, 1}, Expected: true},
+		{A: [4]float64{0, 0}, B: [4]float64{0, 2}, P: [4]float64{0, 3}},
+		{A: [4]float64{0, 0}, B: [4]float64{2, 0}, P: [4]float64{1, 0}, Expected: true},
+		{A: [4]float64{0, 0}, B: [4]float64{3, 3}, P: [4]float64{2, 2}, Expected: true},
+		{A: [4]float64{0, 0}, B: [4]float64{3, 3}, P: [4]float64{2, 2.0000000000000004}},
+		{A: [4]float64{0, 0}, B: [4]float64{0.3, 0.9}, P: [4]float64{0.1, 0.3}, Expected: exactOrient2d([4]float64{0, 0}, [4]float64{0.3, 0.9}, [4]float64{0.1, 0.3}) == 0},
+	} {
+		if expected, got := testcase.Expected, onSegment(testcase.A, testcase.B, testcase.P); expected != got {
+			t.Fatalf("(case %d) expected %t, got %t", i, expected, got)
+		}
+	}
+}
+
+func TestRayhIntersects(t *testing.T) {
+	for i, testcase := range []struct {
+		Point    Point
+		A, B     Point
+		Expected bool
+	}{
+		{Point: Point{0, 1}, A: Point{2, 0}, B: Point{2, 2}, Expected: true},
+		{Point: Point{3, 1}, A: Point{2, 0}, B: Point{2, 2}},
+		{Point: Point{2, 1}, A: Point{2, 0}, B: Point{2, 2}, Expected: true},
+		{Point: Point{0, 0}, A: Point{2, 0}, B: Point{2, 2}, Expected: true},
+		{Point: Point{0, 2}, A: Point{2, 0}, B: Point{2, 2}},
+		{Point: Point{0, 0}, A: Point{1, 0}, B: Point{2, 0}},
+		{Point: Point{3, 1}, A: Point{0, 0}, B: Point{4, 2}},
+		{Point: Point{1, 1}, A: Point{0, 2}, B: Point{4, 0}, Expected: true},
+		{Point: Point{2, 1}, A: Point{4, 2}, B: Point{0, 0}, Expected: true},
+		{Point: Point{0.1, 0.3}, A: Point{0, 0}, B: Point{0.3, 0.9}, Expected: exactOrient2d([4]float64{0, 0}, [4]float64{0.3, 0.9}, [4]float64{0.1, 0.3}) >= 0},
+	} {
+		if expected, got := testcase.Expected, testcase.Point.RayhIntersects(testcase.A, testcase.B); expected != got {
+			t.Fatalf("(case %d) expected %t, got %t", i, expected, got)
+		}
+	}
+}
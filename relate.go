@@ -0,0 +1,601 @@
+package geo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// IntersectionMatrix is the dimensionally extended nine-intersection matrix
+// (DE-9IM) of two geometries.
+// It is indexed by the location in the first geometry and the location
+// in the second, e.g. m[Interior][Boundary] is the dimension of the intersection
+// of the interior of the first geometry with the boundary of the second.
+// Each entry is 0 for points, 1 for lines, 2 for areas,
+// or -1 if the two locations do not intersect.
+type IntersectionMatrix [3][3]int
+
+// Relate returns the intersection matrix of two geometries.
+// Like the spatial predicates it is Cartesian, it treats collections
+// as the union of their members and a Circle as a polygon with 64 vertices
+// on its circumference.
+func Relate(a, b Geometry) IntersectionMatrix {
+	return relateGeometries(newRelateGeometry(a), newRelateGeometry(b))
+}
+
+// RelatePattern returns true if the intersection matrix of two geometries
+// matches a pattern, see IntersectionMatrix.Matches.
+func RelatePattern(a, b Geometry, pattern string) (bool, error) {
+	return Relate(a, b).Matches(pattern)
+}
+
+// matrixOrder is the order of the entries of an intersection matrix in its string form.
+var matrixOrder = [9][2]Location{
+	{Interior, Interior}, {Interior, Boundary}, {Interior, Exterior},
+	{Boundary, Interior}, {Boundary, Boundary}, {Boundary, Exterior},
+	{Exterior, Interior}, {Exterior, Boundary}, {Exterior, Exterior},
+}
+
+// String returns the matrix as nine characters, row by row,
+// with F for entries that are empty, e.g. "212101212".
+func (m IntersectionMatrix) String() string {
+	var b strings.Builder
+	for _, cell := range matrixOrder {
+		if dim := m[cell[0]][cell[1]]; dim < 0 {
+			b.WriteByte('F')
+		} else {
+			b.WriteByte(byte('0' + dim))
+		}
+	}
+	return b.String()
+}
+
+// Matches returns true if the matrix matches a pattern of nine characters,
+// given row by row like the string form of the matrix.
+// T matches any non-empty entry, F an empty one, 0, 1 and 2 that dimension,
+// and * any entry. T and F may also be given in lower case.
+// An error is returned if the pattern is not valid.
+func (m IntersectionMatrix) Matches(pattern string) (bool, error) {
+	if len(pattern) != len(matrixOrder) {
+		return false, fmt.Errorf("DE-9IM pattern must have 9 characters, got %q", pattern)
+	}
+	matches := true
+	for i, cell := range matrixOrder {
+		dim := m[cell[0]][cell[1]]
+		switch c := pattern[i]; c {
+		case 'T', 't':
+			matches = matches && dim >= 0
+		case 'F', 'f':
+			matches = matches && dim < 0
+		case '0', '1', '2':
+			matches = matches && dim == int(c-'0')
+		case '*':
+		default:
+			return false, fmt.Errorf("DE-9IM pattern must be made of T, F, 0, 1, 2 and *, got %q", pattern)
+		}
+	}
+	return matches, nil
+}
+
+// get returns the dimension of the intersection of location la of the first
+// geometry and location lb of the second.
+func (m IntersectionMatrix) get(la, lb Location) int {
+	return m[la][lb]
+}
+
+// set raises the dimension of the intersection of la and lb to dim.
+func (m *IntersectionMatrix) set(la, lb Location, dim int) {
+	if dim > m[la][lb] {
+		m[la][lb] = dim
+	}
+}
+
+// relateGeometry is a geometry broken into the parts that its intersection
+// matrix with another geometry is computed from.
+type relateGeometry struct {
+	points [][4]float64
+
+	// lines holds the lines of each Line and MultiLine,
+	// whose boundaries follow the "mod 2" rule separately.
+	lines [][][][4]float64
+
+	// polygons holds closed copies of the polygons, wound so that
+	// their interiors are on the left of every ring.
+	polygons [][][][4]float64
+}
+
+// newRelateGeometry breaks a geometry into points, lines and polygons.
+// Lines without any length are treated as points,
+// and a Circle as a polygon with 64 vertices on its circumference.
+func newRelateGeometry(g Geometry) relateGeometry {
+	var rg relateGeometry
+	visitMeasurable(g, func(g Geometry) {
+		switch v := g.(type) {
+		case *Point:
+			rg.addPoint(*v)
+		case *MultiPoint:
+			for _, p := range *v {
+				rg.addPoint(p)
+			}
+		case *Line:
+			rg.addLines([][][4]float64{*v})
+		case *MultiLine:
+			rg.addLines(*v)
+		case *Polygon:
+			rg.addPolygon(*v)
+		case *MultiPolygon:
+			for _, poly := range *v {
+				rg.addPolygon(poly)
+			}
+		case *Circle:
+			if !v.IsEmpty() {
+				rg.addPolygon([][][4]float64{circleRing(*v)})
+			}
+		}
+	})
+	return rg
+}
+
+// addPoint adds a point, unless it is empty.
+func (rg *relateGeometry) addPoint(p Point) {
+	if !p.IsEmpty() {
+		rg.points = append(rg.points, p)
+	}
+}
+
+// addLines adds the lines of a Line or MultiLine.
+func (rg *relateGeometry) addLines(lines [][][4]float64) {
+	var group [][][4]float64
+	for _, line := range lines {
+		if len(ringSegments(line, 0)) > 0 {
+			group = append(group, line)
+		} else if len(line) > 0 {
+			rg.addPoint(line[0])
+		}
+	}
+	if len(group) > 0 {
+		rg.lines = append(rg.lines, group)
+	}
+}
+
+// addPolygon adds a copy of a polygon with its rings closed and rewound.
+func (rg *relateGeometry) addPolygon(poly [][][4]float64) {
+	var rings [][][4]float64
+	for _, ring := range poly {
+		if len(ring) == 0 {
+			continue
+		}
+		closed := append([][4]float64{}, ring...)
+		if first, last := ring[0], ring[len(ring)-1]; first[0] != last[0] || first[1] != last[1] {
+			closed = append(closed, first)
+		}
+		rings = append(rings, closed)
+	}
+	if len(rings) == 0 {
+		return
+	}
+	rewindPolygon(rings)
+	rg.polygons = append(rg.polygons, rings)
+}
+
+// dimension returns the highest dimension of the parts of the geometry,
+// or -1 if it is empty.
+func (rg relateGeometry) dimension() int {
+	switch {
+	case len(rg.polygons) > 0:
+		return 2
+	case len(rg.lines) > 0:
+		return 1
+	case len(rg.points) > 0:
+		return 0
+	}
+	return -1
+}
+
+// relateTag identifies the line or polygon of one of the two geometries
+// that a segment comes from.
+type relateTag struct {
+	geometry int
+	line     int
+	polygon  int
+}
+
+// relateNode is a point where segments meet or end, or a point of either geometry.
+type relateNode struct {
+	p     [4]float64
+	tags  []int
+	edges []*relateEdge
+}
+
+// relateEdge is a piece of a segment between two nodes, with its ends in XY order.
+type relateEdge struct {
+	a, b [4]float64
+	tags []int
+
+	// forward records, for each tag, whether the segment it comes from
+	// runs from a to b.
+	forward []bool
+}
+
+// relateGraph is two geometries with their segments split wherever they meet,
+// so that the location of each piece relative to either geometry is the same
+// along its length, and so are the locations of the areas on either side of it.
+type relateGraph struct {
+	geoms  [2]relateGeometry
+	tags   []relateTag
+	nodes  []*relateNode
+	nodeAt map[[2]float64]*relateNode
+	edges  []*relateEdge
+}
+
+// newRelateGraph nodes the segments of two geometries that have been broken into parts.
+func newRelateGraph(ga, gb relateGeometry) *relateGraph {
+	var (
+		graph = &relateGraph{geoms: [2]relateGeometry{ga, gb}}
+		segs  []segment
+	)
+	for i, g := range graph.geoms {
+		for j, group := range g.lines {
+			for _, line := range group {
+				segs = append(segs, ringSegments(line, len(graph.tags))...)
+			}
+			graph.tags = append(graph.tags, relateTag{geometry: i, line: j, polygon: -1})
+		}
+		for j, poly := range g.polygons {
+			for _, ring := range poly {
+				segs = append(segs, ringSegments(ring, len(graph.tags))...)
+			}
+			graph.tags = append(graph.tags, relateTag{geometry: i, line: -1, polygon: j})
+		}
+	}
+	for i := range segs {
+		segs[i].seq = i
+	}
+
+	graph.nodeAt = map[[2]float64]*relateNode{}
+	cuts := make([][][4]float64, len(segs))
+	addNode := func(p [4]float64, tag int) {
+		key := [2]float64{p[0], p[1]}
+		n, ok := graph.nodeAt[key]
+		if !ok {
+			n = &relateNode{p: p}
+			graph.nodeAt[key] = n
+			graph.nodes = append(graph.nodes, n)
+		}
+		if tag >= 0 && !containsInt(n.tags, tag) {
+			n.tags = append(n.tags, tag)
+		}
+	}
+	for _, g := range graph.geoms {
+		for _, p := range g.points {
+			addNode(p, -1)
+		}
+	}
+	for _, s := range segs {
+		addNode(s.a, s.ring)
+		addNode(s.b, s.ring)
+	}
+	sweepSegments(segs, func(s, t segment) bool {
+		for _, p := range segmentIntersection(s.a, s.b, t.a, t.b) {
+			addNode(p, s.ring)
+			addNode(p, t.ring)
+			if !isEndpoint(s, p) {
+				cuts[s.seq] = append(cuts[s.seq], p)
+			}
+			if !isEndpoint(t, p) {
+				cuts[t.seq] = append(cuts[t.seq], p)
+			}
+		}
+		return true
+	})
+	graph.edges = splitEdges(segs, cuts)
+	for _, e := range graph.edges {
+		for _, p := range [][4]float64{e.a, e.b} {
+			n := graph.nodeAt[[2]float64{p[0], p[1]}]
+			n.edges = append(n.edges, e)
+		}
+	}
+	return graph
+}
+
+// relateGeometries returns the intersection matrix of two geometries that
+// have been broken into parts.
+// The matrix is made up of the locations of the nodes of their graph,
+// the edges between the nodes, and the areas next to the edges.
+func relateGeometries(ga, gb relateGeometry) IntersectionMatrix {
+	graph := newRelateGraph(ga, gb)
+
+	var m IntersectionMatrix
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] = -1
+		}
+	}
+	// Geometries are bounded, so their exteriors always share an area.
+	m.set(Exterior, Exterior, 2)
+
+	for _, n := range graph.nodes {
+		m.set(graph.locateNode(0, n), graph.locateNode(1, n), 0)
+	}
+	for _, e := range graph.edges {
+		var (
+			la, leftA, rightA = graph.locateEdge(0, e)
+			lb, leftB, rightB = graph.locateEdge(1, e)
+		)
+		m.set(la, lb, 1)
+		m.set(faceLocation(leftA), faceLocation(leftB), 2)
+		m.set(faceLocation(rightA), faceLocation(rightB), 2)
+	}
+	return m
+}
+
+// splitEdges splits segments at their cuts, and merges the pieces that
+// more than one segment has in common.
+func splitEdges(segs []segment, cuts [][][4]float64) []*relateEdge {
+	var (
+		edges = map[[4]float64]*relateEdge{}
+		order []*relateEdge
+	)
+	for i, s := range segs {
+		points := cuts[i]
+		sort.Slice(points, func(j, k int) bool {
+			return squaredDistance(s.a, points[j]) < squaredDistance(s.a, points[k])
+		})
+		path := [][4]float64{s.a}
+		for _, p := range append(points, s.b) {
+			if last := path[len(path)-1]; last[0] != p[0] || last[1] != p[1] {
+				path = append(path, p)
+			}
+		}
+
+		for j := 1; j < len(path); j++ {
+			a, b, forward := path[j-1], path[j], true
+			if b[0] < a[0] || b[0] == a[0] && b[1] < a[1] {
+				a, b, forward = b, a, false
+			}
+			key := [4]float64{a[0], a[1], b[0], b[1]}
+			e, ok := edges[key]
+			if !ok {
+				e = &relateEdge{a: a, b: b}
+				edges[key] = e
+				order = append(order, e)
+			}
+			e.tags = append(e.tags, s.ring)
+			e.forward = append(e.forward, forward)
+		}
+	}
+	return order
+}
+
+// locateNode returns the location of a node relative to one of the geometries.
+// Nodes where segments cross are not exactly on them,
+// so the segments that a node is on are taken from its tags.
+// A node on the boundaries of polygons is inside their union if the union
+// is on both sides of every edge of those boundaries that meets at the node,
+// e.g. on the edge that two polygons of a collection have in common.
+func (graph *relateGraph) locateNode(id int, n *relateNode) Location {
+	var (
+		rg         = graph.geoms[id]
+		loc        = Exterior
+		onBoundary = false
+	)
+	if containsXY(rg.points, n.p) {
+		loc = Interior
+	}
+	for i, group := range rg.lines {
+		l := MultiLine(group).Locate(n.p)
+		if l == Exterior && hasTag(n.tags, graph.tags, relateTag{geometry: id, line: i, polygon: -1}) {
+			l = Interior
+		}
+		loc = unionLocation(loc, l)
+	}
+	for i, poly := range rg.polygons {
+		if hasTag(n.tags, graph.tags, relateTag{geometry: id, line: -1, polygon: i}) {
+			onBoundary = true
+			continue
+		}
+		// Points do not split the edges that they are on, so they are not tagged.
+		switch l := Polygon(poly).Locate(n.p); l {
+		case Boundary:
+			onBoundary = true
+		default:
+			loc = unionLocation(loc, l)
+		}
+	}
+	if onBoundary && loc != Interior {
+		if graph.surrounded(id, n) {
+			return Interior
+		}
+		return Boundary
+	}
+	return loc
+}
+
+// surrounded returns true if the polygons of one of the geometries are on both
+// sides of every edge at a node that is on the boundary of any of them.
+// A node that only a point of the other geometry is at has no edges,
+// so the edges that pass through it are used instead.
+func (graph *relateGraph) surrounded(id int, n *relateNode) bool {
+	edges := n.edges
+	if len(edges) == 0 {
+		for _, e := range graph.edges {
+			if onSegment(e.a, e.b, n.p) {
+				edges = append(edges, e)
+			}
+		}
+	}
+	found := false
+	for _, e := range edges {
+		onPolygon := false
+		for _, t := range e.tags {
+			if tag := graph.tags[t]; tag.geometry == id && tag.polygon >= 0 {
+				onPolygon = true
+			}
+		}
+		if !onPolygon {
+			continue
+		}
+		if _, left, right := graph.locateEdge(id, e); !left || !right {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// locateEdge returns the location of an edge relative to one of the geometries,
+// and whether the areas to its left and right are inside that geometry,
+// looking from its first end to its second.
+//
+// The polygons are treated as their union, so an edge that is on the boundary
+// of one polygon and inside or on the boundary of another, with the polygons
+// on both sides of it, is inside.
+func (graph *relateGraph) locateEdge(id int, e *relateEdge) (loc Location, left, right bool) {
+	rg := graph.geoms[id]
+	for i := range rg.lines {
+		if hasTag(e.tags, graph.tags, relateTag{geometry: id, line: i, polygon: -1}) {
+			loc = Interior
+		}
+	}
+	onBoundary := false
+	for i := range rg.polygons {
+		var (
+			tag = relateTag{geometry: id, line: -1, polygon: i}
+			on  = false
+		)
+		for j, t := range e.tags {
+			if graph.tags[t] != tag {
+				continue
+			}
+			// Polygons are wound with their interiors on the left of their rings.
+			on = true
+			if e.forward[j] {
+				left = true
+			} else {
+				right = true
+			}
+		}
+		if on {
+			onBoundary = true
+			continue
+		}
+		if graph.edgeInside(e, tag) {
+			left, right = true, true
+		}
+	}
+	switch {
+	case left && right:
+		loc = Interior
+	case onBoundary:
+		loc = unionLocation(loc, Boundary)
+	}
+	return loc, left, right
+}
+
+// edgeInside returns true if an edge that is not on the boundary of a polygon
+// is inside it.
+// The edge does not cross the boundary, so it is inside if either end is,
+// and if both ends are on the boundary it is inside if it leaves one of them
+// into the interior.
+// Only if that cannot be decided is its midpoint located, which may be rounded
+// to the wrong side of an edge that is nearly parallel to it.
+func (graph *relateGraph) edgeInside(e *relateEdge, tag relateTag) bool {
+	poly := Polygon(graph.geoms[tag.geometry].polygons[tag.polygon])
+	for _, ends := range [][2][4]float64{{e.a, e.b}, {e.b, e.a}} {
+		n := graph.nodeAt[[2]float64{ends[0][0], ends[0][1]}]
+		if !hasTag(n.tags, graph.tags, tag) {
+			if loc := poly.Locate(Point(n.p)); loc != Boundary {
+				return loc == Interior
+			}
+			continue
+		}
+		if inside, ok := graph.leavesInto(n, ends[1], tag); ok {
+			return inside
+		}
+	}
+	return poly.Locate(Point(lerp(e.a, e.b, 0.5))) == Interior
+}
+
+// leavesInto returns true if the direction from a node towards p is inside
+// the polygon whose boundary passes through the node.
+// It finds the first edge of the boundary counterclockwise from that direction,
+// and the direction is inside if the polygon is on the right of that edge.
+// Directions are compared exactly, however close they are.
+func (graph *relateGraph) leavesInto(n *relateNode, p [4]float64, tag relateTag) (inside, ok bool) {
+	var first [4]float64
+	for _, e := range n.edges {
+		for j, t := range e.tags {
+			if graph.tags[t] != tag {
+				continue
+			}
+			var (
+				other = e.b
+				right = !e.forward[j]
+			)
+			if other[0] == n.p[0] && other[1] == n.p[1] {
+				other, right = e.a, e.forward[j]
+			}
+			if !ok || ccwBefore(n.p, p, other, first) {
+				first, inside, ok = other, right, true
+			}
+		}
+	}
+	return inside, ok
+}
+
+// ccwBefore returns true if the direction from o towards q is reached before
+// the direction towards r when turning counterclockwise from the direction towards p.
+// None of the directions may be the same.
+func ccwBefore(o, p, q, r [4]float64) bool {
+	hq, hr := ccwHalf(o, p, q), ccwHalf(o, p, r)
+	if hq != hr {
+		return hq < hr
+	}
+	return orient2d(o, q, r) > 0
+}
+
+// ccwHalf returns 0 if the direction from o towards q is less than half a turn
+// counterclockwise from the direction towards p, and 1 otherwise.
+func ccwHalf(o, p, q [4]float64) int {
+	if orient2d(o, p, q) > 0 {
+		return 0
+	}
+	return 1
+}
+
+// unionLocation returns the location of a point relative to the union of
+// two geometries, given its locations relative to each of them.
+func unionLocation(l1, l2 Location) Location {
+	if l2 > l1 {
+		return l2
+	}
+	return l1
+}
+
+// faceLocation returns the location of an area that is either inside or outside a geometry.
+func faceLocation(inside bool) Location {
+	if inside {
+		return Interior
+	}
+	return Exterior
+}
+
+// hasTag returns true if any of the ids refers to the tag.
+func hasTag(ids []int, tags []relateTag, tag relateTag) bool {
+	for _, id := range ids {
+		if tags[id] == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// containsInt returns true if the slice contains x.
+func containsInt(s []int, x int) bool {
+	for _, y := range s {
+		if y == x {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRelate(t *testing.T) {
+	var (
+		square = &Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+		left   = &Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}}
+		right  = &Polygon{{{1, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 0}}}
+		both   = &Polygon{{{0, 0}, {2, 0}, {2, 1}, {0, 1}, {0, 0}}}
+
+		// Four squares that meet at the center of square.
+		quarters = &GeometryCollection{
+			&Polygon{{{0, 0}, {5, 0}, {5, 5}, {0, 5}, {0, 0}}},
+			&Polygon{{{5, 0}, {10, 0}, {10, 5}, {5, 5}, {5, 0}}},
+			&Polygon{{{5, 5}, {10, 5}, {10, 10}, {5, 10}, {5, 5}}},
+			&Polygon{{{0, 5}, {5, 5}, {5, 10}, {0, 10}, {0, 5}}},
+		}
+	)
+
+	for i, testcase := range []struct {
+		A, B     Geometry
+		Expected string
+	}{
+		{A: square, B: &Polygon{{{5, 5}, {15, 5}, {15, 15}, {5, 15}, {5, 5}}}, Expected: "212101212"},
+		{A: square, B: &Polygon{{{10, 0}, {20, 0}, {20, 10}, {10, 10}, {10, 0}}}, Expected: "FF2F11212"},
+		{A: square, B: &Polygon{{{10, 10}, {20, 10}, {20, 20}, {10, 10}}}, Expected: "FF2F01212"},
+		{A: square, B: &Polygon{{{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}}}, Expected: "212FF1FF2"},
+		{A: square, B: &Polygon{{{20, 0}, {30, 0}, {30, 10}, {20, 0}}}, Expected: "FF2FF1212"},
+		{A: square, B: &Polygon{{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}}}, Expected: "2FFF1FFF2"},
+		{A: &Point{5, 5}, B: square, Expected: "0FFFFF212"},
+		{A: &Point{0, 5}, B: square, Expected: "F0FFFF212"},
+		{A: &Point{0, 0}, B: &Line{{0, 0}, {10, 0}}, Expected: "F0FFFF102"},
+		{A: &Line{{0, 0}, {10, 10}}, B: &Line{{0, 10}, {10, 0}}, Expected: "0F1FF0102"},
+		{A: &Line{{0, 0}, {10, 0}}, B: &Line{{5, 0}, {15, 0}}, Expected: "1010F0102"},
+		{A: &Line{{2, 5}, {8, 5}}, B: square, Expected: "1FF0FF212"},
+		{A: &Line{{-5, 5}, {5, 5}}, B: square, Expected: "1010F0212"},
+		{A: &Line{{0, 0}, {10, 0}}, B: square, Expected: "F1FF0F212"},
+		{A: &GeometryCollection{}, B: square, Expected: "FFFFFF212"},
+		{A: &GeometryCollection{left, right}, B: both, Expected: "2FFF1FFF2"},
+		{A: both, B: &FeatureCollection{{Geometry: left}, {Geometry: right}}, Expected: "2FFF1FFF2"},
+		{A: &GeometryCollection{left, right}, B: &Line{{1, 0.25}, {1, 0.75}}, Expected: "102FF1FF2"},
+		{A: quarters, B: square, Expected: "2FFF1FFF2"},
+		{A: quarters, B: &Point{5, 5}, Expected: "0F2FF1FF2"},
+		{A: &Point{math.NaN(), math.NaN()}, B: &Line{}, Expected: "FFFFFFFF2"},
+	} {
+		if got := Relate(testcase.A, testcase.B).String(); testcase.Expected != got {
+			t.Fatalf("(case %d) expected %s, got %s", i, testcase.Expected, got)
+		}
+	}
+}
+
+func TestIntersectionMatrixMatches(t *testing.T) {
+	m := IntersectionMatrix{
+		Interior: {Interior: 2, Boundary: 1, Exterior: 2},
+		Boundary: {Interior: 1, Boundary: 0, Exterior: 1},
+		Exterior: {Interior: 2, Boundary: 1, Exterior: 2},
+	}
+	if expected, got := "212101212", m.String(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	for i, testcase := range []struct {
+		Pattern  string
+		Expected bool
+	}{
+		{Pattern: "212101212", Expected: true},
+		{Pattern: "*********", Expected: true},
+		{Pattern: "T*T***T**", Expected: true},
+		{Pattern: "t*t***t**", Expected: true},
+		{Pattern: "T*F**F***", Expected: false},
+		{Pattern: "2********", Expected: true},
+		{Pattern: "1********", Expected: false},
+		{Pattern: "****0****", Expected: true},
+		{Pattern: "****F****", Expected: false},
+	} {
+		got, err := m.Matches(testcase.Pattern)
+		if err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if expected := testcase.Expected; expected != got {
+			t.Fatalf("(case %d) expected %t, got %t", i, expected, got)
+		}
+	}
+	for i, pattern := range []string{"", "T*F**F**", "T*F**F****", "T*F**X***"} {
+		if _, err := m.Matches(pattern); err == nil {
+			t.Fatalf("(case %d) expected an error for %q", i, pattern)
+		}
+	}
+}
+
+func TestRelatePattern(t *testing.T) {
+	var (
+		parcel   = &Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+		proposed = &Polygon{{{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}}}
+	)
+	// Within.
+	ok, err := RelatePattern(proposed, parcel, "T*F**F***")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatalf("expected %s to match", Relate(proposed, parcel))
+	}
+	// Interiors that do not intersect.
+	if ok, _ := RelatePattern(proposed, parcel, "F********"); ok {
+		t.Fatalf("expected %s not to match", Relate(proposed, parcel))
+	}
+	if _, err := RelatePattern(proposed, parcel, "within"); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,520 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// wgs84SRID is the spatial reference identifier of WGS 84,
+// the only coordinate reference system allowed by RFC 7946.
+const wgs84SRID = 4326
+
+// Errors returned by the RFC 7946 encoder and decoder.
+var (
+	errRFC7946Antimeridian = errors.New("rfc7946: geometry crosses the antimeridian")
+	errRFC7946Circle       = errors.New("rfc7946: Circle is not a GeoJSON geometry type")
+	errRFC7946Pole         = errors.New("rfc7946: cannot split a ring that encloses a pole")
+)
+
+// RFC7946 encodes and decodes GeoJSON that strictly follows RFC 7946.
+// The package's MarshalJSON and UnmarshalJSON methods are more lenient:
+// they accept the non-standard Circle type, keep M ordinates,
+// and leave polygon winding order and the antimeridian alone.
+//
+// When encoding, exterior rings are wound counterclockwise and holes clockwise,
+// positions are limited to three ordinates, and Circles are refused.
+// Geometries with an SRID other than 4326 are refused,
+// since RFC 7946 only allows WGS 84 coordinates.
+type RFC7946 struct {
+	// Precision is the number of decimal places kept in each ordinate when encoding.
+	// RFC 7946 suggests 6, which is about 10 centimeters.
+	// Zero means coordinates are not rounded.
+	Precision int
+
+	// SplitAntimeridian splits lines and polygons that cross the antimeridian
+	// into multi-part geometries when encoding, as section 3.1.9 requires.
+	// If it is false such geometries are refused.
+	SplitAntimeridian bool
+}
+
+// Marshal returns RFC 7946 GeoJSON for a geometry, feature or feature collection.
+// The geometry itself is not modified.
+func (rfc RFC7946) Marshal(g Geometry) ([]byte, error) {
+	ng, err := rfc.normalize(g)
+	if err != nil {
+		return nil, err
+	}
+	return ng.MarshalJSON()
+}
+
+// Unmarshal unmarshals any RFC 7946 GeoJSON type from a JSON blob.
+// Circles and geometries that cross the antimeridian are rejected.
+// Polygon rings are rewound to follow the right-hand rule,
+// and M ordinates are dropped.
+func (rfc RFC7946) Unmarshal(data []byte) (Geometry, error) {
+	g, err := UnmarshalJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRFC7946(g); err != nil {
+		return nil, err
+	}
+	if LayoutOf(g).HasM() {
+		g.Transform(dropM{})
+	}
+	if lg, ok := g.(*layoutGeometry); ok {
+		g = withDecodedLayout(layoutFor(lg.Layout.HasZ(), false), lg.Geometry)
+	}
+	return g, nil
+}
+
+// checkRFC7946 rejects decoded geometries that RFC 7946 does not allow
+// and rewinds polygons in place.
+func checkRFC7946(g Geometry) error {
+	switch v := g.(type) {
+	case *Circle:
+		return errRFC7946Circle
+	case *Line:
+		if crossesAntimeridian(*v) {
+			return errRFC7946Antimeridian
+		}
+	case *MultiLine:
+		for _, line := range *v {
+			if crossesAntimeridian(line) {
+				return errRFC7946Antimeridian
+			}
+		}
+	case *Polygon:
+		if polygonCrossesAntimeridian(*v) {
+			return errRFC7946Antimeridian
+		}
+		rewindPolygon(*v)
+	case *MultiPolygon:
+		for _, poly := range *v {
+			if polygonCrossesAntimeridian(poly) {
+				return errRFC7946Antimeridian
+			}
+			rewindPolygon(poly)
+		}
+	case *GeometryCollection:
+		for _, member := range *v {
+			if err := checkRFC7946(member); err != nil {
+				return err
+			}
+		}
+	case *Feature:
+		return checkRFC7946(v.Geometry)
+	case *FeatureCollection:
+		for _, feat := range *v {
+			if err := checkRFC7946(feat); err != nil {
+				return err
+			}
+		}
+	case *boundingBox:
+		return checkRFC7946(v.Geometry)
+	case *sridGeometry:
+		if v.SRID != 0 && v.SRID != wgs84SRID {
+			return fmt.Errorf("rfc7946: coordinates must be WGS 84 (SRID %d), got SRID %d", wgs84SRID, v.SRID)
+		}
+		return checkRFC7946(v.Geometry)
+	case *layoutGeometry:
+		return checkRFC7946(v.Geometry)
+	}
+	return nil
+}
+
+// normalize returns a copy of a geometry that follows RFC 7946.
+func (rfc RFC7946) normalize(g Geometry) (Geometry, error) {
+	switch v := g.(type) {
+	case *Point:
+		p := Point(rfc.position(*v))
+		return &p, nil
+	case *MultiPoint:
+		mp := MultiPoint(rfc.positions(*v))
+		return &mp, nil
+	case *Line:
+		lines, err := rfc.lines([][][4]float64{*v})
+		if err != nil {
+			return nil, err
+		}
+		if len(lines) == 1 {
+			l := Line(lines[0])
+			return &l, nil
+		}
+		ml := MultiLine(lines)
+		return &ml, nil
+	case *MultiLine:
+		lines, err := rfc.lines(*v)
+		if err != nil {
+			return nil, err
+		}
+		ml := MultiLine(lines)
+		return &ml, nil
+	case *Polygon:
+		polys, err := rfc.polygons([][][][4]float64{*v})
+		if err != nil {
+			return nil, err
+		}
+		if len(polys) == 1 {
+			p := Polygon(polys[0])
+			return &p, nil
+		}
+		mp := MultiPolygon(polys)
+		return &mp, nil
+	case *MultiPolygon:
+		polys, err := rfc.polygons(*v)
+		if err != nil {
+			return nil, err
+		}
+		mp := MultiPolygon(polys)
+		return &mp, nil
+	case *GeometryCollection:
+		gc := make(GeometryCollection, len(*v))
+		for i, member := range *v {
+			ng, err := rfc.normalize(member)
+			if err != nil {
+				return nil, err
+			}
+			gc[i] = ng
+		}
+		return &gc, nil
+	case *Feature:
+		if v.Geometry == nil {
+			return &Feature{ID: v.ID, Properties: v.Properties, Members: v.Members}, nil
+		}
+		geom, err := rfc.normalize(v.Geometry)
+		if err != nil {
+			return nil, err
+		}
+		return &Feature{ID: v.ID, Geometry: geom, Properties: v.Properties, Members: v.Members}, nil
+	case *FeatureCollection:
+		coll := make(FeatureCollection, len(*v))
+		for i, feat := range *v {
+			nf, err := rfc.normalize(feat)
+			if err != nil {
+				return nil, err
+			}
+			coll[i] = nf.(*Feature)
+		}
+		return &coll, nil
+	case *Circle:
+		return nil, errRFC7946Circle
+	case *boundingBox:
+		geom, err := rfc.normalize(v.Geometry)
+		if err != nil {
+			return nil, err
+		}
+		if v.computed {
+			return WithComputedBBox(geom), nil
+		}
+		return WithBBox(v.Box, geom), nil
+	case *sridGeometry:
+		if v.SRID != 0 && v.SRID != wgs84SRID {
+			return nil, fmt.Errorf("rfc7946: coordinates must be WGS 84 (SRID %d), got SRID %d", wgs84SRID, v.SRID)
+		}
+		return rfc.normalize(v.Geometry)
+	case *layoutGeometry:
+		geom, err := rfc.normalize(v.Geometry)
+		if err != nil {
+			return nil, err
+		}
+		return WithLayout(layoutFor(v.Layout.HasZ(), false), geom), nil
+	default:
+		return nil, fmt.Errorf("rfc7946: cannot encode %T", g)
+	}
+}
+
+// position drops the M ordinate of a coordinate and rounds it to the configured precision.
+func (rfc RFC7946) position(c [4]float64) [4]float64 {
+	c[3] = 0
+	if rfc.Precision > 0 {
+		scale := math.Pow(10, float64(rfc.Precision))
+		for i := 0; i < 3; i++ {
+			c[i] = roundHalfAway(c[i]*scale) / scale
+		}
+	}
+	return c
+}
+
+// positions returns a copy of a list of coordinates with each one converted by position.
+func (rfc RFC7946) positions(pts [][4]float64) [][4]float64 {
+	out := make([][4]float64, len(pts))
+	for i, c := range pts {
+		out[i] = rfc.position(c)
+	}
+	return out
+}
+
+// lines copies a list of lines, splitting any that cross the antimeridian.
+func (rfc RFC7946) lines(lines [][][4]float64) ([][][4]float64, error) {
+	out := [][][4]float64{}
+	for _, line := range lines {
+		if !crossesAntimeridian(line) {
+			out = append(out, rfc.positions(line))
+			continue
+		}
+		if !rfc.SplitAntimeridian {
+			return nil, errRFC7946Antimeridian
+		}
+		for _, part := range splitLine(line) {
+			out = append(out, rfc.positions(part))
+		}
+	}
+	return out, nil
+}
+
+// polygons copies a list of polygons, splitting any that cross the antimeridian
+// and rewinding every ring to follow the right-hand rule.
+func (rfc RFC7946) polygons(polys [][][][4]float64) ([][][][4]float64, error) {
+	out := [][][][4]float64{}
+	for _, poly := range polys {
+		parts := [][][][4]float64{poly}
+		if polygonCrossesAntimeridian(poly) {
+			if !rfc.SplitAntimeridian {
+				return nil, errRFC7946Antimeridian
+			}
+			var err error
+			if parts, err = splitPolygon(poly); err != nil {
+				return nil, err
+			}
+		}
+		for _, part := range parts {
+			np := make([][][4]float64, len(part))
+			for i, ring := range part {
+				np[i] = rfc.positions(ring)
+			}
+			rewindPolygon(np)
+			out = append(out, np)
+		}
+	}
+	return out, nil
+}
+
+// dropM is a Transformer that sets the M ordinate of every point to zero.
+type dropM struct{}
+
+// Transform transforms a point.
+func (dropM) Transform(p Point) Point {
+	p[3] = 0
+	return p
+}
+
+// roundHalfAway rounds x to the nearest integer, rounding halfway cases away from zero.
+func roundHalfAway(x float64) float64 {
+	return math.Trunc(x + math.Copysign(0.5, x))
+}
+
+// signedArea returns the area of a closed ring in the XY plane.
+// The area is positive if the ring is counterclockwise and negative if it is clockwise.
+func signedArea(ring [][4]float64) float64 {
+	a := 0.0
+	for i := 1; i < len(ring); i++ {
+		a += ring[i-1][0]*ring[i][1] - ring[i][0]*ring[i-1][1]
+	}
+	return a / 2
+}
+
+// reverseRing reverses the order of the coordinates in a ring.
+func reverseRing(ring [][4]float64) {
+	for i, j := 0, len(ring)-1; i < j; i, j = i+1, j-1 {
+		ring[i], ring[j] = ring[j], ring[i]
+	}
+}
+
+// rewindPolygon winds the exterior ring of a polygon counterclockwise
+// and its holes clockwise, as RFC 7946 section 3.1.6 requires.
+func rewindPolygon(poly [][][4]float64) {
+	for i, ring := range poly {
+		if a := signedArea(ring); (i == 0 && a < 0) || (i > 0 && a > 0) {
+			reverseRing(ring)
+		}
+	}
+}
+
+// crossesAntimeridian returns true if any segment of a line spans more than
+// 180 degrees of longitude, which is taken to mean that it crosses the antimeridian.
+func crossesAntimeridian(pts [][4]float64) bool {
+	for i := 1; i < len(pts); i++ {
+		if math.Abs(pts[i][0]-pts[i-1][0]) > 180 {
+			return true
+		}
+	}
+	return false
+}
+
+// polygonCrossesAntimeridian returns true if any ring of a polygon crosses the antimeridian.
+func polygonCrossesAntimeridian(poly [][][4]float64) bool {
+	for _, ring := range poly {
+		if crossesAntimeridian(ring) {
+			return true
+		}
+	}
+	return false
+}
+
+// antimeridianCrossing returns the point where the segment from a to b
+// crosses the antimeridian, with the longitude on a's side.
+func antimeridianCrossing(a, b [4]float64) [4]float64 {
+	edge, bx := 180.0, b[0]+360
+	if b[0] > a[0] {
+		edge, bx = -180, b[0]-360
+	}
+	return lerpX(a, [4]float64{bx, b[1], b[2], b[3]}, edge)
+}
+
+// lerpX returns the point on the segment from a to b whose X ordinate is x.
+func lerpX(a, b [4]float64, x float64) [4]float64 {
+	t := (x - a[0]) / (b[0] - a[0])
+	c := [4]float64{x}
+	for i := 1; i < 4; i++ {
+		c[i] = a[i] + t*(b[i]-a[i])
+	}
+	return c
+}
+
+// splitLine splits a line where it crosses the antimeridian.
+func splitLine(pts [][4]float64) [][][4]float64 {
+	var (
+		parts = [][][4]float64{}
+		part  = [][4]float64{pts[0]}
+	)
+	for i := 1; i < len(pts); i++ {
+		a, b := pts[i-1], pts[i]
+		if math.Abs(b[0]-a[0]) > 180 {
+			c := antimeridianCrossing(a, b)
+			if c != a {
+				part = append(part, c)
+			}
+			if len(part) > 1 {
+				parts = append(parts, part)
+			}
+			c[0] = -c[0]
+			part = [][4]float64{c}
+			if c == b {
+				continue
+			}
+		}
+		part = append(part, b)
+	}
+	if len(part) > 1 {
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// splitPolygon splits a polygon where it crosses the antimeridian.
+// The rings are unwrapped so that their longitudes are continuous,
+// clipped on either side of the antimeridian,
+// and the part beyond it is shifted back by 360 degrees.
+func splitPolygon(poly [][][4]float64) ([][][][4]float64, error) {
+	rings := make([][][4]float64, len(poly))
+	for i, ring := range poly {
+		r := unwrapLongitudes(ring)
+		if len(r) > 0 && math.Abs(r[len(r)-1][0]-r[0][0]) > 180 {
+			return nil, errRFC7946Pole
+		}
+		rings[i] = r
+	}
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	for _, c := range rings[0] {
+		minX, maxX = math.Min(minX, c[0]), math.Max(maxX, c[0])
+	}
+	// Holes are unwrapped on their own, so move them next to the exterior ring.
+	for _, hole := range rings[1:] {
+		shift := 0.0
+		for len(hole) > 0 && hole[0][0]+shift > maxX {
+			shift -= 360
+		}
+		for len(hole) > 0 && hole[0][0]+shift < minX {
+			shift += 360
+		}
+		shiftLongitudes(hole, shift)
+	}
+	edge, shift := 180.0, -360.0
+	if minX < -180 {
+		edge, shift = -180, 360
+	}
+	var near, far [][][4]float64
+	for i, ring := range rings {
+		n, f := clipRing(ring, edge, edge > 0), clipRing(ring, edge, edge < 0)
+		shiftLongitudes(f, shift)
+		if i == 0 || len(near) > 0 {
+			if len(n) >= 4 {
+				near = append(near, n)
+			}
+		}
+		if i == 0 || len(far) > 0 {
+			if len(f) >= 4 {
+				far = append(far, f)
+			}
+		}
+	}
+	parts := [][][][4]float64{}
+	for _, part := range [][][][4]float64{near, far} {
+		if len(part) > 0 {
+			parts = append(parts, part)
+		}
+	}
+	return parts, nil
+}
+
+// unwrapLongitudes returns a copy of a ring with 360 degrees added to or
+// subtracted from longitudes so that no segment spans more than 180 degrees.
+func unwrapLongitudes(ring [][4]float64) [][4]float64 {
+	out := make([][4]float64, len(ring))
+	copy(out, ring)
+	for i := 1; i < len(out); i++ {
+		for out[i][0]-out[i-1][0] > 180 {
+			out[i][0] -= 360
+		}
+		for out[i][0]-out[i-1][0] < -180 {
+			out[i][0] += 360
+		}
+	}
+	return out
+}
+
+// shiftLongitudes adds shift to every longitude in a ring.
+func shiftLongitudes(ring [][4]float64, shift float64) {
+	for i := range ring {
+		ring[i][0] += shift
+	}
+}
+
+// clipRing clips a closed ring to one side of the vertical line X = edge,
+// keeping the part where X <= edge if below is true and X >= edge otherwise.
+// This is the Sutherland-Hodgman algorithm for a single clipping edge.
+// The result is closed, or empty if no part of the ring is kept.
+func clipRing(ring [][4]float64, edge float64, below bool) [][4]float64 {
+	inside := func(c [4]float64) bool {
+		if below {
+			return c[0] <= edge
+		}
+		return c[0] >= edge
+	}
+	out := [][4]float64{}
+	for i := 1; i < len(ring); i++ {
+		a, b := ring[i-1], ring[i]
+		switch {
+		case inside(a) && inside(b):
+			out = appendDistinct(out, b)
+		case inside(a):
+			out = appendDistinct(out, lerpX(a, b, edge))
+		case inside(b):
+			out = appendDistinct(appendDistinct(out, lerpX(a, b, edge)), b)
+		}
+	}
+	if len(out) > 0 && out[0] != out[len(out)-1] {
+		out = append(out, out[0])
+	}
+	return out
+}
+
+// appendDistinct appends c to pts unless it repeats the last coordinate.
+func appendDistinct(pts [][4]float64, c [4]float64) [][4]float64 {
+	if len(pts) > 0 && pts[len(pts)-1] == c {
+		return pts
+	}
+	return append(pts, c)
+}
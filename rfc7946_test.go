@@ -0,0 +1,196 @@
+package geo
+
+import "testing"
+
+func TestRFC7946Marshal(t *testing.T) {
+	for i, testcase := range []struct {
+		RFC      RFC7946
+		Input    Geometry
+		Expected string
+	}{
+		{
+			Input:    &Point{1.123456789, 2.5, 0, 7},
+			Expected: `{"type":"Point","coordinates":[1.123456789,2.5]}`,
+		},
+		{
+			RFC:      RFC7946{Precision: 6},
+			Input:    &Point{1.123456789, -2.0000005, 3.33333333},
+			Expected: `{"type":"Point","coordinates":[1.123457,-2.000001,3.333333]}`,
+		},
+		{
+			// Clockwise exterior and counterclockwise hole are rewound.
+			Input:    &Polygon{{{0, 0}, {0, 4}, {4, 4}, {4, 0}, {0, 0}}, {{1, 1}, {2, 1}, {2, 2}, {1, 1}}},
+			Expected: `{"type":"Polygon","coordinates":[[[0,0],[4,0],[4,4],[0,4],[0,0]],[[1,1],[2,2],[2,1],[1,1]]]}`,
+		},
+		{
+			RFC:      RFC7946{SplitAntimeridian: true},
+			Input:    &Line{{170, 0}, {-170, 10}},
+			Expected: `{"type":"MultiLineString","coordinates":[[[170,0],[180,5]],[[-180,5],[-170,10]]]}`,
+		},
+		{
+			RFC:      RFC7946{SplitAntimeridian: true},
+			Input:    &Line{{-170, 0}, {170, 10}, {160, 10}},
+			Expected: `{"type":"MultiLineString","coordinates":[[[-170,0],[-180,5]],[[180,5],[170,10],[160,10]]]}`,
+		},
+		{
+			RFC:      RFC7946{SplitAntimeridian: true},
+			Input:    &Polygon{{{170, 0}, {-170, 0}, {-170, 10}, {170, 10}, {170, 0}}},
+			Expected: `{"type":"MultiPolygon","coordinates":[[[[180,0],[180,10],[170,10],[170,0],[180,0]]],[[[-180,0],[-170,0],[-170,10],[-180,10],[-180,0]]]]}`,
+		},
+		{
+			RFC:      RFC7946{SplitAntimeridian: true},
+			Input:    &MultiPolygon{{{{-170, 0}, {-170, 10}, {170, 10}, {170, 0}, {-170, 0}}}},
+			Expected: `{"type":"MultiPolygon","coordinates":[[[[-170,10],[-180,10],[-180,0],[-170,0],[-170,10]]],[[[180,10],[170,10],[170,0],[180,0],[180,10]]]]}`,
+		},
+		{
+			Input:    &Feature{Geometry: WithSRID(4326, &Point{1, 2}), Properties: map[string]interface{}{"a": 1}},
+			Expected: `{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":{"a":1}}`,
+		},
+		{
+			Input:    &FeatureCollection{{Geometry: WithLayout(XYZM, &Point{1, 2})}},
+			Expected: `{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2,0]},"properties":null}]}`,
+		},
+		{
+			Input:    WithBBox([]float64{0, 0, 1, 1}, &GeometryCollection{&MultiPoint{{0, 0}, {1, 1, 0, 5}}}),
+			Expected: `{"type":"GeometryCollection","geometries":[{"type":"MultiPoint","coordinates":[[0,0],[1,1]]}],"bbox":[0,0,1,1]}`,
+		},
+	} {
+		data, err := testcase.RFC.Marshal(testcase.Input)
+		if err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if expected, got := testcase.Expected, string(data); expected != got {
+			t.Fatalf("(case %d) expected %s, got %s", i, expected, got)
+		}
+	}
+
+	// The input is not modified.
+	poly := &Polygon{{{0, 0}, {0, 4}, {4, 4}, {0, 0}}}
+	if _, err := (RFC7946{Precision: 1}).Marshal(poly); err != nil {
+		t.Fatal(err)
+	}
+	if expected := (&Polygon{{{0, 0}, {0, 4}, {4, 4}, {0, 0}}}); !expected.Equal(poly) {
+		t.Fatalf("expected %s, got %s", expected, poly)
+	}
+
+	// Fail
+	for i, testcase := range []struct {
+		RFC   RFC7946
+		Input Geometry
+	}{
+		{Input: &Circle{Radius: 1}},
+		{Input: &Feature{Geometry: &Circle{Radius: 1}}},
+		{Input: &GeometryCollection{&Circle{Radius: 1}}},
+		{Input: &Line{{170, 0}, {-170, 10}}},
+		{Input: &MultiPolygon{{{{170, 0}, {-170, 0}, {-170, 10}, {170, 0}}}}},
+		{Input: WithSRID(3857, &Point{1, 2})},
+		{Input: badGeom{}},
+		{
+			// Encloses the north pole.
+			RFC:   RFC7946{SplitAntimeridian: true},
+			Input: &Polygon{{{0, 80}, {120, 80}, {-120, 80}, {0, 80}}},
+		},
+	} {
+		if _, err := testcase.RFC.Marshal(testcase.Input); err == nil {
+			t.Fatalf("(case %d) expected error, got nil", i)
+		}
+	}
+}
+
+func TestRFC7946SplitPolygonHole(t *testing.T) {
+	rfc := RFC7946{SplitAntimeridian: true}
+	data, err := rfc.Marshal(&Polygon{
+		{{160, -10}, {-160, -10}, {-160, 10}, {160, 10}, {160, -10}},
+		{{170, -5}, {170, 5}, {-170, 5}, {-170, -5}, {170, -5}},
+		{{-175, -1}, {-175, 1}, {-174, 1}, {-175, -1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := UnmarshalJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mp, ok := g.(*MultiPolygon)
+	if !ok || len(*mp) != 2 {
+		t.Fatalf("expected a MultiPolygon with 2 polygons, got %s", g)
+	}
+	// The first hole is split in two, the second one is entirely east of the antimeridian.
+	if expected, got := 2, len((*mp)[0]); expected != got {
+		t.Fatalf("expected %d rings, got %d", expected, got)
+	}
+	if expected, got := 3, len((*mp)[1]); expected != got {
+		t.Fatalf("expected %d rings, got %d", expected, got)
+	}
+	for _, poly := range *mp {
+		for j, ring := range poly {
+			if (j == 0) != (signedArea(ring) > 0) {
+				t.Fatalf("ring %d has the wrong winding order", j)
+			}
+			for _, c := range ring {
+				if c[0] < -180 || c[0] > 180 {
+					t.Fatalf("longitude %f out of range", c[0])
+				}
+			}
+		}
+	}
+}
+
+func TestRFC7946Unmarshal(t *testing.T) {
+	for i, testcase := range []struct {
+		Input    string
+		Expected Geometry
+	}{
+		{
+			Input:    `{"type":"Polygon","coordinates":[[[0,0],[0,4],[4,4],[0,0]]]}`,
+			Expected: &Polygon{{{0, 0}, {4, 4}, {0, 4}, {0, 0}}},
+		},
+		{
+			Input:    `{"type":"Point","coordinates":[1,2,3,4]}`,
+			Expected: &Point{1, 2, 3},
+		},
+		{
+			// Z ordinates that are all zero.
+			Input:    `{"type":"Polygon","coordinates":[[[0,0,0],[0,1,0],[1,1,0],[0,0,0]]]}`,
+			Expected: &Polygon{{{0, 0, decodedZero}, {1, 1, decodedZero}, {0, 1, decodedZero}, {0, 0, decodedZero}}},
+		},
+		{
+			Input:    `{"type":"Feature","geometry":{"type":"Polygon","coordinates":[[[0,0,0],[0,1,0],[1,1,0],[0,0,0]]]},"properties":null}`,
+			Expected: &Feature{Geometry: &Polygon{{{0, 0, decodedZero}, {1, 1, decodedZero}, {0, 1, decodedZero}, {0, 0, decodedZero}}}},
+		},
+		{
+			Input:    `{"type":"Point","coordinates":[1,2,0,0]}`,
+			Expected: &Point{1, 2, decodedZero},
+		},
+		{
+			Input:    `{"type":"Feature","geometry":{"type":"MultiPolygon","coordinates":[[[[0,0],[0,4],[4,4],[0,0]]]]},"properties":null}`,
+			Expected: &Feature{Geometry: &MultiPolygon{{{{0, 0}, {4, 4}, {0, 4}, {0, 0}}}}},
+		},
+	} {
+		g, err := RFC7946{}.Unmarshal([]byte(testcase.Input))
+		if err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if !testcase.Expected.Equal(g) {
+			t.Fatalf("(case %d) expected %s, got %s", i, testcase.Expected, g)
+		}
+		if expected, got := LayoutOf(testcase.Expected), LayoutOf(g); expected != got {
+			t.Fatalf("(case %d) expected %s, got %s", i, expected, got)
+		}
+	}
+
+	// Fail
+	for i, input := range []string{
+		`{"type":"Circle","radius":1,"coordinates":[0,0]}`,
+		`{"type":"LineString","coordinates":[[170,0],[-170,0]]}`,
+		`{"type":"LineString","coordinates":[[170,0,0],[-170,0,0]]}`,
+		`{"type":"MultiPolygon","coordinates":[[[[170,0,0],[-170,0,0],[-170,1,0],[170,0,0]]]]}`,
+		`{"type":"GeometryCollection","geometries":[{"type":"MultiLineString","coordinates":[[[170,0],[-170,0]]]}]}`,
+		`{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Polygon","coordinates":[[[170,0],[-170,0],[-170,1],[170,0]]]}}]}`,
+		`{"type":"Point","coordinates":"bork"}`,
+	} {
+		if _, err := (RFC7946{}).Unmarshal([]byte(input)); err == nil {
+			t.Fatalf("(case %d) expected error, got nil", i)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package geo
+
+import "math"
+
+// Rhumb lines, or loxodromes, cross every meridian at the same angle,
+// so they can be followed with a constant bearing.
+// Like the great circle methods, the methods in this file work on a sphere
+// with the same radius as the one Circle uses, and measure distances in meters.
+// Rhumb lines take the shorter way around, across the antimeridian if need be.
+
+// RhumbDistance returns the distance in meters along the rhumb line
+// from the point to another point.
+func (point Point) RhumbDistance(to Point) float64 {
+	var (
+		lat1 = toRadians(point[1])
+		lat2 = toRadians(to[1])
+		dLat = lat2 - lat1
+		dLng = rhumbLongitudeDifference(point, to)
+		q    = rhumbStretch(lat1, lat2)
+	)
+	return earthRadiusMeters * math.Hypot(dLat, q*dLng)
+}
+
+// RhumbBearing returns the constant bearing in degrees clockwise from north
+// of the rhumb line from the point to another point, in the range [0, 360).
+func (point Point) RhumbBearing(to Point) float64 {
+	var (
+		dLng = rhumbLongitudeDifference(point, to)
+		dPsi = projectedLatitudeDifference(toRadians(point[1]), toRadians(to[1]))
+	)
+	return normalizeBearing(toDegrees(math.Atan2(dLng, dPsi)))
+}
+
+// RhumbDestination returns the point reached by travelling the given distance
+// in meters from the point along the rhumb line with the given bearing.
+// The longitude of the destination is in the range [-180, 180],
+// and it has the same Z and M as the point.
+func (point Point) RhumbDestination(distance, bearing float64) Point {
+	var (
+		d     = distance / earthRadiusMeters
+		theta = toRadians(bearing)
+		lat1  = toRadians(point[1])
+		dLat  = d * math.Cos(theta)
+		lat2  = lat1 + dLat
+	)
+	// A rhumb line that passes a pole comes back down the other side.
+	if lat2 > math.Pi/2 {
+		lat2 = math.Pi - lat2
+	} else if lat2 < -math.Pi/2 {
+		lat2 = -math.Pi - lat2
+	}
+	dLng := d * math.Sin(theta) / rhumbStretch(lat1, lat2)
+	return Point{angNormalize(point[0] + toDegrees(dLng)), toDegrees(lat2), point[2], point[3]}
+}
+
+// RhumbMidpoint returns the point halfway along the rhumb line from the point
+// to another point.
+// Z and M are the averages of those of the two points.
+func (point Point) RhumbMidpoint(to Point) Point {
+	return point.rhumbIntermediatePoint(to, 0.5)
+}
+
+// RhumbDensify returns the line with points added along the rhumb line
+// between each pair of consecutive points, so that no two consecutive points
+// are more than spacing meters apart.
+// The points added to each segment are evenly spaced.
+// A copy of the line is returned if spacing is not positive.
+func (line Line) RhumbDensify(spacing float64) Line {
+	dense := Line{}
+	for i, p := range line {
+		if i > 0 && spacing > 0 {
+			prev := Point(line[i-1])
+			n := math.Ceil(prev.RhumbDistance(p) / spacing)
+			for j := 1.0; j < n; j++ {
+				dense = append(dense, prev.rhumbIntermediatePoint(p, j/n))
+			}
+		}
+		dense = append(dense, p)
+	}
+	return dense
+}
+
+// RhumbDensify densifies each line along rhumb lines, see Line.RhumbDensify.
+func (multiLine MultiLine) RhumbDensify(spacing float64) MultiLine {
+	dense := make(MultiLine, len(multiLine))
+	for i, line := range multiLine {
+		dense[i] = Line(line).RhumbDensify(spacing)
+	}
+	return dense
+}
+
+// rhumbIntermediatePoint returns the point at the given fraction of the way
+// along the rhumb line from the point to another point.
+// Z and M are interpolated linearly.
+func (point Point) rhumbIntermediatePoint(to Point, fraction float64) Point {
+	p := point.RhumbDestination(fraction*point.RhumbDistance(to), point.RhumbBearing(to))
+	p[2] = point[2] + fraction*(to[2]-point[2])
+	p[3] = point[3] + fraction*(to[3]-point[3])
+	return p
+}
+
+// rhumbLongitudeDifference returns the difference in longitude in radians
+// from a to b, taking the shorter way around.
+func rhumbLongitudeDifference(a, b Point) float64 {
+	return toRadians(angNormalize(b[0] - a[0]))
+}
+
+// projectedLatitudeDifference returns the difference between two latitudes
+// on a Mercator projection.
+func projectedLatitudeDifference(lat1, lat2 float64) float64 {
+	return math.Log(math.Tan(math.Pi/4+lat2/2) / math.Tan(math.Pi/4+lat1/2))
+}
+
+// rhumbStretch returns the ratio of the difference between two latitudes
+// to their difference on a Mercator projection,
+// which is the cosine of the latitude if they are the same.
+func rhumbStretch(lat1, lat2 float64) float64 {
+	dPsi := projectedLatitudeDifference(lat1, lat2)
+	if math.Abs(dPsi) > 1e-12 {
+		return (lat2 - lat1) / dPsi
+	}
+	return math.Cos(lat1)
+}
@@ -0,0 +1,98 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRhumb(t *testing.T) {
+	var (
+		plymouth = Point{dms(-4, 8, 2), dms(50, 21, 59), 0, 4}
+		boston   = Point{dms(-71, 2, 27), dms(42, 21, 4), 10, 8}
+	)
+	if expected, got := 5198e3, plymouth.RhumbDistance(boston); math.Abs(expected-got) > 1e3 {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	if expected, got := dms(260, 7, 38), plymouth.RhumbBearing(boston); math.Abs(expected-got) > 1e-3 {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	mid := plymouth.RhumbMidpoint(boston)
+	if expected := (Point{dms(-38, 49, 0), dms(46, 21, 32), 5, 6}); math.Abs(expected[0]-mid[0]) > 1e-3 || math.Abs(expected[1]-mid[1]) > 1e-3 || expected[2] != mid[2] || expected[3] != mid[3] {
+		t.Fatalf("expected %v, got %v", expected, mid)
+	}
+	var (
+		dover    = Point{dms(1, 20, 17), dms(51, 7, 32)}
+		expected = Point{dms(1, 51, 8), dms(50, 57, 48)}
+		got      = dover.RhumbDestination(40.23e3, dms(116, 38, 10))
+	)
+	if math.Abs(expected[0]-got[0]) > 1e-3 || math.Abs(expected[1]-got[1]) > 1e-3 {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestRhumbAntimeridian(t *testing.T) {
+	var (
+		a = Point{179, 10}
+		b = Point{-179, 12}
+	)
+	if d := a.RhumbDistance(b); d > 400e3 {
+		t.Fatalf("expected the short way across the antimeridian, got %f", d)
+	}
+	if bearing := a.RhumbBearing(b); bearing <= 0 || bearing >= 90 {
+		t.Fatalf("expected a bearing between north and east, got %f", bearing)
+	}
+	mid := a.RhumbMidpoint(b)
+	if math.Abs(math.Abs(mid[0])-180) > 0.01 || math.Abs(mid[1]-11) > 0.01 {
+		t.Fatalf("expected a midpoint near the antimeridian, got %v", mid)
+	}
+	p := a.RhumbDestination(a.RhumbDistance(b), a.RhumbBearing(b))
+	if math.Abs(p[0]-b[0]) > 1e-9 || math.Abs(p[1]-b[1]) > 1e-9 {
+		t.Fatalf("expected %v, got %v", b, p)
+	}
+	for _, q := range (Line{a, b}).RhumbDensify(10e3) {
+		if math.Abs(q[0]) < 179 {
+			t.Fatalf("expected every point near the antimeridian, got %v", q)
+		}
+	}
+}
+
+func TestRhumbAlongParallel(t *testing.T) {
+	var (
+		a = Point{0, 60}
+		b = Point{10, 60}
+	)
+	if expected, got := earthRadiusMeters*toRadians(10)*math.Cos(toRadians(60)), a.RhumbDistance(b); math.Abs(expected-got) > 1e-6 {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	if expected, got := 90.0, a.RhumbBearing(b); math.Abs(expected-got) > 1e-9 {
+		t.Fatalf("expected %f, got %f", expected, got)
+	}
+	for _, q := range (Line{a, b}).RhumbDensify(1e3) {
+		if math.Abs(q[1]-60) > 1e-9 {
+			t.Fatalf("expected every point on the parallel, got %v", q)
+		}
+	}
+}
+
+func TestRhumbDensify(t *testing.T) {
+	var (
+		line    = Line{{-73.8, 40.6}, {-0.5, 51.6}, {-0.5, 51.6}}
+		spacing = 100e3
+		dense   = line.RhumbDensify(spacing)
+		bearing = Point(line[0]).RhumbBearing(line[1])
+	)
+	for i := 1; i < len(dense)-1; i++ {
+		if d := Point(dense[i-1]).RhumbDistance(dense[i]); d > spacing+1e-6 {
+			t.Fatalf("expected points at most %f apart, got %f", spacing, d)
+		}
+		if got := Point(dense[i-1]).RhumbBearing(dense[i]); math.Abs(bearing-got) > 1e-6 {
+			t.Fatalf("expected a constant bearing of %f, got %f", bearing, got)
+		}
+	}
+	if expected, got := line, line.RhumbDensify(-1); !expected.Equal(&got) {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if expected, got := dense, Line(MultiLine{line}.RhumbDensify(spacing)[0]); !expected.Equal(&got) {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
@@ -7,14 +7,42 @@ type scanner interface {
 	scan(s string) error
 }
 
-// scan scans an interface{} with a scanner
+// scan scans an interface{} with a scanner.
+// A []byte may hold Well Known Text, Well Known Binary
+// or hex-encoded Well Known Binary, and a string may hold
+// Well Known Text or hex-encoded Well Known Binary.
+// Postgres drivers return geometry columns as hex-encoded WKB.
 func scan(s scanner, data interface{}) error {
 	switch v := data.(type) {
 	case []byte:
-		return s.scan(string(v))
+		if isWKB(v) {
+			return scanWKB(s, v)
+		}
+		return scanText(s, string(v))
 	case string:
-		return s.scan(v)
+		return scanText(s, v)
 	default:
 		return fmt.Errorf("could not scan from %T", data)
 	}
 }
+
+// scanText scans Well Known Text or hex-encoded Well Known Binary.
+func scanText(s scanner, text string) error {
+	if !isHexWKB(text) {
+		return s.scan(text)
+	}
+	data, err := decodeHexWKB(text)
+	if err != nil {
+		return err
+	}
+	return scanWKB(s, data)
+}
+
+// scanWKB scans Well Known Binary.
+func scanWKB(s scanner, data []byte) error {
+	u, ok := s.(wkbUnmarshaler)
+	if !ok {
+		return fmt.Errorf("could not scan %T from WKB", s)
+	}
+	return u.UnmarshalWKB(data)
+}
@@ -0,0 +1,151 @@
+package geo
+
+import (
+	"math"
+	"sort"
+)
+
+// snapRound snap rounds the polygons of several geometries together to a grid.
+//
+// Every vertex, and every point where two edges cross, is rounded to the center
+// of the grid cell (the "hot pixel") that it is in, and every edge that passes
+// through a hot pixel is bent to go through its center.
+// Afterwards edges only meet at their vertices or where they overlap,
+// and all the vertices are on the grid, so the geometries can be compared exactly.
+// Polygons may collapse into rings that touch themselves or have no area.
+// See John D. Hobby, "Practical segment intersection with finite precision output" (1999).
+func snapRound(geoms []relateGeometry, grid float64) []relateGeometry {
+	snap := func(p [4]float64) [4]float64 {
+		p[0], p[1] = math.Round(p[0]/grid)*grid, math.Round(p[1]/grid)*grid
+		return p
+	}
+
+	var (
+		rounded = make([][][][][4]float64, len(geoms))
+		segs    []segment
+		hot     = map[[2]float64]bool{}
+	)
+	for i, g := range geoms {
+		rounded[i] = make([][][][4]float64, len(g.polygons))
+		for j, poly := range g.polygons {
+			rounded[i][j] = make([][][4]float64, len(poly))
+			for k, ring := range poly {
+				r := make([][4]float64, len(ring))
+				for l, p := range ring {
+					r[l] = snap(p)
+					hot[[2]float64{r[l][0], r[l][1]}] = true
+				}
+				rounded[i][j][k] = r
+				segs = append(segs, ringSegments(r, len(segs))...)
+			}
+		}
+	}
+	for i := range segs {
+		segs[i].seq = i
+	}
+	sweepSegments(segs, func(s, t segment) bool {
+		for _, p := range segmentIntersection(s.a, s.b, t.a, t.b) {
+			p = snap(p)
+			hot[[2]float64{p[0], p[1]}] = true
+		}
+		return true
+	})
+
+	pixels := make([][4]float64, 0, len(hot))
+	for p := range hot {
+		pixels = append(pixels, [4]float64{p[0], p[1]})
+	}
+	sort.Slice(pixels, func(i, j int) bool {
+		return pixels[i][0] < pixels[j][0] || pixels[i][0] == pixels[j][0] && pixels[i][1] < pixels[j][1]
+	})
+
+	snapped := make([]relateGeometry, len(geoms))
+	for i := range geoms {
+		for _, poly := range rounded[i] {
+			rings := make([][][4]float64, 0, len(poly))
+			for _, ring := range poly {
+				if len(ring) == 0 {
+					continue
+				}
+				rings = append(rings, snapRing(ring, pixels, grid))
+			}
+			rewindPolygon(rings)
+			snapped[i].polygons = append(snapped[i].polygons, rings)
+		}
+	}
+	return snapped
+}
+
+// snapRing returns a ring with every edge bent to go through the centers of
+// the hot pixels that it passes through, which are sorted by X and then Y.
+func snapRing(ring [][4]float64, pixels [][4]float64, grid float64) [][4]float64 {
+	var (
+		half    = grid / 2
+		snapped = [][4]float64{ring[0]}
+	)
+	for i := 1; i < len(ring); i++ {
+		var (
+			a, b    = ring[i-1], ring[i]
+			minX    = math.Min(a[0], b[0]) - half
+			maxX    = math.Max(a[0], b[0]) + half
+			minY    = math.Min(a[1], b[1]) - half
+			maxY    = math.Max(a[1], b[1]) + half
+			centers [][4]float64
+		)
+		first := sort.Search(len(pixels), func(j int) bool {
+			return pixels[j][0] >= minX
+		})
+		for _, c := range pixels[first:] {
+			if c[0] > maxX {
+				break
+			}
+			if c[1] < minY || c[1] > maxY {
+				continue
+			}
+			if (c[0] == a[0] && c[1] == a[1]) || (c[0] == b[0] && c[1] == b[1]) {
+				continue
+			}
+			if segmentInPixel(a, b, c, half) {
+				centers = append(centers, c)
+			}
+		}
+		sort.Slice(centers, func(j, k int) bool {
+			return squaredDistance(a, centers[j]) < squaredDistance(a, centers[k])
+		})
+		for _, c := range append(centers, b) {
+			if last := snapped[len(snapped)-1]; last[0] != c[0] || last[1] != c[1] {
+				snapped = append(snapped, c)
+			}
+		}
+	}
+	return snapped
+}
+
+// segmentInPixel returns true if the segment ab has a point in common with the
+// square pixel with center c and sides of twice half.
+func segmentInPixel(a, b, c [4]float64, half float64) bool {
+	var (
+		minX, maxX = c[0] - half, c[0] + half
+		minY, maxY = c[1] - half, c[1] + half
+	)
+	if a[0] >= minX && a[0] <= maxX && a[1] >= minY && a[1] <= maxY {
+		return true
+	}
+	corners := [][4]float64{{minX, minY}, {maxX, minY}, {maxX, maxY}, {minX, maxY}}
+	for i, p := range corners {
+		if segmentsIntersect(a, b, p, corners[(i+1)%len(corners)]) {
+			return true
+		}
+	}
+	return false
+}
+
+// gridSize returns the smallest power of two that is at least size,
+// so that the points of a grid of that size are exact.
+func gridSize(size float64) float64 {
+	frac, exp := math.Frexp(size)
+	if frac == 0.5 {
+		return size
+	}
+	return math.Ldexp(1, exp)
+}
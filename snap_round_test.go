@@ -0,0 +1,77 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSnapRound(t *testing.T) {
+	for i, testcase := range []struct {
+		A, B Geometry
+		Grid float64
+	}{
+		{
+			// Squares whose edges cross.
+			A:    &Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}},
+			B:    &Polygon{{{5, 5}, {15, 5}, {15, 15}, {5, 15}, {5, 5}}},
+			Grid: 1,
+		},
+		{
+			// A vertex a tiny distance from the edge of the other triangle.
+			A:    &Polygon{{{0, 0}, {10, 0}, {5, 5 + 1e-13}, {0, 0}}},
+			B:    &Polygon{{{0, 5}, {10, 5}, {5, 10}, {0, 5}}},
+			Grid: 1.0 / 1024,
+		},
+		{
+			// Edges that cross at a point that is not on the grid.
+			A:    &Polygon{{{0, 0}, {3, 0}, {0, 7}, {0, 0}}},
+			B:    &Polygon{{{0, 1}, {7, 1}, {7, 2}, {0, 1}}},
+			Grid: 0.5,
+		},
+	} {
+		snapped := snapRound([]relateGeometry{polygonalParts(testcase.A), polygonalParts(testcase.B)}, testcase.Grid)
+
+		var segs []segment
+		for _, g := range snapped {
+			for _, poly := range g.polygons {
+				for _, ring := range poly {
+					for _, p := range ring {
+						for _, v := range p[:2] {
+							if v != math.Round(v/testcase.Grid)*testcase.Grid {
+								t.Fatalf("(case %d) expected vertices on the grid, got %v", i, p)
+							}
+						}
+					}
+					segs = append(segs, ringSegments(ring, len(segs))...)
+				}
+			}
+		}
+		for j := range segs {
+			segs[j].seq = j
+		}
+		sweepSegments(segs, func(s, u segment) bool {
+			for _, p := range segmentIntersection(s.a, s.b, u.a, u.b) {
+				if !isEndpoint(s, p) || !isEndpoint(u, p) {
+					t.Fatalf("(case %d) expected edges to meet at vertices, %v and %v meet at %v", i, s, u, p)
+				}
+			}
+			return true
+		})
+	}
+}
+
+func TestGridSize(t *testing.T) {
+	for _, testcase := range []struct {
+		Size, Expected float64
+	}{
+		{1, 1},
+		{0.5, 0.5},
+		{0.3, 0.5},
+		{3, 4},
+		{1e-10, math.Ldexp(1, -33)},
+	} {
+		if got := gridSize(testcase.Size); got != testcase.Expected {
+			t.Fatalf("expected grid size %g for %g, got %g", testcase.Expected, testcase.Size, got)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package geo
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"strconv"
+)
+
+// WithSRID returns a geometry that carries a spatial reference identifier.
+// The returned geometry converts to and from Extended Well Known Text,
+// e.g. "SRID=4326;POINT(1 2)", and Extended Well Known Binary,
+// which is what PostGIS uses for columns that enforce an SRID.
+// WithSRID(0, nil) can be used as a destination for scanning
+// any EWKT or EWKB geometry.
+func WithSRID(srid int, geom Geometry) Geometry {
+	return &sridGeometry{
+		Geometry: geom,
+		SRID:     srid,
+	}
+}
+
+// SRIDOf returns the spatial reference identifier of a geometry,
+// or 0 if the geometry does not have one.
+// The SRID is found under any other decorators, e.g. WithLayout and WithBBox.
+func SRIDOf(g Geometry) int {
+	for {
+		switch v := g.(type) {
+		case *sridGeometry:
+			return v.SRID
+		case *boundingBox:
+			g = v.Geometry
+		case *layoutGeometry:
+			g = v.Geometry
+		default:
+			return 0
+		}
+	}
+}
+
+// sridGeometry is a utility type for decorating geometries with an SRID.
+type sridGeometry struct {
+	Geometry
+
+	SRID int
+}
+
+// Equal compares two geometries that have SRIDs.
+func (sg *sridGeometry) Equal(g Geometry) bool {
+	other, ok := g.(*sridGeometry)
+	if !ok {
+		return false
+	}
+	if sg.SRID != other.SRID {
+		return false
+	}
+	if sg.Geometry == nil || other.Geometry == nil {
+		return sg.Geometry == nil && other.Geometry == nil
+	}
+	return sg.Geometry.Equal(other.Geometry)
+}
+
+// MarshalWKB returns the Extended Well Known Binary representation
+// of the geometry using the given byte order.
+func (sg *sridGeometry) MarshalWKB(order binary.ByteOrder) ([]byte, error) {
+	return marshalEWKB(sg.Geometry, sg.SRID, order)
+}
+
+// Scan scans the geometry from Extended Well Known Text or Extended Well Known Binary.
+// The wrapped geometry is replaced by the one that is scanned.
+func (sg *sridGeometry) Scan(src interface{}) error {
+	return scan(sg, src)
+}
+
+// scan scans the geometry from Extended Well Known Text.
+func (sg *sridGeometry) scan(s string) error {
+	g, srid, err := parseWKT(s)
+	if err != nil {
+		return err
+	}
+	sg.Geometry, sg.SRID = g, srid
+	return nil
+}
+
+// String returns the Extended Well Known Text representation of the geometry.
+// A nil geometry, e.g. before scanning into WithSRID(0, nil),
+// is written as an empty GEOMETRYCOLLECTION.
+func (sg *sridGeometry) String() string {
+	wkt := geometryCollectionWKTTag + " " + emptyWKTTag
+	if sg.Geometry != nil {
+		wkt = sg.Geometry.String()
+	}
+	if sg.SRID == 0 {
+		return wkt
+	}
+	return sridWKTTag + "=" + strconv.Itoa(sg.SRID) + ";" + wkt
+}
+
+// UnmarshalWKB unmarshals the geometry from Extended Well Known Binary.
+// The wrapped geometry is replaced by the one that is unmarshalled.
+func (sg *sridGeometry) UnmarshalWKB(data []byte) error {
+	g, srid, err := unmarshalWKB(data)
+	if err != nil {
+		return err
+	}
+	sg.Geometry, sg.SRID = g, srid
+	return nil
+}
+
+// Value returns Extended Well Known Text for the geometry.
+// A nil geometry is returned as SQL NULL.
+func (sg *sridGeometry) Value() (driver.Value, error) {
+	if sg.Geometry == nil {
+		return nil, nil
+	}
+	return sg.String(), nil
+}
@@ -0,0 +1,202 @@
+package geo
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// ewkbPoint is SRID=4326;POINT(1 2) as PostGIS encodes it.
+const ewkbPoint = "0101000020E6100000000000000000F03F0000000000000040"
+
+func TestSRIDScan(t *testing.T) {
+	// Pass
+	for i, testcase := range []struct {
+		Input    interface{}
+		Expected Geometry
+	}{
+		{
+			Input:    `SRID=4326;POINT(1 2)`,
+			Expected: WithSRID(4326, &Point{1, 2}),
+		},
+		{
+			Input:    []byte(`srid = 3857 ; LINESTRING(0 0, 1 1)`),
+			Expected: WithSRID(3857, &Line{{0, 0}, {1, 1}}),
+		},
+		{
+			Input:    ewkbPoint,
+			Expected: WithSRID(4326, &Point{1, 2}),
+		},
+		{
+			Input:    `POINT(1 2)`,
+			Expected: WithSRID(0, &Point{1, 2}),
+		},
+	} {
+		g := WithSRID(0, nil)
+		if err := g.Scan(testcase.Input); err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if expected := testcase.Expected; !expected.Equal(g) {
+			t.Fatalf("(case %d) expected %s, got %s", i, expected, g)
+		}
+	}
+
+	// Fail
+	for i, input := range []string{
+		`SRID=4326 POINT(1 2)`,
+		`SRID=;POINT(1 2)`,
+		`SRID=4.5;POINT(1 2)`,
+		`SRID:4326;POINT(1 2)`,
+		`SRID=4326;`,
+	} {
+		if _, err := ScanGeometry(input); err == nil {
+			t.Fatalf("(case %d) expected error, got nil", i)
+		}
+	}
+}
+
+func TestSRIDScanGeometry(t *testing.T) {
+	for i, input := range []string{
+		`SRID=4326;POINT(1 2)`,
+		ewkbPoint,
+	} {
+		g, err := ScanGeometry(input)
+		if err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if expected, got := 4326, SRIDOf(g); expected != got {
+			t.Fatalf("(case %d) expected SRID %d, got %d", i, expected, got)
+		}
+	}
+
+	// The typed Scan methods accept EWKT and EWKB but discard the SRID.
+	for i, input := range []interface{}{
+		`SRID=4326;POINT(1 2)`,
+		ewkbPoint,
+	} {
+		p := &Point{}
+		if err := p.Scan(input); err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if expected := (&Point{1, 2}); !expected.Equal(p) {
+			t.Fatalf("(case %d) expected %s, got %s", i, expected, p)
+		}
+	}
+
+	// A Feature keeps the SRID.
+	f := &Feature{}
+	if err := f.Scan(`SRID=4326;POINT(1 2)`); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := 4326, SRIDOf(f.Geometry); expected != got {
+		t.Fatalf("expected SRID %d, got %d", expected, got)
+	}
+}
+
+func TestSRIDEqual(t *testing.T) {
+	cases{
+		G: WithSRID(4326, &Point{1, 2}),
+		Same: []Geometry{
+			WithSRID(4326, &Point{1, 2}),
+		},
+		Different: []Geometry{
+			&Point{1, 2},
+			WithSRID(3857, &Point{1, 2}),
+			WithSRID(4326, &Point{2, 1}),
+		},
+	}.test(t)
+}
+
+func TestSRIDOf(t *testing.T) {
+	for i, testcase := range []struct {
+		Input    Geometry
+		Expected int
+	}{
+		{Input: &Point{1, 2}, Expected: 0},
+		{Input: WithSRID(4326, &Point{1, 2}), Expected: 4326},
+		{Input: WithBBox([]float64{1, 2, 1, 2}, WithSRID(4326, &Point{1, 2})), Expected: 4326},
+		{Input: WithLayout(XYZ, WithSRID(4326, &Point{1, 2})), Expected: 4326},
+		{Input: WithSRID(4326, WithLayout(XYZ, &Point{1, 2})), Expected: 4326},
+	} {
+		if expected, got := testcase.Expected, SRIDOf(testcase.Input); expected != got {
+			t.Fatalf("(case %d) expected %d, got %d", i, expected, got)
+		}
+	}
+}
+
+func TestSRIDValue(t *testing.T) {
+	valueTestcases{
+		{
+			Input:    WithSRID(4326, &Point{1, 2}),
+			Expected: `SRID=4326;POINT(1 2)`,
+		},
+		{
+			Input:    WithSRID(0, &Point{1, 2}),
+			Expected: `POINT(1 2)`,
+		},
+		{
+			Input:    WithLayout(XYZ, WithSRID(4326, &Point{1, 2})),
+			Expected: `SRID=4326;POINT Z (1 2 0)`,
+		},
+		{
+			Input:    WithSRID(0, nil),
+			Expected: nil,
+		},
+	}.pass(t)
+
+	if expected, got := `SRID=4326;GEOMETRYCOLLECTION EMPTY`, WithSRID(4326, nil).String(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestSRIDMarshalWKB(t *testing.T) {
+	g := WithSRID(4326, &Point{1, 2}).(wkbMarshaler)
+	data, err := g.MarshalWKB(binary.LittleEndian)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := strings.ToLower(ewkbPoint), hex.EncodeToString(data); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+
+	// Round trip in big endian.
+	data, err = g.MarshalWKB(binary.BigEndian)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := UnmarshalWKB(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.Equal(got) {
+		t.Fatalf("expected %s, got %s", g, got)
+	}
+
+	// 3D geometries use the PostGIS Z flag.
+	g = WithSRID(4326, &Point{1, 2, 3}).(wkbMarshaler)
+	if data, err = g.MarshalWKB(binary.LittleEndian); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := "01010000a0e6100000000000000000f03f00000000000000400000000000000840", hex.EncodeToString(data); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+
+	// The SRID is kept under a layout.
+	g = WithLayout(XY, WithSRID(4326, &Point{1, 2})).(wkbMarshaler)
+	if data, err = g.MarshalWKB(binary.LittleEndian); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := strings.ToLower(ewkbPoint), hex.EncodeToString(data); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+
+	// Members of a collection do not repeat the SRID.
+	gc := WithSRID(4326, &GeometryCollection{&Point{1, 2}}).(wkbMarshaler)
+	if data, err = gc.MarshalWKB(binary.LittleEndian); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := 9+4+21, len(data); expected != got {
+		t.Fatalf("expected %d bytes, got %d", expected, got)
+	}
+}
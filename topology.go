@@ -0,0 +1,84 @@
+package geo
+
+// The predicates in this file follow the definitions of the OGC Simple Features
+// specification, in terms of the intersection matrix of two geometries, see Relate.
+// They are Cartesian, like Locate, and treat collections as the union of their
+// members and a Circle as a polygon with 64 vertices on its circumference.
+// The points of a MultiPoint are separate points, not the vertices of a line.
+
+// Intersects returns true if the geometries have at least one point in common.
+func Intersects(a, b Geometry) bool {
+	return !Disjoint(a, b)
+}
+
+// Disjoint returns true if the geometries have no point in common.
+func Disjoint(a, b Geometry) bool {
+	m := Relate(a, b)
+	return m.get(Interior, Interior) < 0 && m.get(Interior, Boundary) < 0 &&
+		m.get(Boundary, Interior) < 0 && m.get(Boundary, Boundary) < 0
+}
+
+// Touches returns true if the geometries have at least one point in common,
+// but their interiors do not intersect.
+// Two points or MultiPoints never touch, since they have no boundaries.
+func Touches(a, b Geometry) bool {
+	m := Relate(a, b)
+	return m.get(Interior, Interior) < 0 &&
+		(m.get(Interior, Boundary) >= 0 || m.get(Boundary, Interior) >= 0 || m.get(Boundary, Boundary) >= 0)
+}
+
+// Crosses returns true if the interiors of the geometries intersect in
+// a geometry of lower dimension than the higher of their dimensions,
+// and each of them has a point outside the other.
+// It applies to points and lines, points and polygons, lines and polygons,
+// and to two lines that meet at points without sharing any part of their length.
+// It is false for other combinations, e.g. two polygons.
+func Crosses(a, b Geometry) bool {
+	var (
+		ga, gb     = newRelateGeometry(a), newRelateGeometry(b)
+		m          = relateGeometries(ga, gb)
+		dimA, dimB = ga.dimension(), gb.dimension()
+	)
+	switch {
+	case dimA < 0 || dimB < 0:
+		return false
+	case dimA < dimB:
+		return m.get(Interior, Interior) >= 0 && m.get(Interior, Exterior) >= 0
+	case dimA > dimB:
+		return m.get(Interior, Interior) >= 0 && m.get(Exterior, Interior) >= 0
+	case dimA == 1:
+		return m.get(Interior, Interior) == 0
+	}
+	return false
+}
+
+// Within returns true if every point of a is in b, and their interiors intersect.
+// A geometry is not within its own boundary, e.g. a line along the edge of a polygon.
+func Within(a, b Geometry) bool {
+	m := Relate(a, b)
+	return m.get(Interior, Interior) >= 0 && m.get(Interior, Exterior) < 0 && m.get(Boundary, Exterior) < 0
+}
+
+// Contains returns true if b is within a.
+// Unlike the Contains methods of the geometries, a polygon does not contain
+// a point on its boundary.
+func Contains(a, b Geometry) bool {
+	return Within(b, a)
+}
+
+// Overlaps returns true if the geometries have the same dimension,
+// their interiors intersect in a geometry of that dimension too,
+// and each of them has a point outside the other.
+// This is what is usually meant by two parcels of land overlapping:
+// parcels that only share a boundary, or where one is inside the other, do not overlap.
+func Overlaps(a, b Geometry) bool {
+	var (
+		ga, gb     = newRelateGeometry(a), newRelateGeometry(b)
+		m          = relateGeometries(ga, gb)
+		dimA, dimB = ga.dimension(), gb.dimension()
+	)
+	if dimA != dimB || dimA < 0 {
+		return false
+	}
+	return m.get(Interior, Interior) == dimA && m.get(Interior, Exterior) >= 0 && m.get(Exterior, Interior) >= 0
+}
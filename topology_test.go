@@ -0,0 +1,288 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// topologyTestcases is a helper type for tests of the spatial predicates.
+type topologyTestcases []struct {
+	A, B     Geometry
+	Expected []string
+}
+
+// test checks that exactly the expected predicates are true.
+func (tests topologyTestcases) test(t *testing.T) {
+	predicates := []struct {
+		Name string
+		F    func(a, b Geometry) bool
+	}{
+		{"Intersects", Intersects},
+		{"Disjoint", Disjoint},
+		{"Touches", Touches},
+		{"Crosses", Crosses},
+		{"Within", Within},
+		{"Contains", Contains},
+		{"Overlaps", Overlaps},
+	}
+	for i, testcase := range tests {
+		for _, predicate := range predicates {
+			expected := false
+			for _, name := range testcase.Expected {
+				expected = expected || name == predicate.Name
+			}
+			if got := predicate.F(testcase.A, testcase.B); expected != got {
+				t.Fatalf("(case %d) expected %s to be %t, got %t", i, predicate.Name, expected, got)
+			}
+		}
+	}
+}
+
+func TestTopologyPolygons(t *testing.T) {
+	square := &Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+
+	topologyTestcases{
+		{
+			A:        square,
+			B:        &Polygon{{{20, 0}, {30, 0}, {30, 10}, {20, 0}}},
+			Expected: []string{"Disjoint"},
+		},
+		{
+			// Parcels that share an edge.
+			A:        square,
+			B:        &Polygon{{{10, 0}, {20, 0}, {20, 10}, {10, 10}, {10, 0}}},
+			Expected: []string{"Intersects", "Touches"},
+		},
+		{
+			// Parcels that share part of an edge.
+			A:        square,
+			B:        &Polygon{{{10, 5}, {20, 5}, {20, 20}, {10, 20}, {10, 5}}},
+			Expected: []string{"Intersects", "Touches"},
+		},
+		{
+			// Touching at a corner.
+			A:        square,
+			B:        &Polygon{{{10, 10}, {20, 10}, {20, 20}, {10, 10}}},
+			Expected: []string{"Intersects", "Touches"},
+		},
+		{
+			A:        square,
+			B:        &Polygon{{{5, 5}, {15, 5}, {15, 15}, {5, 15}, {5, 5}}},
+			Expected: []string{"Intersects", "Overlaps"},
+		},
+		{
+			A:        &Polygon{{{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}}},
+			B:        square,
+			Expected: []string{"Intersects", "Within"},
+		},
+		{
+			// Inside, touching the boundary from within.
+			A:        &Polygon{{{0, 0}, {5, 0}, {5, 5}, {0, 5}, {0, 0}}},
+			B:        square,
+			Expected: []string{"Intersects", "Within"},
+		},
+		{
+			A:        square,
+			B:        &Polygon{{{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}}},
+			Expected: []string{"Intersects", "Contains"},
+		},
+		{
+			// Equal, with the rings wound in opposite directions.
+			A:        square,
+			B:        &Polygon{{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}}},
+			Expected: []string{"Intersects", "Within", "Contains"},
+		},
+		{
+			// Inside a hole.
+			A:        &Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}, {{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}}},
+			B:        &Polygon{{{3, 3}, {7, 3}, {7, 7}, {3, 7}, {3, 3}}},
+			Expected: []string{"Disjoint"},
+		},
+		{
+			// Filling a hole.
+			A:        &Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}, {{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}}},
+			B:        &Polygon{{{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}}},
+			Expected: []string{"Intersects", "Touches"},
+		},
+		{
+			// Crossing a hole.
+			A:        &Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}, {{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}}},
+			B:        &Polygon{{{3, 3}, {12, 3}, {12, 7}, {3, 7}, {3, 3}}},
+			Expected: []string{"Intersects", "Overlaps"},
+		},
+		{
+			A:        &MultiPolygon{{{{20, 20}, {30, 20}, {30, 30}, {20, 20}}}, {{{2, 2}, {8, 2}, {8, 8}, {2, 2}}}},
+			B:        square,
+			Expected: []string{"Intersects", "Overlaps"},
+		},
+		{
+			A:        square,
+			B:        &Circle{Coordinates: Point{5, 5}, Radius: 1000},
+			Expected: []string{"Intersects", "Contains"},
+		},
+	}.test(t)
+}
+
+func TestTopologyLines(t *testing.T) {
+	square := &Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+
+	topologyTestcases{
+		{
+			A:        &Line{{0, 0}, {10, 10}},
+			B:        &Line{{0, 10}, {10, 0}},
+			Expected: []string{"Intersects", "Crosses"},
+		},
+		{
+			// End to end.
+			A:        &Line{{0, 0}, {10, 10}},
+			B:        &Line{{10, 10}, {20, 0}},
+			Expected: []string{"Intersects", "Touches"},
+		},
+		{
+			// End on the other line.
+			A:        &Line{{0, 0}, {10, 10}},
+			B:        &Line{{5, 5}, {10, 0}},
+			Expected: []string{"Intersects", "Touches"},
+		},
+		{
+			A:        &Line{{0, 0}, {10, 0}},
+			B:        &Line{{5, 0}, {15, 0}},
+			Expected: []string{"Intersects", "Overlaps"},
+		},
+		{
+			A:        &Line{{2, 0}, {8, 0}},
+			B:        &Line{{0, 0}, {5, 0}, {10, 0}},
+			Expected: []string{"Intersects", "Within"},
+		},
+		{
+			A:        &Line{{0, 1}, {10, 1}},
+			B:        &Line{{0, 0}, {10, 0}},
+			Expected: []string{"Disjoint"},
+		},
+		{
+			// The ends of a closed line are not its boundary.
+			A:        &Line{{0, 0}, {10, 0}, {10, 10}, {0, 0}},
+			B:        &Line{{0, 0}, {-10, 0}},
+			Expected: []string{"Intersects", "Touches"},
+		},
+		{
+			A:        &Line{{-5, 5}, {5, 5}},
+			B:        square,
+			Expected: []string{"Intersects", "Crosses"},
+		},
+		{
+			A:        &Line{{2, 5}, {8, 5}},
+			B:        square,
+			Expected: []string{"Intersects", "Within"},
+		},
+		{
+			// Along the boundary.
+			A:        &Line{{0, 0}, {10, 0}},
+			B:        square,
+			Expected: []string{"Intersects", "Touches"},
+		},
+		{
+			A:        &Line{{-5, 0}, {-5, 10}},
+			B:        square,
+			Expected: []string{"Disjoint"},
+		},
+		{
+			// From the boundary inwards.
+			A:        &Line{{0, 5}, {5, 5}},
+			B:        square,
+			Expected: []string{"Intersects", "Within"},
+		},
+		{
+			A:        &MultiLine{{{-5, 5}, {-1, 5}}, {{2, 5}, {8, 5}}},
+			B:        square,
+			Expected: []string{"Intersects", "Crosses"},
+		},
+	}.test(t)
+}
+
+func TestTopologyPoints(t *testing.T) {
+	square := &Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+
+	topologyTestcases{
+		{A: &Point{1, 2}, B: &Point{1, 2}, Expected: []string{"Intersects", "Within", "Contains"}},
+		{A: &Point{1, 2}, B: &Point{2, 1}, Expected: []string{"Disjoint"}},
+		{A: &Point{5, 5}, B: square, Expected: []string{"Intersects", "Within"}},
+		{A: &Point{0, 5}, B: square, Expected: []string{"Intersects", "Touches"}},
+		{A: &Point{15, 5}, B: square, Expected: []string{"Disjoint"}},
+		{A: &Point{5, 0}, B: &Line{{0, 0}, {10, 0}}, Expected: []string{"Intersects", "Within"}},
+		{A: &Point{0, 0}, B: &Line{{0, 0}, {10, 0}}, Expected: []string{"Intersects", "Touches"}},
+		{A: &MultiPoint{{5, 5}, {15, 5}}, B: square, Expected: []string{"Intersects", "Crosses"}},
+		{A: &MultiPoint{{1, 1}, {2, 2}}, B: &MultiPoint{{2, 2}, {3, 3}}, Expected: []string{"Intersects", "Overlaps"}},
+		{A: &MultiPoint{{1, 1}, {2, 2}}, B: &MultiPoint{{2, 2}, {1, 1}, {3, 3}}, Expected: []string{"Intersects", "Within"}},
+	}.test(t)
+}
+
+func TestTopologyCollections(t *testing.T) {
+	var (
+		square = &Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+
+		// Two parcels that share the edge from (1, 0) to (1, 1).
+		left    = &Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}}
+		right   = &Polygon{{{1, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 0}}}
+		parcels = &FeatureCollection{{Geometry: left}, {Geometry: right}}
+	)
+
+	topologyTestcases{
+		{
+			A:        &GeometryCollection{&Point{5, 5}, &Line{{1, 1}, {2, 2}}},
+			B:        square,
+			Expected: []string{"Intersects", "Within"},
+		},
+		{
+			A:        &FeatureCollection{{Geometry: &Point{20, 20}}, {Geometry: &Point{5, 5}}},
+			B:        &Feature{Geometry: square},
+			Expected: []string{"Intersects", "Crosses"},
+		},
+		{
+			// Two halves of the square, which share an edge.
+			A: &GeometryCollection{
+				&Polygon{{{0, 0}, {5, 0}, {5, 10}, {0, 10}, {0, 0}}},
+				&Polygon{{{5, 0}, {10, 0}, {10, 10}, {5, 10}, {5, 0}}},
+			},
+			B:        WithSRID(4326, square),
+			Expected: []string{"Intersects", "Within", "Contains"},
+		},
+		{
+			// A line along the edge that the parcels share.
+			A:        &Line{{1, 0.25}, {1, 0.75}},
+			B:        &GeometryCollection{left, right},
+			Expected: []string{"Intersects", "Within"},
+		},
+		{
+			A:        parcels,
+			B:        &Line{{1, 0.25}, {1, 0.75}},
+			Expected: []string{"Intersects", "Contains"},
+		},
+		{
+			A:        &Point{1, 0.5},
+			B:        parcels,
+			Expected: []string{"Intersects", "Within"},
+		},
+		{
+			// A line along the edge of both parcels still touches them.
+			A:        &Line{{0.5, 0}, {1.5, 0}},
+			B:        parcels,
+			Expected: []string{"Intersects", "Touches"},
+		},
+		{
+			A:        parcels,
+			B:        &Polygon{{{0, 0}, {2, 0}, {2, 1}, {0, 1}, {0, 0}}},
+			Expected: []string{"Intersects", "Within", "Contains"},
+		},
+		{
+			A:        &GeometryCollection{},
+			B:        square,
+			Expected: []string{"Disjoint"},
+		},
+		{
+			A:        &Point{math.NaN(), math.NaN()},
+			B:        &Point{math.NaN(), math.NaN()},
+			Expected: []string{"Disjoint"},
+		},
+	}.test(t)
+}
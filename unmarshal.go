@@ -3,6 +3,8 @@ package geo
 import "encoding/json"
 
 // UnmarshalJSON unmarshals any GeoJSON type from a JSON blob.
+// Geometries keep the layout of their positions, see LayoutOf,
+// even for zero ordinates, e.g. a Point at [1, 2, 0].
 func UnmarshalJSON(data []byte) (Geometry, error) {
 	geom := &geometry{}
 	if err := json.Unmarshal(data, geom); err != nil {
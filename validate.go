@@ -0,0 +1,689 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ValidationReason is the reason that a geometry is not valid.
+type ValidationReason int
+
+// Validation reasons.
+const (
+	// InvalidCoordinate means a coordinate is NaN or infinite.
+	InvalidCoordinate ValidationReason = iota + 1
+
+	// TooFewPoints means a line has fewer than two distinct points,
+	// or a ring has fewer than four points or three distinct points.
+	TooFewPoints
+
+	// RingNotClosed means the last point of a ring is not its first point.
+	RingNotClosed
+
+	// RingSelfIntersection means a ring crosses or touches itself.
+	RingSelfIntersection
+
+	// RingsIntersect means two rings of a polygon cross each other
+	// or share part of an edge.
+	RingsIntersect
+
+	// HoleOutsideShell means a hole is not inside the exterior ring of its polygon.
+	HoleOutsideShell
+
+	// NestedHoles means a hole is inside another hole of the same polygon.
+	NestedHoles
+
+	// DisconnectedInterior means the rings of a polygon touch in a way
+	// that splits its interior into more than one piece.
+	DisconnectedInterior
+
+	// OverlappingPolygons means the interiors of two polygons of a MultiPolygon
+	// intersect, or their boundaries share part of an edge.
+	OverlappingPolygons
+
+	// InvalidRadius means the radius of a circle is negative or not finite.
+	InvalidRadius
+
+	// InvalidBBox means a bounding box is not valid, see NewEnvelope.
+	InvalidBBox
+)
+
+// String returns a description of the reason.
+func (reason ValidationReason) String() string {
+	switch reason {
+	case InvalidCoordinate:
+		return "invalid coordinate"
+	case TooFewPoints:
+		return "too few points"
+	case RingNotClosed:
+		return "ring not closed"
+	case RingSelfIntersection:
+		return "ring self-intersection"
+	case RingsIntersect:
+		return "rings intersect"
+	case HoleOutsideShell:
+		return "hole outside shell"
+	case NestedHoles:
+		return "nested holes"
+	case DisconnectedInterior:
+		return "disconnected interior"
+	case OverlappingPolygons:
+		return "overlapping polygons"
+	case InvalidRadius:
+		return "invalid radius"
+	case InvalidBBox:
+		return "invalid bbox"
+	}
+	return "unknown reason"
+}
+
+// ValidationError describes one problem with a geometry.
+// Indexes that do not apply to the problem are -1.
+type ValidationError struct {
+	Reason ValidationReason
+
+	// Member is the index of the member of a GeometryCollection or
+	// FeatureCollection that has the problem.
+	// For nested collections it is the index in the outermost collection.
+	Member int
+
+	// Part is the index of the polygon in a MultiPolygon,
+	// or of the line in a MultiLine, or of the point in a MultiPoint.
+	// For OverlappingPolygons it is the index of the first polygon.
+	Part int
+
+	// Ring is the index of the ring in the polygon, where 0 is the exterior ring.
+	// For RingsIntersect and NestedHoles it is the index of the first ring
+	// and Other is the index of the second one.
+	// For OverlappingPolygons Other is the index of the second polygon.
+	Ring  int
+	Other int
+
+	// Vertex is the index of the vertex in the line or ring
+	// where the problem was found.
+	// For intersections it is the index of the start of the segment.
+	Vertex int
+
+	// Point is where the problem was found, if it has a location.
+	Point Point
+}
+
+// Error returns a description of the problem.
+func (e ValidationError) Error() string {
+	var where []string
+	for _, index := range []struct {
+		Name  string
+		Value int
+	}{
+		{Name: "member", Value: e.Member},
+		{Name: "part", Value: e.Part},
+		{Name: "ring", Value: e.Ring},
+		{Name: "other", Value: e.Other},
+		{Name: "vertex", Value: e.Vertex},
+	} {
+		if index.Value >= 0 {
+			where = append(where, fmt.Sprintf("%s %d", index.Name, index.Value))
+		}
+	}
+	s := e.Reason.String()
+	if len(where) > 0 {
+		s += " (" + strings.Join(where, ", ") + ")"
+	}
+	if !e.Point.IsEmpty() {
+		s += " at " + e.Point.String()
+	}
+	return s
+}
+
+// ValidationErrors is a list of the problems with a geometry.
+type ValidationErrors []ValidationError
+
+// Error returns a description of every problem.
+func (errs ValidationErrors) Error() string {
+	s := make([]string, len(errs))
+	for i, err := range errs {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, "; ")
+}
+
+// Validate returns the problems that make a geometry invalid according to the
+// OGC Simple Features specification, or nil if the geometry is valid.
+//
+// Coordinates must be finite, lines must have at least two distinct points,
+// and rings must be closed and have at least four points.
+// The rings of a polygon must not cross themselves or each other,
+// although different rings may touch at single points
+// as long as the interior of the polygon stays connected.
+// Holes must be inside the exterior ring and must not be nested.
+// The polygons of a MultiPolygon must not overlap, and may only touch at points.
+// Lines may cross themselves, and repeated consecutive points are allowed.
+// Empty geometries are valid.
+func Validate(g Geometry) ValidationErrors {
+	var errs ValidationErrors
+	switch v := g.(type) {
+	case *Point:
+		errs = validatePoint(*v)
+	case *MultiPoint:
+		for i, p := range *v {
+			errs = append(errs, setValidationIndex(validatePoint(p), func(e *ValidationError) { e.Part = i })...)
+		}
+	case *Line:
+		errs = validateLine(*v)
+	case *MultiLine:
+		for i, line := range *v {
+			errs = append(errs, setValidationIndex(validateLine(line), func(e *ValidationError) { e.Part = i })...)
+		}
+	case *Polygon:
+		errs = validatePolygon(*v)
+	case *MultiPolygon:
+		errs = validateMultiPolygon(*v)
+	case *Circle:
+		errs = validatePoint(v.Coordinates)
+		if r := v.Radius; r < 0 || math.IsNaN(r) || math.IsInf(r, 0) {
+			errs = append(errs, newValidationError(InvalidRadius, -1, -1, emptyPoint()))
+		}
+	case *Feature:
+		if v.Geometry != nil {
+			errs = Validate(v.Geometry)
+		}
+	case *GeometryCollection:
+		for i, member := range *v {
+			errs = append(errs, setValidationIndex(Validate(member), func(e *ValidationError) { e.Member = i })...)
+		}
+	case *FeatureCollection:
+		for i, feat := range *v {
+			errs = append(errs, setValidationIndex(Validate(feat), func(e *ValidationError) { e.Member = i })...)
+		}
+	case *boundingBox:
+		if !v.computed {
+			if _, err := NewEnvelope(v.Box); err != nil {
+				errs = append(errs, newValidationError(InvalidBBox, -1, -1, emptyPoint()))
+			}
+		}
+		errs = append(errs, Validate(v.Geometry)...)
+	case *sridGeometry:
+		errs = Validate(v.Geometry)
+	case *layoutGeometry:
+		errs = Validate(v.Geometry)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// newValidationError returns a validation error with the given ring and vertex
+// and no other indexes.
+func newValidationError(reason ValidationReason, ring, vertex int, p Point) ValidationError {
+	return ValidationError{
+		Reason: reason,
+		Member: -1,
+		Part:   -1,
+		Ring:   ring,
+		Other:  -1,
+		Vertex: vertex,
+		Point:  p,
+	}
+}
+
+// setValidationIndex calls f for every error and returns the errors.
+func setValidationIndex(errs ValidationErrors, f func(e *ValidationError)) ValidationErrors {
+	for i := range errs {
+		f(&errs[i])
+	}
+	return errs
+}
+
+// emptyPoint returns an empty point.
+func emptyPoint() Point {
+	return Point{math.NaN(), math.NaN()}
+}
+
+// validatePoints checks that every point has finite coordinates.
+// Each error has the index of the point as its vertex.
+func validatePoints(points [][4]float64, ring int) ValidationErrors {
+	var errs ValidationErrors
+	for i, p := range points {
+		for _, x := range p {
+			if math.IsNaN(x) || math.IsInf(x, 0) {
+				errs = append(errs, newValidationError(InvalidCoordinate, ring, i, emptyPoint()))
+				break
+			}
+		}
+	}
+	return errs
+}
+
+// validatePoint checks that a point is empty or has finite coordinates.
+func validatePoint(point Point) ValidationErrors {
+	if point.IsEmpty() {
+		return nil
+	}
+	return setValidationIndex(validatePoints([][4]float64{point}, -1), func(e *ValidationError) { e.Vertex = -1 })
+}
+
+// validateLine checks that a line has finite coordinates and at least two distinct points.
+func validateLine(line [][4]float64) ValidationErrors {
+	if len(line) == 0 {
+		return nil
+	}
+	errs := validatePoints(line, -1)
+	if len(errs) > 0 {
+		return errs
+	}
+	if len(dedupe(line)) < 2 {
+		return ValidationErrors{newValidationError(TooFewPoints, -1, -1, emptyPoint())}
+	}
+	return nil
+}
+
+// validatePolygon checks the rings of a polygon and how they are arranged.
+func validatePolygon(polygon [][][4]float64) ValidationErrors {
+	var errs ValidationErrors
+	for i, ring := range polygon {
+		errs = append(errs, validateRing(ring, i)...)
+	}
+	if len(errs) > 0 || len(polygon) == 0 {
+		// The arrangement of the rings only makes sense if every ring is valid.
+		return errs
+	}
+	var (
+		shell = polygon[0]
+		graph = newTouchGraph()
+	)
+	for i := 1; i < len(polygon); i++ {
+		hole := polygon[i]
+
+		// Holes must be inside the shell.
+		switch inside, outside := ringLocations(hole, shell); {
+		case inside && outside:
+			errs = append(errs, ringsIntersectError(0, i))
+			continue
+		case outside:
+			errs = append(errs, newValidationError(HoleOutsideShell, i, -1, emptyPoint()))
+			continue
+		}
+		// Holes must not be inside each other.
+		for j := 1; j < i; j++ {
+			other := polygon[j]
+			inside, outside := ringLocations(hole, other)
+			if !inside {
+				inside, outside = ringLocations(other, hole)
+			}
+			switch {
+			case inside && outside:
+				errs = append(errs, ringsIntersectError(j, i))
+			case inside:
+				e := newValidationError(NestedHoles, j, -1, emptyPoint())
+				e.Other = i
+				errs = append(errs, e)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	// Rings must not cross, and where they touch they must not disconnect the interior.
+	for i := range polygon {
+		for j := i + 1; j < len(polygon); j++ {
+			if e, ok := intersectRings(polygon[i], polygon[j], i, j, graph); !ok {
+				errs = append(errs, e)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	if p, ok := graph.disconnected(); ok {
+		errs = append(errs, newValidationError(DisconnectedInterior, -1, -1, p))
+	}
+	return errs
+}
+
+// validateRing checks that a ring has finite coordinates, is closed,
+// has enough points and does not intersect itself.
+func validateRing(ring [][4]float64, index int) ValidationErrors {
+	if errs := validatePoints(ring, index); len(errs) > 0 {
+		return errs
+	}
+	if len(ring) < 4 {
+		return ValidationErrors{newValidationError(TooFewPoints, index, -1, emptyPoint())}
+	}
+	first, last := ring[0], ring[len(ring)-1]
+	if first[0] != last[0] || first[1] != last[1] {
+		return ValidationErrors{newValidationError(RingNotClosed, index, len(ring)-1, Point(last))}
+	}
+	vertices := dedupe(ring)
+	if len(vertices) < 4 {
+		return ValidationErrors{newValidationError(TooFewPoints, index, -1, emptyPoint())}
+	}
+	if e, ok := selfIntersection(ring, index); !ok {
+		return ValidationErrors{e}
+	}
+	return nil
+}
+
+// selfIntersection checks whether a closed ring crosses or touches itself.
+func selfIntersection(ring [][4]float64, index int) (ValidationError, bool) {
+	var (
+		segs  = ringSegments(ring, index)
+		n     = len(segs)
+		found *ValidationError
+	)
+	sweepSegments(segs, func(s, t segment) bool {
+		if s.index > t.index {
+			s, t = t, s
+		}
+		points := segmentIntersection(s.a, s.b, t.a, t.b)
+		k, l := s.seq, t.seq
+		adjacent := l == k+1 || (k == 0 && l == n-1)
+		if len(points) == 0 || adjacent && len(points) == 1 {
+			return true
+		}
+		e := newValidationError(RingSelfIntersection, index, t.index, Point(points[0]))
+		found = &e
+		return false
+	})
+	if found != nil {
+		return *found, false
+	}
+	return ValidationError{}, true
+}
+
+// ringLocations reports whether any vertex of ring, or the midpoint of any of its edges,
+// is inside or outside of other.
+// Points on the boundary of other are ignored.
+func ringLocations(ring, other [][4]float64) (inside, outside bool) {
+	for i, p := range ring {
+		points := []Point{p}
+		if i > 0 {
+			q := ring[i-1]
+			points = append(points, Point{(p[0] + q[0]) / 2, (p[1] + q[1]) / 2})
+		}
+		for _, point := range points {
+			switch locateInRing(other, point) {
+			case Interior:
+				inside = true
+			case Exterior:
+				outside = true
+			}
+		}
+	}
+	return inside, outside
+}
+
+// ringsIntersectError returns the error for two rings that cross.
+func ringsIntersectError(ring, other int) ValidationError {
+	e := newValidationError(RingsIntersect, ring, -1, emptyPoint())
+	e.Other = other
+	return e
+}
+
+// intersectRings finds where two rings of a polygon meet.
+// Points where they touch are added to the graph,
+// and an error is returned if they cross or share part of an edge.
+func intersectRings(r1, r2 [][4]float64, i, j int, graph *touchGraph) (ValidationError, bool) {
+	var (
+		segs  = append(ringSegments(r1, i), ringSegments(r2, j)...)
+		found *ValidationError
+	)
+	sweepSegments(segs, func(s, t segment) bool {
+		if s.ring == t.ring {
+			return true
+		}
+		if s.ring > t.ring {
+			s, t = t, s
+		}
+		points := segmentIntersection(s.a, s.b, t.a, t.b)
+		switch len(points) {
+		case 0:
+			return true
+		case 1:
+			p := points[0]
+			if isEndpoint(s, p) || isEndpoint(t, p) {
+				graph.touch(i, j, p)
+				return true
+			}
+		}
+		e := newValidationError(RingsIntersect, i, s.index, Point(points[0]))
+		e.Other = j
+		found = &e
+		return false
+	})
+	if found != nil {
+		return *found, false
+	}
+	return ValidationError{}, true
+}
+
+// validateMultiPolygon checks each polygon and that the polygons do not overlap.
+func validateMultiPolygon(mp MultiPolygon) ValidationErrors {
+	var errs ValidationErrors
+	for i, poly := range mp {
+		errs = append(errs, setValidationIndex(validatePolygon(poly), func(e *ValidationError) { e.Part = i })...)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	bounds := make([]Envelope, len(mp))
+	for i, poly := range mp {
+		bounds[i] = Polygon(poly).Bounds()
+	}
+	for i := range mp {
+		for j := i + 1; j < len(mp); j++ {
+			if !bounds[i].Intersects(bounds[j]) {
+				continue
+			}
+			if p, ok := polygonsOverlap(mp[i], mp[j]); ok {
+				e := newValidationError(OverlappingPolygons, -1, -1, p)
+				e.Part, e.Other = i, j
+				errs = append(errs, e)
+			}
+		}
+	}
+	return errs
+}
+
+// polygonsOverlap returns a point where the interiors of two valid polygons intersect,
+// or where their boundaries share part of an edge.
+func polygonsOverlap(p1, p2 [][][4]float64) (Point, bool) {
+	if len(p1) == 0 || len(p2) == 0 {
+		return Point{}, false
+	}
+	var segs []segment
+	for i, ring := range p1 {
+		segs = append(segs, ringSegments(ring, i)...)
+	}
+	for i, ring := range p2 {
+		segs = append(segs, ringSegments(ring, len(p1)+i)...)
+	}
+	var (
+		at    Point
+		found bool
+	)
+	sweepSegments(segs, func(s, t segment) bool {
+		if (s.ring < len(p1)) == (t.ring < len(p1)) {
+			return true
+		}
+		points := segmentIntersection(s.a, s.b, t.a, t.b)
+		switch len(points) {
+		case 0:
+			return true
+		case 1:
+			p := points[0]
+			if isEndpoint(s, p) || isEndpoint(t, p) {
+				return true
+			}
+		}
+		at, found = Point(points[0]), true
+		return false
+	})
+	if found {
+		return at, true
+	}
+	// The boundaries do not cross, so check whether either polygon is inside the other.
+	for _, pair := range [][2][][][4]float64{{p1, p2}, {p2, p1}} {
+		for i, p := range pair[0][0] {
+			points := []Point{p}
+			if i > 0 {
+				q := pair[0][0][i-1]
+				points = append(points, Point{(p[0] + q[0]) / 2, (p[1] + q[1]) / 2})
+			}
+			for _, point := range points {
+				if Polygon(pair[1]).Locate(point) == Interior {
+					return point, true
+				}
+			}
+		}
+	}
+	return Point{}, false
+}
+
+// dedupe returns the points without repeated consecutive points.
+func dedupe(points [][4]float64) [][4]float64 {
+	deduped := make([][4]float64, 0, len(points))
+	for _, p := range points {
+		if n := len(deduped); n > 0 && deduped[n-1][0] == p[0] && deduped[n-1][1] == p[1] {
+			continue
+		}
+		deduped = append(deduped, p)
+	}
+	return deduped
+}
+
+// segment is an edge of a line or ring.
+type segment struct {
+	a, b [4]float64
+
+	// ring identifies the line or ring that the segment belongs to,
+	// index is the index of a in it, and seq is the position of the segment
+	// in the ring after repeated points have been removed.
+	ring, index, seq int
+}
+
+// isEndpoint returns true if p is one of the ends of the segment.
+func isEndpoint(s segment, p [4]float64) bool {
+	return (p[0] == s.a[0] && p[1] == s.a[1]) || (p[0] == s.b[0] && p[1] == s.b[1])
+}
+
+// ringSegments returns the segments of a line or ring, without segments of zero length.
+func ringSegments(ring [][4]float64, id int) []segment {
+	segs := make([]segment, 0, len(ring))
+	for i := 1; i < len(ring); i++ {
+		a, b := ring[i-1], ring[i]
+		if a[0] == b[0] && a[1] == b[1] {
+			continue
+		}
+		segs = append(segs, segment{a: a, b: b, ring: id, index: i - 1, seq: len(segs)})
+	}
+	return segs
+}
+
+// sweepSegments calls f for every pair of segments whose envelopes overlap,
+// until f returns false.
+// It sweeps a vertical line across the segments from west to east,
+// keeping track of the segments that cross it.
+func sweepSegments(segs []segment, f func(s, t segment) bool) {
+	type event struct {
+		minX, maxX float64
+		seg        segment
+	}
+	events := make([]event, len(segs))
+	for i, s := range segs {
+		events[i] = event{minX: math.Min(s.a[0], s.b[0]), maxX: math.Max(s.a[0], s.b[0]), seg: s}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].minX < events[j].minX
+	})
+	var active []event
+	for _, e := range events {
+		// Drop the segments that end before this one starts.
+		n := 0
+		for _, a := range active {
+			if a.maxX >= e.minX {
+				active[n] = a
+				n++
+			}
+		}
+		active = active[:n]
+
+		var (
+			minY = math.Min(e.seg.a[1], e.seg.b[1])
+			maxY = math.Max(e.seg.a[1], e.seg.b[1])
+		)
+		for _, a := range active {
+			if math.Max(a.seg.a[1], a.seg.b[1]) < minY || math.Min(a.seg.a[1], a.seg.b[1]) > maxY {
+				continue
+			}
+			if !f(a.seg, e.seg) {
+				return
+			}
+		}
+		active = append(active, e)
+	}
+}
+
+// touchGraph records the points where the rings of a polygon touch.
+// The interior of the polygon is disconnected if the rings and the points
+// where they touch form a cycle.
+type touchGraph struct {
+	points map[[2]float64]int
+	edges  map[[2]int]bool
+	order  [][2]int
+	at     map[[2]int][4]float64
+}
+
+// newTouchGraph returns an empty graph.
+func newTouchGraph() *touchGraph {
+	return &touchGraph{
+		points: map[[2]float64]int{},
+		edges:  map[[2]int]bool{},
+		at:     map[[2]int][4]float64{},
+	}
+}
+
+// touch records that rings i and j touch at p.
+func (g *touchGraph) touch(i, j int, p [4]float64) {
+	key := [2]float64{p[0], p[1]}
+	id, ok := g.points[key]
+	if !ok {
+		id = len(g.points)
+		g.points[key] = id
+	}
+	for _, ring := range []int{i, j} {
+		// Rings are numbered from 0 and points from -1 downwards.
+		edge := [2]int{ring, -1 - id}
+		if !g.edges[edge] {
+			g.edges[edge] = true
+			g.order = append(g.order, edge)
+			g.at[edge] = p
+		}
+	}
+}
+
+// disconnected returns a point on a cycle in the graph, if there is one.
+func (g *touchGraph) disconnected() (Point, bool) {
+	parent := map[int]int{}
+	var find func(x int) int
+	find = func(x int) int {
+		p, ok := parent[x]
+		if !ok || p == x {
+			parent[x] = x
+			return x
+		}
+		root := find(p)
+		parent[x] = root
+		return root
+	}
+	for _, edge := range g.order {
+		a, b := find(edge[0]), find(edge[1])
+		if a == b {
+			return Point(g.at[edge]), true
+		}
+		parent[a] = b
+	}
+	return Point{}, false
+}
@@ -0,0 +1,238 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// validateTestcases is a helper type for Validate tests.
+type validateTestcases []struct {
+	G        Geometry
+	Expected []ValidationReason
+}
+
+// test checks that Validate finds the expected problems, in order.
+func (tests validateTestcases) test(t *testing.T) {
+	for i, testcase := range tests {
+		errs := Validate(testcase.G)
+		if expected, got := len(testcase.Expected), len(errs); expected != got {
+			t.Fatalf("(case %d) expected %d errors, got %d (%v)", i, expected, got, errs)
+		}
+		for j, err := range errs {
+			if expected, got := testcase.Expected[j], err.Reason; expected != got {
+				t.Fatalf("(case %d) expected %s, got %s", i, expected, got)
+			}
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	var (
+		nan    = math.NaN()
+		inf    = math.Inf(1)
+		square = [][4]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}
+	)
+	validateTestcases{
+		{G: &Point{1, 2}},
+		{G: &Point{nan, nan}},
+		{G: &Point{inf, 2}, Expected: []ValidationReason{InvalidCoordinate}},
+		{G: &MultiPoint{{1, 2}, {1, inf}, {3, 4}}, Expected: []ValidationReason{InvalidCoordinate}},
+		{G: &Line{}},
+		{G: &Line{{0, 0}, {1, 1}, {0, 1}, {1, 0}}},
+		{G: &Line{{0, 0}, {0, 0}}, Expected: []ValidationReason{TooFewPoints}},
+		{G: &Line{{0, 0}, {nan, 0}}, Expected: []ValidationReason{InvalidCoordinate}},
+		{G: &MultiLine{{{0, 0}, {1, 1}}, {{2, 2}}}, Expected: []ValidationReason{TooFewPoints}},
+		{G: &Polygon{}},
+		{G: &Polygon{square}},
+		{
+			// Repeated points are allowed.
+			G: &Polygon{{{0, 0}, {10, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}},
+		},
+		{
+			G:        &Polygon{{{0, 0}, {10, 0}, {0, 0}}},
+			Expected: []ValidationReason{TooFewPoints},
+		},
+		{
+			G:        &Polygon{{{0, 0}, {10, 0}, {0, 0}, {0, 0}}},
+			Expected: []ValidationReason{TooFewPoints},
+		},
+		{
+			G:        &Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			Expected: []ValidationReason{RingNotClosed},
+		},
+		{
+			// Bowtie.
+			G:        &Polygon{{{0, 0}, {10, 10}, {10, 0}, {0, 10}, {0, 0}}},
+			Expected: []ValidationReason{RingSelfIntersection},
+		},
+		{
+			// Spike.
+			G:        &Polygon{{{0, 0}, {10, 0}, {10, 10}, {10, 20}, {10, 10}, {0, 10}, {0, 0}}},
+			Expected: []ValidationReason{RingSelfIntersection},
+		},
+		{
+			// Ring that touches itself.
+			G:        &Polygon{{{0, 0}, {10, 0}, {10, 10}, {5, 0}, {0, 10}, {0, 0}}},
+			Expected: []ValidationReason{RingSelfIntersection},
+		},
+		{
+			G: &Polygon{square, {{2, 2}, {2, 4}, {4, 4}, {4, 2}, {2, 2}}, {{6, 6}, {6, 8}, {8, 8}, {8, 6}, {6, 6}}},
+		},
+		{
+			// Hole touching the shell at a point.
+			G: &Polygon{square, {{0, 5}, {5, 8}, {5, 2}, {0, 5}}},
+		},
+		{
+			// Holes touching each other at a point.
+			G: &Polygon{square, {{2, 2}, {2, 5}, {5, 5}, {5, 2}, {2, 2}}, {{5, 5}, {5, 8}, {8, 8}, {8, 5}, {5, 5}}},
+		},
+		{
+			G:        &Polygon{square, {{20, 20}, {20, 30}, {30, 30}, {20, 20}}},
+			Expected: []ValidationReason{HoleOutsideShell},
+		},
+		{
+			G:        &Polygon{square, {{5, 5}, {5, 15}, {15, 15}, {5, 5}}},
+			Expected: []ValidationReason{RingsIntersect},
+		},
+		{
+			// Hole sharing an edge with the shell.
+			G:        &Polygon{square, {{0, 2}, {5, 2}, {5, 4}, {0, 4}, {0, 2}}},
+			Expected: []ValidationReason{RingsIntersect},
+		},
+		{
+			G:        &Polygon{square, {{1, 1}, {1, 9}, {9, 9}, {9, 1}, {1, 1}}, {{2, 2}, {2, 4}, {4, 4}, {4, 2}, {2, 2}}},
+			Expected: []ValidationReason{NestedHoles},
+		},
+		{
+			// Hole touching the shell at two points, which cuts the polygon in two.
+			G:        &Polygon{square, {{0, 5}, {5, 10}, {5, 0}, {0, 5}}},
+			Expected: []ValidationReason{DisconnectedInterior},
+		},
+		{
+			// Holes touching each other and the shell in a cycle.
+			G: &Polygon{
+				square,
+				{{0, 5}, {5, 5}, {2, 8}, {0, 5}},
+				{{5, 5}, {5, 0}, {8, 3}, {5, 5}},
+			},
+			Expected: []ValidationReason{DisconnectedInterior},
+		},
+		{
+			G:        &MultiPolygon{{square}, {{{5, 0}, {20, 0}, {20, 10}, {5, 0}}}},
+			Expected: []ValidationReason{OverlappingPolygons},
+		},
+		{
+			// Touching at a point.
+			G: &MultiPolygon{{square}, {{{10, 10}, {20, 10}, {20, 20}, {10, 10}}}},
+		},
+		{
+			// Sharing an edge.
+			G:        &MultiPolygon{{square}, {{{10, 0}, {20, 0}, {20, 10}, {10, 10}, {10, 0}}}},
+			Expected: []ValidationReason{OverlappingPolygons},
+		},
+		{
+			G:        &MultiPolygon{{square}, {{{5, 5}, {15, 5}, {15, 15}, {5, 5}}}},
+			Expected: []ValidationReason{OverlappingPolygons},
+		},
+		{
+			// Nested.
+			G:        &MultiPolygon{{square}, {{{2, 2}, {8, 2}, {8, 8}, {2, 2}}}},
+			Expected: []ValidationReason{OverlappingPolygons},
+		},
+		{
+			// Inside a hole.
+			G: &MultiPolygon{
+				{square, {{2, 2}, {2, 8}, {8, 8}, {8, 2}, {2, 2}}},
+				{{{3, 3}, {7, 3}, {7, 7}, {3, 3}}},
+			},
+		},
+		{
+			// Identical.
+			G:        &MultiPolygon{{square}, {square}},
+			Expected: []ValidationReason{OverlappingPolygons},
+		},
+		{G: &Circle{Coordinates: Point{1, 2}, Radius: 10}},
+		{G: &Circle{Coordinates: Point{1, 2}, Radius: -1}, Expected: []ValidationReason{InvalidRadius}},
+		{
+			G: &GeometryCollection{
+				&Point{1, 2},
+				&Polygon{{{0, 0}, {10, 0}, {10, 10}}},
+				&GeometryCollection{&Line{{1, 1}}},
+			},
+			Expected: []ValidationReason{TooFewPoints, TooFewPoints},
+		},
+		{
+			G: &FeatureCollection{
+				{Geometry: &Point{1, 2}},
+				{Geometry: &Point{inf, 2}},
+				{},
+			},
+			Expected: []ValidationReason{InvalidCoordinate},
+		},
+		{G: WithSRID(4326, &Point{inf, 2}), Expected: []ValidationReason{InvalidCoordinate}},
+		{G: WithBBox([]float64{1, 2, 1, 2}, &Point{1, 2})},
+		{G: WithBBox([]float64{1, 2}, &Point{1, 2}), Expected: []ValidationReason{InvalidBBox}},
+		{G: WithComputedBBox(&Point{1, 2})},
+	}.test(t)
+}
+
+func TestValidateIndexes(t *testing.T) {
+	for i, testcase := range []struct {
+		G        Geometry
+		Expected ValidationError
+	}{
+		{
+			G:        &Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}, {{2, 2}, {4, 2}, {4, 4}, {2, 4}}},
+			Expected: ValidationError{Reason: RingNotClosed, Member: -1, Part: -1, Ring: 1, Other: -1, Vertex: 3, Point: Point{2, 4}},
+		},
+		{
+			G:        &Polygon{{{0, 0}, {10, 10}, {10, 0}, {0, 10}, {0, 0}}},
+			Expected: ValidationError{Reason: RingSelfIntersection, Member: -1, Part: -1, Ring: 0, Other: -1, Vertex: 2, Point: Point{5, 5}},
+		},
+		{
+			G:        &MultiLine{{{0, 0}, {1, 1}}, {{0, 0}, {1, math.Inf(1)}}},
+			Expected: ValidationError{Reason: InvalidCoordinate, Member: -1, Part: 1, Ring: -1, Other: -1, Vertex: 1, Point: Point{math.NaN(), math.NaN()}},
+		},
+		{
+			G: &GeometryCollection{
+				&Point{},
+				&GeometryCollection{
+					&MultiPolygon{
+						{{{0, 0}, {10, 0}, {10, 10}, {0, 0}}},
+						{{{0, 0}, {10, 0}, {10, 10}, {0, 0}}, {{20, 20}, {21, 20}, {21, 21}, {20, 20}}},
+					},
+				},
+			},
+			Expected: ValidationError{Reason: HoleOutsideShell, Member: 1, Part: 1, Ring: 1, Other: -1, Vertex: -1, Point: Point{math.NaN(), math.NaN()}},
+		},
+		{
+			G:        &MultiPolygon{{{{0, 0}, {10, 0}, {10, 10}, {0, 0}}}, {{{5, 0}, {15, 0}, {15, 10}, {5, 0}}}},
+			Expected: ValidationError{Reason: OverlappingPolygons, Member: -1, Part: 0, Ring: -1, Other: 1, Vertex: -1, Point: Point{10, 0}},
+		},
+	} {
+		errs := Validate(testcase.G)
+		if len(errs) != 1 {
+			t.Fatalf("(case %d) expected 1 error, got %v", i, errs)
+		}
+		expected, got := testcase.Expected, errs[0]
+		if expected.Point.IsEmpty() && got.Point.IsEmpty() {
+			expected.Point, got.Point = Point{}, Point{}
+		}
+		if expected != got {
+			t.Fatalf("(case %d) expected %#v, got %#v", i, expected, got)
+		}
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	errs := Validate(&GeometryCollection{
+		&Polygon{{{0, 0}, {10, 10}, {10, 0}, {0, 10}, {0, 0}}},
+		&Circle{Radius: math.Inf(1)},
+	})
+	if expected, got := "ring self-intersection (member 0, ring 0, vertex 2) at POINT(5 5); invalid radius (member 1)", errs.Error(); expected != got {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+	if errs := Validate(&Point{1, 2}); errs != nil {
+		t.Fatalf("expected nil, got %v", errs)
+	}
+}
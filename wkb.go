@@ -0,0 +1,538 @@
+package geo
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+)
+
+// Well Known Binary byte orders.
+const (
+	wkbXDR byte = 0 // big endian
+	wkbNDR byte = 1 // little endian
+)
+
+// Well Known Binary geometry type codes.
+const (
+	wkbPoint              uint32 = 1
+	wkbLine               uint32 = 2
+	wkbPolygon            uint32 = 3
+	wkbMultiPoint         uint32 = 4
+	wkbMultiLine          uint32 = 5
+	wkbMultiPolygon       uint32 = 6
+	wkbGeometryCollection uint32 = 7
+)
+
+// Well Known Binary type codes are offset by these amounts
+// for geometries with Z and M ordinates, e.g. 3001 is a POINT ZM.
+const (
+	wkbZ uint32 = 1000
+	wkbM uint32 = 2000
+)
+
+// Extended Well Known Binary flags, as used by PostGIS.
+const (
+	ewkbZ    uint32 = 0x80000000
+	ewkbM    uint32 = 0x40000000
+	ewkbSRID uint32 = 0x20000000
+)
+
+// wkbUnmarshaler is implemented by types that can be decoded from Well Known Binary.
+type wkbUnmarshaler interface {
+	UnmarshalWKB(data []byte) error
+}
+
+// UnmarshalWKB decodes any geometry from Well Known Binary
+// or Extended Well Known Binary. Both byte orders are supported.
+// If the data has an SRID the geometry is wrapped with WithSRID,
+// The geometry keeps its layout, see LayoutOf, even for zero ordinates,
+// e.g. a POINT Z at Z=0, and if it is empty it is wrapped with WithLayout.
+func UnmarshalWKB(data []byte) (Geometry, error) {
+	g, srid, err := unmarshalWKB(data)
+	if err != nil {
+		return nil, err
+	}
+	if srid != 0 {
+		return WithSRID(srid, g), nil
+	}
+	return g, nil
+}
+
+// unmarshalWKB decodes a geometry from Well Known Binary or Extended Well Known Binary.
+// If any types are provided, the geometry must have one of them.
+// The returned SRID is 0 if the data does not have one.
+func unmarshalWKB(data []byte, types ...uint32) (Geometry, int, error) {
+	r := &wkbReader{data: data}
+	g, err := r.geometry(types...)
+	if err != nil {
+		return nil, 0, err
+	}
+	if r.pos != len(r.data) {
+		return nil, 0, fmt.Errorf("wkb: %d trailing bytes at offset %d", len(r.data)-r.pos, r.pos)
+	}
+	return g, r.srid, nil
+}
+
+// isWKB returns true if data looks like Well Known Binary.
+// Well Known Text always starts with a letter, while
+// Well Known Binary always starts with a byte order mark of 0 or 1.
+func isWKB(data []byte) bool {
+	return len(data) >= 5 && (data[0] == wkbXDR || data[0] == wkbNDR)
+}
+
+// isHexWKB returns true if s looks like hex-encoded Well Known Binary.
+func isHexWKB(s string) bool {
+	if len(s) < 10 || len(s)%2 != 0 {
+		return false
+	}
+	if s[0] != '0' || (s[1] != '0' && s[1] != '1') {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= '0' && c <= '9', c >= 'a' && c <= 'f', c >= 'A' && c <= 'F':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// decodeHexWKB decodes hex-encoded Well Known Binary.
+func decodeHexWKB(s string) ([]byte, error) {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("wkb: %s", err)
+	}
+	return data, nil
+}
+
+// wkbReader decodes Well Known Binary.
+type wkbReader struct {
+	data   []byte
+	pos    int
+	order  binary.ByteOrder
+	srid   int    // SRID of the outermost geometry
+	layout Layout // layout of the current geometry
+}
+
+// need returns an error if there are fewer than n bytes left.
+func (r *wkbReader) need(n int) error {
+	if len(r.data)-r.pos < n {
+		return fmt.Errorf("wkb: unexpected end of input at offset %d", len(r.data))
+	}
+	return nil
+}
+
+// byteOrder reads a byte order mark.
+func (r *wkbReader) byteOrder() error {
+	if err := r.need(1); err != nil {
+		return err
+	}
+	switch r.data[r.pos] {
+	case wkbXDR:
+		r.order = binary.BigEndian
+	case wkbNDR:
+		r.order = binary.LittleEndian
+	default:
+		return fmt.Errorf("wkb: invalid byte order %d at offset %d", r.data[r.pos], r.pos)
+	}
+	r.pos++
+	return nil
+}
+
+// uint32 reads an unsigned 32 bit integer.
+func (r *wkbReader) uint32() (uint32, error) {
+	if err := r.need(4); err != nil {
+		return 0, err
+	}
+	v := r.order.Uint32(r.data[r.pos:])
+	r.pos += 4
+	return v, nil
+}
+
+// count reads an element count and checks that at least
+// size bytes per element are left in the input.
+func (r *wkbReader) count(size int) (int, error) {
+	n, err := r.uint32()
+	if err != nil {
+		return 0, err
+	}
+	if uint64(n)*uint64(size) > uint64(len(r.data)-r.pos) {
+		return 0, fmt.Errorf("wkb: count %d at offset %d exceeds input size", n, r.pos-4)
+	}
+	return int(n), nil
+}
+
+// float64 reads a double.
+func (r *wkbReader) float64() (float64, error) {
+	if err := r.need(8); err != nil {
+		return 0, err
+	}
+	v := math.Float64frombits(r.order.Uint64(r.data[r.pos:]))
+	r.pos += 8
+	return v, nil
+}
+
+// coord reads a single coordinate.
+func (r *wkbReader) coord() ([4]float64, error) {
+	c := [4]float64{}
+	for _, i := range wkbOrdinates(r.layout) {
+		f, err := r.float64()
+		if err != nil {
+			return c, err
+		}
+		c[i] = f
+	}
+	return c, nil
+}
+
+// wkbOrdinates returns the indexes of the ordinates that are encoded for a layout.
+func wkbOrdinates(layout Layout) []int {
+	switch layout {
+	case XYZ:
+		return []int{0, 1, 2}
+	case XYM:
+		return []int{0, 1, 3}
+	case XYZM:
+		return []int{0, 1, 2, 3}
+	}
+	return []int{0, 1}
+}
+
+// coords reads a list of coordinates preceded by its length.
+func (r *wkbReader) coords() ([][4]float64, error) {
+	n, err := r.count(8 * r.layout.Dims())
+	if err != nil {
+		return nil, err
+	}
+	pts := make([][4]float64, n)
+	for i := range pts {
+		if pts[i], err = r.coord(); err != nil {
+			return nil, err
+		}
+	}
+	return pts, nil
+}
+
+// rings reads a list of coordinate lists preceded by its length.
+func (r *wkbReader) rings() ([][][4]float64, error) {
+	n, err := r.count(4)
+	if err != nil {
+		return nil, err
+	}
+	rings := make([][][4]float64, n)
+	for i := range rings {
+		if rings[i], err = r.coords(); err != nil {
+			return nil, err
+		}
+	}
+	return rings, nil
+}
+
+// members reads the members of a multi geometry,
+// each of which must have the given type and the layout of the multi geometry.
+// The members of a collection, whose type is 0, may have any type and layout.
+func (r *wkbReader) members(typ uint32) ([]Geometry, error) {
+	n, err := r.count(5)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		layout = r.layout
+		geoms  = make([]Geometry, n)
+	)
+	for i := range geoms {
+		start := r.pos
+		if typ == 0 {
+			geoms[i], err = r.geometry()
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if geoms[i], err = r.geometry(typ); err != nil {
+			return nil, err
+		}
+		if r.layout != layout {
+			return nil, fmt.Errorf("wkb: member with layout %s in a geometry with layout %s at offset %d", r.layout, layout, start)
+		}
+	}
+	return geoms, nil
+}
+
+// geometry reads a geometry.
+// If any types are provided, the geometry must have one of them.
+func (r *wkbReader) geometry(types ...uint32) (Geometry, error) {
+	start := r.pos
+	if err := r.byteOrder(); err != nil {
+		return nil, err
+	}
+	typ, err := r.uint32()
+	if err != nil {
+		return nil, err
+	}
+	if typ&ewkbSRID != 0 {
+		srid, err := r.uint32()
+		if err != nil {
+			return nil, err
+		}
+		if start == 0 {
+			r.srid = int(int32(srid))
+		}
+		typ &^= ewkbSRID
+	}
+	z, m := typ&ewkbZ != 0, typ&ewkbM != 0
+	typ &^= ewkbZ | ewkbM
+	switch typ / wkbZ {
+	case 1:
+		z, typ = true, typ-wkbZ
+	case 2:
+		m, typ = true, typ-wkbM
+	case 3:
+		z, m, typ = true, true, typ-wkbZ-wkbM
+	}
+	r.layout = layoutFor(z, m)
+	if len(types) > 0 && !wkbTypeIn(typ, types) {
+		return nil, fmt.Errorf("wkb: unexpected geometry type %d at offset %d", typ, start)
+	}
+	layout := r.layout
+	g, err := r.body(typ, start)
+	if err != nil {
+		return nil, err
+	}
+	return withDecodedLayout(layout, g), nil
+}
+
+// body reads the body of a geometry with the given type,
+// whose header started at offset start.
+func (r *wkbReader) body(typ uint32, start int) (Geometry, error) {
+	switch typ {
+	case wkbPoint:
+		c, err := r.coord()
+		pt := Point(c)
+		return &pt, err
+	case wkbLine:
+		pts, err := r.coords()
+		l := Line(pts)
+		return &l, err
+	case wkbPolygon:
+		rings, err := r.rings()
+		p := Polygon(rings)
+		return &p, err
+	case wkbMultiPoint:
+		geoms, err := r.members(wkbPoint)
+		if err != nil {
+			return nil, err
+		}
+		mp := make(MultiPoint, len(geoms))
+		for i, g := range geoms {
+			mp[i] = *withoutLayout(g).(*Point)
+		}
+		return &mp, nil
+	case wkbMultiLine:
+		geoms, err := r.members(wkbLine)
+		if err != nil {
+			return nil, err
+		}
+		ml := make(MultiLine, len(geoms))
+		for i, g := range geoms {
+			ml[i] = *withoutLayout(g).(*Line)
+		}
+		return &ml, nil
+	case wkbMultiPolygon:
+		geoms, err := r.members(wkbPolygon)
+		if err != nil {
+			return nil, err
+		}
+		mp := make(MultiPolygon, len(geoms))
+		for i, g := range geoms {
+			mp[i] = *withoutLayout(g).(*Polygon)
+		}
+		return &mp, nil
+	case wkbGeometryCollection:
+		geoms, err := r.members(0)
+		gc := GeometryCollection(geoms)
+		return &gc, err
+	default:
+		return nil, fmt.Errorf("wkb: unsupported geometry type %d at offset %d", typ, start)
+	}
+}
+
+// wkbTypeIn returns true if typ is one of types.
+func wkbTypeIn(typ uint32, types []uint32) bool {
+	for _, t := range types {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// wkbWriter encodes Well Known Binary.
+type wkbWriter struct {
+	buf      []byte
+	order    binary.ByteOrder
+	srid     int    // written with the next header, if nonzero
+	layout   Layout // layout of the coordinates
+	forced   bool   // the layout is set by WithLayout rather than inferred
+	extended bool   // use PostGIS flags rather than ISO type codes
+}
+
+// newWKBWriter creates a writer that uses the given byte order.
+// A nil order means little endian.
+func newWKBWriter(order binary.ByteOrder) *wkbWriter {
+	if order == nil {
+		order = binary.LittleEndian
+	}
+	return &wkbWriter{order: order}
+}
+
+// header writes a byte order mark and a geometry type.
+func (w *wkbWriter) header(typ uint32) {
+	if w.order == binary.BigEndian {
+		w.buf = append(w.buf, wkbXDR)
+	} else {
+		w.buf = append(w.buf, wkbNDR)
+	}
+	if !w.extended {
+		if w.layout.HasZ() {
+			typ += wkbZ
+		}
+		if w.layout.HasM() {
+			typ += wkbM
+		}
+		w.uint32(typ)
+		return
+	}
+	if w.layout.HasZ() {
+		typ |= ewkbZ
+	}
+	if w.layout.HasM() {
+		typ |= ewkbM
+	}
+	if w.srid == 0 {
+		w.uint32(typ)
+		return
+	}
+	w.uint32(typ | ewkbSRID)
+	w.uint32(uint32(int32(w.srid)))
+	w.srid = 0
+}
+
+// uint32 writes an unsigned 32 bit integer.
+func (w *wkbWriter) uint32(v uint32) {
+	var b [4]byte
+	w.order.PutUint32(b[:], v)
+	w.buf = append(w.buf, b[:]...)
+}
+
+// float64 writes a double.
+func (w *wkbWriter) float64(v float64) {
+	var b [8]byte
+	w.order.PutUint64(b[:], math.Float64bits(unmarked(v)))
+	w.buf = append(w.buf, b[:]...)
+}
+
+// coord writes a single coordinate.
+func (w *wkbWriter) coord(c [4]float64) {
+	for _, i := range wkbOrdinates(w.layout) {
+		w.float64(c[i])
+	}
+}
+
+// coords writes a list of coordinates preceded by its length.
+func (w *wkbWriter) coords(pts [][4]float64) {
+	w.uint32(uint32(len(pts)))
+	for _, c := range pts {
+		w.coord(c)
+	}
+}
+
+// rings writes a list of coordinate lists preceded by its length.
+func (w *wkbWriter) rings(rings [][][4]float64) {
+	w.uint32(uint32(len(rings)))
+	for _, ring := range rings {
+		w.coords(ring)
+	}
+}
+
+// geometry writes a geometry.
+func (w *wkbWriter) geometry(g Geometry) error {
+	switch v := g.(type) {
+	case *Point:
+		w.header(wkbPoint)
+		w.coord(*v)
+	case *Line:
+		w.header(wkbLine)
+		w.coords(*v)
+	case *Polygon:
+		w.header(wkbPolygon)
+		w.rings(*v)
+	case *MultiPoint:
+		w.header(wkbMultiPoint)
+		w.uint32(uint32(len(*v)))
+		for _, c := range *v {
+			w.header(wkbPoint)
+			w.coord(c)
+		}
+	case *MultiLine:
+		w.header(wkbMultiLine)
+		w.uint32(uint32(len(*v)))
+		for _, line := range *v {
+			w.header(wkbLine)
+			w.coords(line)
+		}
+	case *MultiPolygon:
+		w.header(wkbMultiPolygon)
+		w.uint32(uint32(len(*v)))
+		for _, poly := range *v {
+			w.header(wkbPolygon)
+			w.rings(poly)
+		}
+	case *GeometryCollection:
+		w.header(wkbGeometryCollection)
+		w.uint32(uint32(len(*v)))
+		// Members have their own layouts, unless it is set by WithLayout.
+		layout := w.layout
+		for _, member := range *v {
+			if !w.forced {
+				w.layout = LayoutOf(member)
+			}
+			if err := w.geometry(member); err != nil {
+				return err
+			}
+		}
+		w.layout = layout
+	case *boundingBox:
+		return w.geometry(v.Geometry)
+	case *sridGeometry:
+		// The SRID of the outermost geometry is written with its header, see marshalWKB.
+		return w.geometry(v.Geometry)
+	case *layoutGeometry:
+		defer func(layout Layout, forced bool) { w.layout, w.forced = layout, forced }(w.layout, w.forced)
+		w.layout, w.forced = v.Layout, true
+		return w.geometry(v.Geometry)
+	default:
+		return fmt.Errorf("wkb: cannot encode %T", g)
+	}
+	return nil
+}
+
+// marshalWKB encodes a geometry as Well Known Binary,
+// or as Extended Well Known Binary if it has an SRID, see SRIDOf.
+func marshalWKB(g Geometry, order binary.ByteOrder) ([]byte, error) {
+	return marshalEWKB(g, SRIDOf(g), order)
+}
+
+// marshalEWKB encodes a geometry as Extended Well Known Binary.
+// If srid is 0 this is the same as Well Known Binary.
+func marshalEWKB(g Geometry, srid int, order binary.ByteOrder) ([]byte, error) {
+	w := newWKBWriter(order)
+	w.srid, w.layout, w.extended = srid, LayoutOf(g), srid != 0
+	if err := w.geometry(g); err != nil {
+		return nil, err
+	}
+	return w.buf, nil
+}
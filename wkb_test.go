@@ -0,0 +1,195 @@
+package geo
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// wkbMarshaler is implemented by geometries that can be encoded as Well Known Binary.
+type wkbMarshaler interface {
+	Geometry
+	MarshalWKB(order binary.ByteOrder) ([]byte, error)
+}
+
+func TestWKBRoundTrip(t *testing.T) {
+	for i, testcase := range []struct {
+		Input    wkbMarshaler
+		Instance Geometry
+	}{
+		{
+			Input:    &Point{1.5, -2.25},
+			Instance: &Point{},
+		},
+		{
+			Input:    &Line{{0, 0}, {1, 1}, {2, 0}},
+			Instance: &Line{},
+		},
+		{
+			Input:    &Line{},
+			Instance: &Line{},
+		},
+		{
+			Input:    &Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 0}}, {{1, 1}, {2, 1}, {2, 2}, {1, 1}}},
+			Instance: &Polygon{},
+		},
+		{
+			Input:    &MultiPoint{{0, 0}, {1, 1}},
+			Instance: &MultiPoint{},
+		},
+		{
+			Input:    &MultiLine{{{0, 0}, {1, 1}}, {{2, 2}, {3, 3}}},
+			Instance: &MultiLine{},
+		},
+		{
+			Input:    &MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}, {{{5, 5}, {6, 5}, {6, 6}, {5, 5}}}},
+			Instance: &MultiPolygon{},
+		},
+		{
+			Input:    &GeometryCollection{&Point{0, 0}, &GeometryCollection{&Line{{0, 0}, {1, 1}}}},
+			Instance: &GeometryCollection{},
+		},
+		{
+			Input:    &Point{1, 2, 3},
+			Instance: &Point{},
+		},
+		{
+			Input:    &Polygon{{{0, 0, 1}, {4, 0, 1}, {4, 4, 1}, {0, 0, 1}}},
+			Instance: &Polygon{},
+		},
+		{
+			Input:    &GeometryCollection{&Point{0, 0, 1}, &Line{{0, 0}, {1, 1}}},
+			Instance: &GeometryCollection{},
+		},
+	} {
+		for _, order := range []binary.ByteOrder{binary.BigEndian, binary.LittleEndian} {
+			data, err := testcase.Input.MarshalWKB(order)
+			if err != nil {
+				t.Fatalf("(case %d) %s", i, err)
+			}
+			if err := testcase.Instance.(wkbUnmarshaler).UnmarshalWKB(data); err != nil {
+				t.Fatalf("(case %d) %s", i, err)
+			}
+			if !testcase.Input.Equal(testcase.Instance) {
+				t.Fatalf("(case %d) expected %s, got %s", i, testcase.Input, testcase.Instance)
+			}
+			g, err := UnmarshalWKB(data)
+			if err != nil {
+				t.Fatalf("(case %d) %s", i, err)
+			}
+			if !testcase.Input.Equal(g) {
+				t.Fatalf("(case %d) expected %s, got %s", i, testcase.Input, g)
+			}
+		}
+	}
+}
+
+func TestWKBMarshal(t *testing.T) {
+	for i, testcase := range []struct {
+		Input    wkbMarshaler
+		Order    binary.ByteOrder
+		Expected string
+	}{
+		{
+			Input:    &Point{1, 2},
+			Order:    binary.LittleEndian,
+			Expected: "0101000000000000000000f03f0000000000000040",
+		},
+		{
+			Input:    &Point{1, 2},
+			Order:    binary.BigEndian,
+			Expected: "00000000013ff00000000000004000000000000000",
+		},
+		{
+			Input:    &Line{{1, 2}},
+			Order:    binary.LittleEndian,
+			Expected: "010200000001000000000000000000f03f0000000000000040",
+		},
+		{
+			Input:    &Point{1, 2, 3},
+			Order:    binary.LittleEndian,
+			Expected: "01e9030000000000000000f03f00000000000000400000000000000840",
+		},
+	} {
+		got, err := testcase.Input.MarshalWKB(testcase.Order)
+		if err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if expected := testcase.Expected; expected != hex.EncodeToString(got) {
+			t.Fatalf("(case %d) expected %s, got %x", i, expected, got)
+		}
+	}
+
+	// Fail
+	gc := &GeometryCollection{&Circle{Radius: 1}}
+	if _, err := gc.MarshalWKB(binary.LittleEndian); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestWKBUnmarshalFail(t *testing.T) {
+	for i, input := range []string{
+		"",
+		"02",                         // bad byte order
+		"0101000000000000000000f03f", // truncated point
+		"0109000000000000000000f03f0000000000000040",   // unknown type
+		"0101000000000000000000f03f000000000000004000", // trailing bytes
+		"0102000000ffffffff",                           // huge count
+		"010400000001000000010200000000000000",         // multipoint containing a line
+	} {
+		data, _ := hex.DecodeString(input)
+		if _, err := UnmarshalWKB(data); err == nil {
+			t.Fatalf("(case %d) expected error, got nil", i)
+		}
+	}
+
+	// Wrong type
+	data, _ := hex.DecodeString("0101000000000000000000f03f0000000000000040")
+	if err := (&Line{}).UnmarshalWKB(data); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestWKBScan(t *testing.T) {
+	var (
+		pointHex = "0101000000000000000000f03f0000000000000040"
+		gcHex    = "0107000000010000000101000000000000000000f03f0000000000000040"
+	)
+	pointWKB, _ := hex.DecodeString(pointHex)
+
+	// Pass
+	for i, testcase := range []struct {
+		Input    interface{}
+		Instance Geometry
+		Expected Geometry
+	}{
+		{Input: pointWKB, Instance: &Point{}, Expected: &Point{1, 2}},
+		{Input: pointHex, Instance: &Point{}, Expected: &Point{1, 2}},
+		{Input: strings.ToUpper(pointHex), Instance: &Point{}, Expected: &Point{1, 2}},
+		{Input: []byte(pointHex), Instance: &Point{}, Expected: &Point{1, 2}},
+		{Input: pointWKB, Instance: &Feature{}, Expected: &Feature{Geometry: &Point{1, 2}}},
+		{Input: pointHex, Instance: &Feature{}, Expected: &Feature{Geometry: &Point{1, 2}}},
+		{Input: gcHex, Instance: &FeatureCollection{}, Expected: &FeatureCollection{{Geometry: &Point{1, 2}}}},
+		{Input: gcHex, Instance: &GeometryCollection{}, Expected: &GeometryCollection{&Point{1, 2}}},
+		{Input: "0101000080000000000000f03f00000000000000400000000000000840", Instance: &Point{}, Expected: &Point{1, 2, 3}},
+	} {
+		if err := testcase.Instance.Scan(testcase.Input); err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if !testcase.Expected.Equal(testcase.Instance) {
+			t.Fatalf("(case %d) expected %s, got %s", i, testcase.Expected, testcase.Instance)
+		}
+	}
+
+	// Fail
+	scanTestcases{
+		{Input: pointHex, Instance: &Circle{}},
+		{Input: pointWKB, Instance: &Line{}},
+		{Input: pointWKB[:10], Instance: &Point{}},
+	}.fail(t)
+
+	if g, err := ScanGeometry(pointHex); err != nil || !g.Equal(&Point{1, 2}) {
+		t.Fatalf("expected POINT(1 2), got %v (%v)", g, err)
+	}
+}
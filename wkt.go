@@ -0,0 +1,564 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Well Known Text geometry tags.
+const (
+	circleWKTTag             = "CIRCULARSTRING"
+	geometryCollectionWKTTag = "GEOMETRYCOLLECTION"
+	lineWKTTag               = "LINESTRING"
+	multiLineWKTTag          = "MULTILINESTRING"
+	multiPointWKTTag         = "MULTIPOINT"
+	multiPolygonWKTTag       = "MULTIPOLYGON"
+	pointWKTTag              = "POINT"
+	polygonWKTTag            = "POLYGON"
+
+	emptyWKTTag = "EMPTY"
+	sridWKTTag  = "SRID"
+	zWKTTag     = "Z"
+	mWKTTag     = "M"
+	zmWKTTag    = "ZM"
+)
+
+// wktDimensions maps Well Known Text dimension markers to layouts.
+var wktDimensions = map[string]Layout{
+	zWKTTag:  XYZ,
+	mWKTTag:  XYM,
+	zmWKTTag: XYZM,
+}
+
+// isWKTTag returns true if tag names a geometry type.
+func isWKTTag(tag string) bool {
+	switch tag {
+	case circleWKTTag, geometryCollectionWKTTag, lineWKTTag, multiLineWKTTag,
+		multiPointWKTTag, multiPolygonWKTTag, pointWKTTag, polygonWKTTag:
+		return true
+	}
+	return false
+}
+
+// splitWKTTag splits a tag with an attached dimension marker,
+// e.g. "POINTM" as written by PostGIS, into "POINT" and "M".
+func splitWKTTag(tag string) (string, string) {
+	tag = strings.ToUpper(tag)
+	for _, dim := range []string{zmWKTTag, zWKTTag, mWKTTag} {
+		if base := strings.TrimSuffix(tag, dim); base != tag && isWKTTag(base) {
+			return base, dim
+		}
+	}
+	return tag, ""
+}
+
+// WKTError describes a syntax error in Well Known Text.
+type WKTError struct {
+	// Offset is the byte offset in the input where the error was found.
+	Offset int
+	Msg    string
+}
+
+// Error returns a description of the error.
+func (e *WKTError) Error() string {
+	return fmt.Sprintf("wkt: %s at offset %d", e.Msg, e.Offset)
+}
+
+// wktTokenKind enumerates the kinds of tokens in Well Known Text.
+type wktTokenKind int
+
+// Token kinds.
+const (
+	wktEOF wktTokenKind = iota
+	wktWord
+	wktNumber
+	wktLeftParen
+	wktRightParen
+	wktComma
+	wktEquals
+	wktSemicolon
+)
+
+// wktToken is a single token of Well Known Text.
+type wktToken struct {
+	Kind   wktTokenKind
+	Text   string
+	Offset int
+}
+
+// String returns a description of the token for error messages.
+func (tok wktToken) String() string {
+	if tok.Kind == wktEOF {
+		return "end of input"
+	}
+	return strconv.Quote(tok.Text)
+}
+
+// wktLexer splits Well Known Text into tokens.
+type wktLexer struct {
+	input string
+	pos   int
+}
+
+// next returns the next token in the input.
+func (lex *wktLexer) next() (wktToken, error) {
+	for lex.pos < len(lex.input) && isWKTSpace(lex.input[lex.pos]) {
+		lex.pos++
+	}
+	start := lex.pos
+	if start == len(lex.input) {
+		return wktToken{Kind: wktEOF, Offset: start}, nil
+	}
+	switch c := lex.input[start]; {
+	case c == '(':
+		lex.pos++
+		return wktToken{Kind: wktLeftParen, Text: "(", Offset: start}, nil
+	case c == ')':
+		lex.pos++
+		return wktToken{Kind: wktRightParen, Text: ")", Offset: start}, nil
+	case c == ',':
+		lex.pos++
+		return wktToken{Kind: wktComma, Text: ",", Offset: start}, nil
+	case c == '=':
+		lex.pos++
+		return wktToken{Kind: wktEquals, Text: "=", Offset: start}, nil
+	case c == ';':
+		lex.pos++
+		return wktToken{Kind: wktSemicolon, Text: ";", Offset: start}, nil
+	case isWKTLetter(c):
+		for lex.pos < len(lex.input) && isWKTLetter(lex.input[lex.pos]) {
+			lex.pos++
+		}
+		return wktToken{Kind: wktWord, Text: lex.input[start:lex.pos], Offset: start}, nil
+	case isWKTNumberStart(c):
+		for lex.pos < len(lex.input) && isWKTNumberPart(lex.input[lex.pos]) {
+			lex.pos++
+		}
+		return wktToken{Kind: wktNumber, Text: lex.input[start:lex.pos], Offset: start}, nil
+	default:
+		return wktToken{}, &WKTError{Offset: start, Msg: fmt.Sprintf("unexpected character %q", c)}
+	}
+}
+
+func isWKTSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isWKTLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isWKTNumberStart(c byte) bool {
+	return (c >= '0' && c <= '9') || c == '-' || c == '+' || c == '.'
+}
+
+func isWKTNumberPart(c byte) bool {
+	return isWKTNumberStart(c) || c == 'e' || c == 'E'
+}
+
+// wktParser is a recursive descent parser for Well Known Text.
+type wktParser struct {
+	lex    wktLexer
+	tok    wktToken // the current token
+	layout Layout   // layout of the current geometry
+	dims   int      // ordinates per coordinate in the current geometry, 0 if not known yet
+}
+
+// newWKTParser creates a parser and reads the first token.
+func newWKTParser(s string) (*wktParser, error) {
+	p := &wktParser{lex: wktLexer{input: s}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// advance moves to the next token.
+func (p *wktParser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+// errorf returns a WKTError at the offset of the current token.
+func (p *wktParser) errorf(format string, args ...interface{}) error {
+	return &WKTError{Offset: p.tok.Offset, Msg: fmt.Sprintf(format, args...)}
+}
+
+// expect consumes a token of the given kind or returns an error.
+func (p *wktParser) expect(kind wktTokenKind, what string) error {
+	if p.tok.Kind != kind {
+		return p.errorf("expected %s, got %s", what, p.tok)
+	}
+	return p.advance()
+}
+
+// isWord returns true if the current token is the given keyword.
+func (p *wktParser) isWord(word string) bool {
+	return p.tok.Kind == wktWord && strings.EqualFold(p.tok.Text, word)
+}
+
+// empty consumes the EMPTY keyword if it is the current token.
+func (p *wktParser) empty() (bool, error) {
+	if !p.isWord(emptyWKTTag) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+// end returns an error if there is any input left.
+func (p *wktParser) end() error {
+	if p.tok.Kind != wktEOF {
+		return p.errorf("unexpected %s after geometry", p.tok)
+	}
+	return nil
+}
+
+// geometry parses a tagged geometry.
+func (p *wktParser) geometry() (Geometry, error) {
+	if p.tok.Kind != wktWord {
+		return nil, p.errorf("expected geometry type, got %s", p.tok)
+	}
+	tag, dim := splitWKTTag(p.tok.Text)
+	offset := p.tok.Offset
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if _, ok := wktDimensions[strings.ToUpper(p.tok.Text)]; ok && dim == "" && p.tok.Kind == wktWord {
+		dim = strings.ToUpper(p.tok.Text)
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	// Members of a collection inherit its dimension marker.
+	defer func(layout Layout, dims int) { p.layout, p.dims = layout, dims }(p.layout, p.dims)
+	if dim != "" {
+		p.layout = wktDimensions[dim]
+		p.dims = p.layout.Dims()
+	}
+	g, err := p.body(tag, offset)
+	if err != nil || p.dims == 0 {
+		// Without a marker or coordinates the layout is not known.
+		return g, err
+	}
+	return withDecodedLayout(p.layout, g), nil
+}
+
+// body parses the body of a geometry with the given tag.
+func (p *wktParser) body(tag string, offset int) (Geometry, error) {
+	switch tag {
+	case pointWKTTag:
+		pt, err := p.point()
+		return &pt, err
+	case lineWKTTag:
+		pts, err := p.points()
+		l := Line(pts)
+		return &l, err
+	case polygonWKTTag:
+		rings, err := p.rings()
+		poly := Polygon(rings)
+		return &poly, err
+	case multiPointWKTTag:
+		pts, err := p.multiPoint()
+		mp := MultiPoint(pts)
+		return &mp, err
+	case multiLineWKTTag:
+		lines, err := p.rings()
+		ml := MultiLine(lines)
+		return &ml, err
+	case multiPolygonWKTTag:
+		polys, err := p.multiPolygon()
+		mp := MultiPolygon(polys)
+		return &mp, err
+	case geometryCollectionWKTTag:
+		geoms, err := p.geometryCollection()
+		gc := GeometryCollection(geoms)
+		return &gc, err
+	case circleWKTTag:
+		return p.circle(offset)
+	default:
+		return nil, &WKTError{Offset: offset, Msg: fmt.Sprintf("unrecognized geometry type %q", tag)}
+	}
+}
+
+// coord parses a single coordinate, e.g. "1 2" or "1 2 3".
+// Every coordinate in a geometry must have the same number of ordinates.
+// Without a dimension marker three ordinates are XYZ and four are XYZM.
+func (p *wktParser) coord() ([4]float64, error) {
+	c, n := [4]float64{}, 0
+	for ; p.tok.Kind == wktNumber; n++ {
+		if n == len(c) || (p.dims != 0 && n == p.dims) {
+			return c, p.errorf("too many ordinates in coordinate")
+		}
+		f, err := strconv.ParseFloat(p.tok.Text, 64)
+		if err != nil {
+			return c, p.errorf("malformed number %s", p.tok)
+		}
+		c[n] = f
+		if err := p.advance(); err != nil {
+			return c, err
+		}
+	}
+	if n < 2 || (p.dims != 0 && n != p.dims) {
+		return c, p.errorf("expected number, got %s", p.tok)
+	}
+	if p.dims == 0 {
+		p.dims, p.layout = n, layoutFor(n > 2, n > 3)
+	}
+	if p.layout == XYM {
+		c[2], c[3] = 0, c[2]
+	}
+	return c, nil
+}
+
+// coords parses a comma separated list of coordinates.
+func (p *wktParser) coords() ([][4]float64, error) {
+	pts := [][4]float64{}
+	for {
+		c, err := p.coord()
+		if err != nil {
+			return nil, err
+		}
+		pts = append(pts, c)
+		if p.tok.Kind != wktComma {
+			return pts, nil
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// point parses the body of a POINT, e.g. "(1 2)" or "EMPTY".
+func (p *wktParser) point() (Point, error) {
+	if empty, err := p.empty(); empty || err != nil {
+		return Point{math.NaN(), math.NaN()}, err
+	}
+	if err := p.expect(wktLeftParen, "'('"); err != nil {
+		return Point{}, err
+	}
+	c, err := p.coord()
+	if err != nil {
+		return Point{}, err
+	}
+	return Point(c), p.expect(wktRightParen, "')'")
+}
+
+// points parses a parenthesized list of coordinates, e.g. "(1 2, 3 4)" or "EMPTY".
+func (p *wktParser) points() ([][4]float64, error) {
+	if empty, err := p.empty(); empty || err != nil {
+		return [][4]float64{}, err
+	}
+	if err := p.expect(wktLeftParen, "'('"); err != nil {
+		return nil, err
+	}
+	pts, err := p.coords()
+	if err != nil {
+		return nil, err
+	}
+	return pts, p.expect(wktRightParen, "')'")
+}
+
+// rings parses a parenthesized list of coordinate lists,
+// e.g. "((1 2, 3 4), (5 6, 7 8))" or "EMPTY".
+func (p *wktParser) rings() ([][][4]float64, error) {
+	if empty, err := p.empty(); empty || err != nil {
+		return [][][4]float64{}, err
+	}
+	if err := p.expect(wktLeftParen, "'('"); err != nil {
+		return nil, err
+	}
+	rings := [][][4]float64{}
+	for {
+		ring, err := p.points()
+		if err != nil {
+			return nil, err
+		}
+		rings = append(rings, ring)
+		if p.tok.Kind != wktComma {
+			break
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	return rings, p.expect(wktRightParen, "')'")
+}
+
+// multiPoint parses the body of a MULTIPOINT.
+// Both "(1 2, 3 4)" and "((1 2), (3 4))" are accepted.
+func (p *wktParser) multiPoint() ([][4]float64, error) {
+	if empty, err := p.empty(); empty || err != nil {
+		return [][4]float64{}, err
+	}
+	if err := p.expect(wktLeftParen, "'('"); err != nil {
+		return nil, err
+	}
+	pts := [][4]float64{}
+	for {
+		var (
+			c   [4]float64
+			err error
+		)
+		if p.tok.Kind == wktLeftParen {
+			var pt Point
+			pt, err = p.point()
+			c = pt
+		} else {
+			c, err = p.coord()
+		}
+		if err != nil {
+			return nil, err
+		}
+		pts = append(pts, c)
+		if p.tok.Kind != wktComma {
+			break
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	return pts, p.expect(wktRightParen, "')'")
+}
+
+// multiPolygon parses the body of a MULTIPOLYGON.
+func (p *wktParser) multiPolygon() ([][][][4]float64, error) {
+	if empty, err := p.empty(); empty || err != nil {
+		return [][][][4]float64{}, err
+	}
+	if err := p.expect(wktLeftParen, "'('"); err != nil {
+		return nil, err
+	}
+	polys := [][][][4]float64{}
+	for {
+		poly, err := p.rings()
+		if err != nil {
+			return nil, err
+		}
+		polys = append(polys, poly)
+		if p.tok.Kind != wktComma {
+			break
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	return polys, p.expect(wktRightParen, "')'")
+}
+
+// geometryCollection parses the body of a GEOMETRYCOLLECTION.
+func (p *wktParser) geometryCollection() ([]Geometry, error) {
+	if empty, err := p.empty(); empty || err != nil {
+		return []Geometry{}, err
+	}
+	if err := p.expect(wktLeftParen, "'('"); err != nil {
+		return nil, err
+	}
+	geoms := []Geometry{}
+	for {
+		g, err := p.geometry()
+		if err != nil {
+			return nil, err
+		}
+		geoms = append(geoms, g)
+		if p.tok.Kind != wktComma {
+			break
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	return geoms, p.expect(wktRightParen, "')'")
+}
+
+// circle parses the body of a CIRCULARSTRING.
+// Points 0 and 2 should be on opposite sides of the circle,
+// so we can calculate the radius as 1/2 the distance between them
+// and the center as the midpoint.
+func (p *wktParser) circle(offset int) (*Circle, error) {
+	points, err := p.points()
+	if err != nil {
+		return nil, err
+	}
+	if len(points) < 3 {
+		return nil, &WKTError{Offset: offset, Msg: "circle needs at least 3 points"}
+	}
+	var (
+		dx = points[2][0] - points[0][0]
+		dy = points[2][1] - points[0][1]
+	)
+	return &Circle{
+		Coordinates: Point{points[0][0] + (dx / 2), points[0][1] + (dy / 2)},
+		Radius:      Point(points[0]).DistanceFrom(points[2]) / 2,
+	}, nil
+}
+
+// srid parses the optional "SRID=<srid>;" prefix of Extended Well Known Text.
+// It returns 0 if there is no prefix.
+func (p *wktParser) srid() (int, error) {
+	if !p.isWord(sridWKTTag) {
+		return 0, nil
+	}
+	if err := p.advance(); err != nil {
+		return 0, err
+	}
+	if err := p.expect(wktEquals, "'='"); err != nil {
+		return 0, err
+	}
+	if p.tok.Kind != wktNumber {
+		return 0, p.errorf("expected SRID, got %s", p.tok)
+	}
+	srid, err := strconv.ParseInt(p.tok.Text, 10, 32)
+	if err != nil {
+		return 0, p.errorf("malformed SRID %s", p.tok)
+	}
+	if err := p.advance(); err != nil {
+		return 0, err
+	}
+	return int(srid), p.expect(wktSemicolon, "';'")
+}
+
+// parseWKT parses a geometry from Well Known Text or Extended Well Known Text.
+// If any tags are provided, the geometry's type must match one of them.
+// The returned SRID is 0 if the text does not have an SRID prefix.
+func parseWKT(s string, tags ...string) (Geometry, int, error) {
+	p, err := newWKTParser(s)
+	if err != nil {
+		return nil, 0, err
+	}
+	srid, err := p.srid()
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(tags) > 0 && !p.isAnyTag(tags) {
+		return nil, 0, p.errorf("expected %s, got %s", strings.Join(tags, " or "), p.tok)
+	}
+	g, err := p.geometry()
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := p.end(); err != nil {
+		return nil, 0, err
+	}
+	return g, srid, nil
+}
+
+// isAnyTag returns true if the current token is one of the given geometry tags,
+// ignoring any attached dimension marker.
+func (p *wktParser) isAnyTag(tags []string) bool {
+	if p.tok.Kind != wktWord {
+		return false
+	}
+	base, _ := splitWKTTag(p.tok.Text)
+	for _, tag := range tags {
+		if base == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+package geo
+
+import "testing"
+
+func TestScanGeometry(t *testing.T) {
+	// Pass
+	for i, testcase := range []struct {
+		Input    string
+		Expected Geometry
+	}{
+		{
+			Input:    `POINT(1 2)`,
+			Expected: &Point{1, 2},
+		},
+		{
+			Input:    `point ( -1.5   2e3 )`,
+			Expected: &Point{-1.5, 2000},
+		},
+		{
+			Input:    `LineString (0 0,1 1)`,
+			Expected: &Line{{0, 0}, {1, 1}},
+		},
+		{
+			Input:    `LINESTRING EMPTY`,
+			Expected: &Line{},
+		},
+		{
+			Input:    `POLYGON ((0 0, 1 0))`,
+			Expected: &Polygon{{{0, 0}, {1, 0}}},
+		},
+		{
+			Input:    "POLYGON((0 0, 4 0, 4 4, 0 0),\n\t(1 1, 2 1, 2 2, 1 1))",
+			Expected: &Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 0}}, {{1, 1}, {2, 1}, {2, 2}, {1, 1}}},
+		},
+		{
+			Input:    `polygon empty`,
+			Expected: &Polygon{},
+		},
+		{
+			Input:    `MULTIPOINT(0 0, 1 1)`,
+			Expected: &MultiPoint{{0, 0}, {1, 1}},
+		},
+		{
+			Input:    `MULTIPOINT ((0 0), (1 1))`,
+			Expected: &MultiPoint{{0, 0}, {1, 1}},
+		},
+		{
+			Input:    `MULTILINESTRING ((0 0, 1 1), (2 2, 3 3))`,
+			Expected: &MultiLine{{{0, 0}, {1, 1}}, {{2, 2}, {3, 3}}},
+		},
+		{
+			Input:    `MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)), ((5 5, 6 5, 6 6, 5 5), (1 1, 2 2, 3 3)))`,
+			Expected: &MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}, {{{5, 5}, {6, 5}, {6, 6}, {5, 5}}, {{1, 1}, {2, 2}, {3, 3}}}},
+		},
+		{
+			Input:    `MULTIPOLYGON EMPTY`,
+			Expected: &MultiPolygon{},
+		},
+		{
+			Input:    `GEOMETRYCOLLECTION (POINT (0 0), LINESTRING (0 0, 1 1))`,
+			Expected: &GeometryCollection{&Point{0, 0}, &Line{{0, 0}, {1, 1}}},
+		},
+		{
+			Input:    `GEOMETRYCOLLECTION(POINT(0 0), GEOMETRYCOLLECTION(POINT(1 1)))`,
+			Expected: &GeometryCollection{&Point{0, 0}, &GeometryCollection{&Point{1, 1}}},
+		},
+		{
+			Input:    `GEOMETRYCOLLECTION EMPTY`,
+			Expected: &GeometryCollection{},
+		},
+		{
+			Input:    `POINT Z (1 2 3)`,
+			Expected: &Point{1, 2, 3},
+		},
+		{
+			Input:    `POINT(1 2 3)`,
+			Expected: &Point{1, 2, 3},
+		},
+		{
+			Input:    `LINESTRING Z (0 0 1, 1 1 2)`,
+			Expected: &Line{{0, 0, 1}, {1, 1, 2}},
+		},
+		{
+			Input:    `GEOMETRYCOLLECTION (POINT Z (0 0 1), POINT (1 1))`,
+			Expected: &GeometryCollection{&Point{0, 0, 1}, &Point{1, 1}},
+		},
+		{
+			Input:    `CIRCULARSTRING(1 0, 0 1, -1 0, 0 -1, 1 0)`,
+			Expected: &Circle{Radius: 1, Coordinates: Point{0, 0}},
+		},
+	} {
+		g, err := ScanGeometry(testcase.Input)
+		if err != nil {
+			t.Fatalf("(case %d) %s", i, err)
+		}
+		if expected, got := testcase.Expected, g; !expected.Equal(got) {
+			t.Fatalf("(case %d) expected %s, got %s", i, expected, got)
+		}
+	}
+
+	// Fail
+	for i, testcase := range []struct {
+		Input  string
+		Offset int
+	}{
+		{Input: ``, Offset: 0},
+		{Input: `PIKACHU(1 2)`, Offset: 0},
+		{Input: `POINT(1 2`, Offset: 9},
+		{Input: `POINT(1, 2)`, Offset: 7},
+		{Input: `POINT(1 2) POINT(3 4)`, Offset: 11},
+		{Input: `POINT(1 2 3 4 5)`, Offset: 14},
+		{Input: `POINT M (1 2 3 4)`, Offset: 15},
+		{Input: `POINT Z (1 2)`, Offset: 12},
+		{Input: `LINESTRING(0 0, 1 1 1)`, Offset: 20},
+		{Input: `LINESTRING(0 0 0, 1 1)`, Offset: 21},
+		{Input: `LINESTRING(0 0, 1 x)`, Offset: 18},
+		{Input: `LINESTRING(0 0, 1 1.2.3)`, Offset: 18},
+		{Input: `POLYGON((0 0, 1 1)}`, Offset: 18},
+		{Input: `MULTIPOLYGON((0 0, 1 1))`, Offset: 14},
+		{Input: `CIRCULARSTRING(0 0, 1 1)`, Offset: 0},
+	} {
+		_, err := ScanGeometry(testcase.Input)
+		if err == nil {
+			t.Fatalf("(case %d) expected error, got nil", i)
+		}
+		wktErr, ok := err.(*WKTError)
+		if !ok {
+			t.Fatalf("(case %d) expected *WKTError, got %T", i, err)
+		}
+		if expected, got := testcase.Offset, wktErr.Offset; expected != got {
+			t.Fatalf("(case %d) expected offset %d, got %d (%s)", i, expected, got, err)
+		}
+	}
+}
+
+func TestScanWrongType(t *testing.T) {
+	for i, testcase := range []struct {
+		Input    string
+		Instance Geometry
+	}{
+		{Input: `LINESTRING(0 0, 1 1)`, Instance: &Point{}},
+		{Input: `POINT(0 0)`, Instance: &Line{}},
+		{Input: `MULTIPOINT(0 0)`, Instance: &Polygon{}},
+		{Input: `POLYGON((0 0, 1 1))`, Instance: &MultiPolygon{}},
+		{Input: `POINT(0 0)`, Instance: &GeometryCollection{}},
+		{Input: `POINT(0 0)`, Instance: &FeatureCollection{}},
+	} {
+		if err := testcase.Instance.Scan(testcase.Input); err == nil {
+			t.Fatalf("(case %d) expected error, got nil", i)
+		}
+	}
+}
+
+func TestScanCoordinateList(t *testing.T) {
+	const coords = `-114.568826928926 33.5236368809412,-114.568816450573 33.5228264056869,-114.568751159798 33.5200958667454,-114.568773070263 33.5200663151826`
+
+	g, err := ScanGeometry(`LINESTRING(` + coords + `)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &Line{
+		{-114.568826928926, 33.5236368809412},
+		{-114.568816450573, 33.5228264056869},
+		{-114.568751159798, 33.5200958667454},
+		{-114.568773070263, 33.5200663151826},
+	}
+	if !expected.Equal(g) {
+		t.Fatalf("expected %s, got %s", expected, g)
+	}
+
+	// Coordinate lists with unbalanced or missing parentheses,
+	// and a malformed number.
+	for i, testcase := range []struct {
+		Input  string
+		Offset int
+	}{
+		{Input: `LINESTRING(` + coords, Offset: 150},
+		{Input: `LINESTRING ` + coords + `)`, Offset: 11},
+		{Input: `LINESTRING ` + coords, Offset: 11},
+		{Input: `LINESTRING(-114.568826928926 33.5236368809412,-114.568816450573 33.5228264056869,-114.568751159798 33.5200958667454,-114.568773070263 33.520066abcd1826)`, Offset: 143},
+	} {
+		_, err := ScanGeometry(testcase.Input)
+		wktErr, ok := err.(*WKTError)
+		if !ok {
+			t.Fatalf("(case %d) expected *WKTError, got %v", i, err)
+		}
+		if expected, got := testcase.Offset, wktErr.Offset; expected != got {
+			t.Fatalf("(case %d) expected offset %d, got %d (%s)", i, expected, got, err)
+		}
+	}
+}